@@ -0,0 +1,140 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"regexp"
+
+	"github.com/SAP/go-hdb/driver/sqltrace"
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+var reAnonymousBlock = regexp.MustCompile(`(?is)^(?:\s|--[^\n]*\n|/\*.*?\*/)*do\b`)
+
+// isAnonymousBlock returns true, if query is an anonymous SQLScript block (DO BEGIN ... END).
+// Comments preceding the DO keyword are skipped.
+func isAnonymousBlock(query string) bool {
+	return reAnonymousBlock.MatchString(query)
+}
+
+// check if implicitResults implements all required interfaces
+var (
+	_ driver.Rows              = (*implicitResults)(nil)
+	_ driver.RowsNextResultSet = (*implicitResults)(nil)
+)
+
+/*
+implicitResults provides the implicit resultsets of an anonymous block (the results of the select statements
+of the block not assigned to a variable) as rows with one resultset per implicit resultset:
+
+	rows, err := db.Query("do begin select * from t1; select * from t2; end")
+	...
+	for rows.Next() { // rows of t1
+		...
+	}
+	if rows.NextResultSet() {
+		for rows.Next() { // rows of t2
+			...
+		}
+	}
+
+The resultset metadata (columns, column types) of each resultset is provided by the embedded query result
+of the current resultset.
+*/
+type implicitResults struct {
+	*queryResult
+	results []*queryResult
+	idx     int
+}
+
+func (r *implicitResults) HasNextResultSet() bool {
+	return r.idx < len(r.results)-1
+}
+
+func (r *implicitResults) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+	r.idx++
+	r.queryResult = r.results[r.idx]
+	return nil
+}
+
+func (r *implicitResults) Close() error {
+	var err error
+	for _, result := range r.results {
+		if closeErr := result.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// anonymousBlock executes an anonymous block returning its implicit resultsets.
+func (s *stmt) anonymousBlock(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+
+	sqltrace.Tracef("%s %v", s.query, args)
+
+	inArgs, outArgs := splitOutArgs(args)
+
+	s.session.SetWriteLobDone(ctx.Done())
+	fieldValues, tableResults, err := s.session.Call(s.id, s.prmFieldSet, inArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	default:
+	case <-ctx.Done():
+		closeTableResults(s.session, tableResults)
+		return nil, ctx.Err()
+	}
+
+	if err := assignOutArgs(s.prmFieldSet, fieldValues, outArgs, s.session.TimeLocation()); err != nil {
+		closeTableResults(s.session, tableResults)
+		return nil, err
+	}
+
+	if len(tableResults) == 0 {
+		return noResult, nil
+	}
+
+	results := make([]*queryResult, len(tableResults))
+	for i, tableResult := range tableResults {
+		rows, err := newQueryResult(s.session, tableResult.ID(), tableResult.FieldSet(), tableResult.FieldValues(), tableResult.Attrs(), ctxFetchSize(ctx), false, s.connector.DecimalFormat(), s.connector.DecimalConverter(), nil)
+		if err != nil {
+			closeTableResults(s.session, tableResults)
+			return nil, err
+		}
+		results[i] = rows.(*queryResult)
+	}
+	return &implicitResults{queryResult: results[0], results: results}, nil
+}
+
+// closeTableResults releases the open resultsets of table results not returned to the caller.
+func closeTableResults(session *p.Session, tableResults []*p.TableResult) {
+	for _, tableResult := range tableResults {
+		if !tableResult.Attrs().ResultsetClosed() {
+			if err := session.CloseResultsetID(tableResult.ID()); err != nil {
+				sqltrace.Traceln(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestIsAnonymousBlock(t *testing.T) {
+	testData := []struct {
+		query string
+		ok    bool
+	}{
+		{"do begin select 1 from dummy; end", true},
+		{"  DO (IN p INT => ?) BEGIN select :p from dummy; END", true},
+		{"Do\nbegin end", true},
+		{"download", false},
+		{"select 'do' from dummy", false},
+		{"-- setup\ndo begin end", true},
+		{"/* setup\n */ /**/ DO begin end", true},
+		{"-- do\nselect 1 from dummy", false},
+		{"/* do */ select 1 from dummy", false},
+	}
+
+	for _, d := range testData {
+		if ok := isAnonymousBlock(d.query); ok != d.ok {
+			t.Fatalf("query %q: anonymous block %t - expected %t", d.query, ok, d.ok)
+		}
+	}
+}
+
+func TestAnonymousBlockImplicitResults(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("do begin select 1 as a from dummy; select 'x' as b, 2 as c from dummy union all select 'y', 3 from dummy; end")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	testData := []struct {
+		columns   []string
+		typeNames []string
+		numRow    int
+	}{
+		{[]string{"A"}, []string{"INTEGER"}, 1},
+		{[]string{"B", "C"}, []string{"VARCHAR", "INTEGER"}, 2},
+	}
+
+	for i, d := range testData {
+		if i != 0 && !rows.NextResultSet() {
+			t.Fatalf("resultset %d expected", i)
+		}
+
+		columns, err := rows.Columns()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(columns, d.columns) {
+			t.Fatalf("resultset %d: columns %v - expected %v", i, columns, d.columns)
+		}
+
+		columnTypes, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, columnType := range columnTypes {
+			if typeName := columnType.DatabaseTypeName(); typeName != d.typeNames[j] {
+				t.Fatalf("resultset %d column %d: type %s - expected %s", i, j, typeName, d.typeNames[j])
+			}
+		}
+
+		numRow := 0
+		for rows.Next() {
+			numRow++
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if numRow != d.numRow {
+			t.Fatalf("resultset %d: number of rows %d - expected %d", i, numRow, d.numRow)
+		}
+	}
+
+	if rows.NextResultSet() {
+		t.Fatal("no further resultset expected")
+	}
+}
@@ -161,4 +161,17 @@ func TestBulkInsertDuplicates(t *testing.T) {
 			t.Fatalf("statement number: %d - %d expected", dbError.StmtNo(), stmtNo[i])
 		}
 	}
+
+	multiErr, ok := AsMultiError(err)
+	if !ok {
+		t.Fatal("driver.MultiError expected")
+	}
+	if len(multiErr.Errors) != len(stmtNo) {
+		t.Fatalf("number of errors: %d - %d expected", len(multiErr.Errors), len(stmtNo))
+	}
+	for i, e := range multiErr.Errors {
+		if e.StmtNo != stmtNo[i] {
+			t.Fatalf("statement number: %d - %d expected", e.StmtNo, stmtNo[i])
+		}
+	}
 }
@@ -19,8 +19,12 @@ limitations under the License.
 package driver
 
 import (
+	"bytes"
 	"database/sql"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -187,4 +191,272 @@ func TestBulkInsertDuplicates(t *testing.T) {
 			t.Fatalf("statement number: %d - %d expected", dbError.StmtNo(), stmtNo[i])
 		}
 	}
+
+	multiErr, ok := AsMultiError(err)
+	if !ok {
+		t.Fatal("driver.MultiError expected")
+	}
+	if len(multiErr.Errors) != len(stmtNo) {
+		t.Fatalf("number of errors: %d - %d expected", len(multiErr.Errors), len(stmtNo))
+	}
+	for i, e := range multiErr.Errors {
+		if e.StmtNo != stmtNo[i] {
+			t.Fatalf("statement number: %d - %d expected", e.StmtNo, stmtNo[i])
+		}
+	}
+}
+
+// TestBulkInsertLob
+func TestBulkInsertLob(t *testing.T) {
+
+	const samples = 100
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("bulkInsertLob")
+
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, b blob)", TestSchema, table)); err != nil {
+		t.Fatalf("create table failed: %s", err)
+	}
+
+	stmt, err := db.Prepare(fmt.Sprintf("bulk insert into %s.%s values (?,?)", TestSchema, table))
+	if err != nil {
+		t.Fatalf("prepare bulk insert failed: %s", err)
+	}
+	defer stmt.Close()
+
+	content := func(i int) []byte {
+		return bytes.Repeat([]byte(fmt.Sprintf("row %d;", i)), i*100) // row 0: empty lob
+	}
+
+	for i := 0; i < samples; i++ {
+		if _, err := stmt.Exec(i, NewLob(bytes.NewReader(content(i)), nil)); err != nil {
+			t.Fatalf("insert failed: %s", err)
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		t.Fatalf("final insert (flush) failed: %s", err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select * from %s.%s order by i", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	i := 0
+	for rows.Next() {
+
+		var j int
+		b := new(bytes.Buffer)
+
+		if err := rows.Scan(&j, NewLob(nil, b)); err != nil {
+			t.Fatal(err)
+		}
+
+		if j != i {
+			t.Fatalf("value %d - expected %d", j, i)
+		}
+		if !bytes.Equal(b.Bytes(), content(i)) {
+			t.Fatalf("row %d: invalid lob content", i)
+		}
+
+		i++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if i != samples {
+		t.Fatalf("invalid number of records %d - %d expected", i, samples)
+	}
+}
+
+// TestBulkInsertSplit
+func TestBulkInsertSplit(t *testing.T) {
+	// bulk insert exceeds minimal packet size
+	testBulkInsertSplit(t, minPacketSize)
+	// bulk insert exceeds default packet size (packetSize not set)
+	testBulkInsertSplit(t, 0)
+}
+
+func testBulkInsertSplit(t *testing.T, packetSize int) {
+
+	samples := 1000
+
+	dsn, err := url.Parse(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packetSize != 0 {
+		values := dsn.Query()
+		values.Set(DSNPacketSize, strconv.Itoa(packetSize))
+		dsn.RawQuery = values.Encode()
+	} else {
+		packetSize = DefaultPacketSize
+	}
+	// rows of more than 1000 bytes: exceed packet size twice
+	if n := 2 * packetSize / 1000; n > samples {
+		samples = n
+	}
+
+	db, err := sql.Open(DriverName, dsn.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("bulkInsertSplit")
+
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, s nvarchar(1000))", TestSchema, table)); err != nil {
+		t.Fatalf("create table failed: %s", err)
+	}
+
+	stmt, err := db.Prepare(fmt.Sprintf("bulk insert into %s.%s values (?,?)", TestSchema, table))
+	if err != nil {
+		t.Fatalf("prepare bulk insert failed: %s", err)
+	}
+	defer stmt.Close()
+
+	s := strings.Repeat("x", 1000)
+
+	for i := 0; i < samples; i++ {
+		if _, err := stmt.Exec(i, s); err != nil {
+			t.Fatalf("insert failed: %s", err)
+		}
+	}
+	result, err := stmt.Exec()
+	if err != nil {
+		t.Fatalf("final insert (flush) failed: %s", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rowsAffected != int64(samples) {
+		t.Fatalf("rows affected %d - %d expected", rowsAffected, samples)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select i from %s.%s order by i", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	i := 0
+	for rows.Next() {
+		var j int
+		if err := rows.Scan(&j); err != nil {
+			t.Fatal(err)
+		}
+		if j != i {
+			t.Fatalf("value %d - expected %d", j, i)
+		}
+		i++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if i != samples {
+		t.Fatalf("invalid number of records %d - %d expected", i, samples)
+	}
+
+	// single row exceeding packet size
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?,?)", TestSchema, table), 0, strings.Repeat("x", packetSize)); err == nil {
+		t.Fatal("packet size error expected")
+	}
+}
+
+func TestBulkInsertAbort(t *testing.T) {
+
+	const samples = 100
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("bulkInsertAbort_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer)", TestSchema, table)); err != nil {
+		t.Fatalf("create table failed: %s", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("bulk insert into %s.%s values (?)", TestSchema, table))
+	if err != nil {
+		t.Fatalf("prepare bulk insert failed: %s", err)
+	}
+	defer stmt.Close()
+
+	for i := 0; i < samples; i++ {
+		if _, err := stmt.Exec(i); err != nil {
+			t.Fatalf("insert failed: %s", err)
+		}
+	}
+	if _, err := stmt.Exec(); err != nil { // send first batch
+		t.Fatalf("flush failed: %s", err)
+	}
+	for i := 0; i < samples; i++ { // buffered only
+		if _, err := stmt.Exec(i); err != nil {
+			t.Fatalf("insert failed: %s", err)
+		}
+	}
+
+	result, err := stmt.Exec(AbortBulk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rowsAffected != samples {
+		t.Fatalf("rows sent %d - %d expected", rowsAffected, samples)
+	}
+
+	if _, err := stmt.Exec(); err != nil { // nothing left to flush
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("insert into %s.%s values (?)", TestSchema, table), samples); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err == nil { // transaction was rolled back by the abort
+		t.Fatal("commit error expected")
+	}
+
+	var cnt int
+	if err := db.QueryRow(fmt.Sprintf("select count(*) from %s.%s", TestSchema, table)).Scan(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	if cnt != 0 {
+		t.Fatalf("number of records %d - %d expected", cnt, 0)
+	}
+
+	// abort outside of transaction
+	stmt2, err := db.Prepare(fmt.Sprintf("bulk insert into %s.%s values (?)", TestSchema, table))
+	if err != nil {
+		t.Fatalf("prepare bulk insert failed: %s", err)
+	}
+	defer stmt2.Close()
+
+	if _, err := stmt2.Exec(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stmt2.Exec(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stmt2.Exec(AbortBulk); err == nil {
+		t.Fatal("abort error expected")
+	}
 }
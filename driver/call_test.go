@@ -58,6 +58,45 @@ end
 
 }
 
+func TestCallNumArgs(t *testing.T) {
+	const procAdd = `create procedure %[1]s.%[2]s (in a integer, in b integer, out c integer)
+language SQLSCRIPT as
+begin
+    c := a + b;
+end
+`
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	procedure := RandomIdentifier("procAdd_")
+
+	if _, err := db.Exec(fmt.Sprintf(procAdd, TestSchema, procedure)); err != nil {
+		t.Fatal(err)
+	}
+
+	query := fmt.Sprintf("call %s.%s(?, ?, ?)", TestSchema, procedure)
+
+	// client side check: number of input arguments does not match
+	var c int
+	if err := db.QueryRow(query, 1).Scan(&c); err == nil || err.Error() != "invalid number of arguments 1 - 2 expected" {
+		t.Fatalf("error %v - expected invalid number of arguments", err)
+	}
+	if _, err := db.Exec(query, 1, 2, 3, sql.Out{Dest: &c}); err == nil || err.Error() != "invalid number of arguments 3 - 2 expected" {
+		t.Fatalf("error %v - expected invalid number of arguments", err)
+	}
+
+	if err := db.QueryRow(query, 1, 2).Scan(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c != 3 {
+		t.Fatalf("value %d - expected %d", c, 3)
+	}
+}
+
 func TestCallBlobEcho(t *testing.T) {
 	const procBlobEcho = `create procedure %[1]s.%[2]s (in idata blob, out odata blob)
 language SQLSCRIPT as
@@ -238,3 +277,141 @@ end
 	checkTableQueryData(t, db, tableQuery3, testTableQuery3Data)
 
 }
+
+func TestCallOutArgs(t *testing.T) {
+	const procOut = `create procedure %[1]s.%[2]s (in i integer, out o1 integer, in s nvarchar(25), out o2 nvarchar(25))
+language SQLSCRIPT as
+begin
+    o1 := i * 2;
+    o2 := s;
+end
+`
+	const txt = "Hello World!"
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	procedure := RandomIdentifier("procOut_")
+
+	if _, err := db.Exec(fmt.Sprintf(procOut, TestSchema, procedure)); err != nil {
+		t.Fatal(err)
+	}
+
+	var o1 int
+	var o2 string
+
+	if _, err := db.Exec(fmt.Sprintf("call %s.%s(?, ?, ?, ?)", TestSchema, procedure), 21, sql.Out{Dest: &o1}, txt, sql.Out{Dest: &o2}); err != nil {
+		t.Fatal(err)
+	}
+	if o1 != 42 || o2 != txt {
+		t.Fatalf("values %d %q - expected %d %q", o1, o2, 42, txt)
+	}
+}
+
+func TestCallInOutArgs(t *testing.T) {
+	const procInOut = `create procedure %[1]s.%[2]s (inout x integer)
+language SQLSCRIPT as
+begin
+    x := x * 2;
+end
+`
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	procedure := RandomIdentifier("procInOut_")
+
+	if _, err := db.Exec(fmt.Sprintf(procInOut, TestSchema, procedure)); err != nil {
+		t.Fatal(err)
+	}
+
+	query := fmt.Sprintf("call %s.%s(?)", TestSchema, procedure)
+
+	// inout parameter: input argument and output argument
+	var x int
+	if _, err := db.Exec(query, 21, sql.Out{Dest: &x}); err != nil {
+		t.Fatal(err)
+	}
+	if x != 42 {
+		t.Fatalf("value %d - expected %d", x, 42)
+	}
+
+	x = 21
+	if _, err := db.Exec(query, sql.Out{Dest: &x, In: true}); err == nil {
+		t.Fatal("input output argument error expected")
+	}
+}
+
+func TestCallTableOutNextResultSet(t *testing.T) {
+	const procTableOut = `create procedure %[1]s.%[2]s (in i integer, out o integer, out t1 %[1]s.%[3]s, out t2 %[1]s.%[3]s)
+language SQLSCRIPT as
+begin
+  o := i;
+  t1 = select 0 as i, 'A' as x from dummy;
+  t2 = select 1 as i, 'B' as x from dummy union all select 2 as i, 'C' as x from dummy;
+end
+`
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tableType := RandomIdentifier("tt3_")
+	procedure := RandomIdentifier("procTableOutNext_")
+
+	if _, err := db.Exec(fmt.Sprintf("create type %s.%s as table (i integer, x varchar(10))", TestSchema, tableType)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf(procTableOut, TestSchema, procedure, tableType)); err != nil {
+		t.Fatal(err)
+	}
+
+	var o int
+	rows, err := db.Query(fmt.Sprintf("call %s.%s(?, ?, ?, ?)", TestSchema, procedure), 7, sql.Out{Dest: &o})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	if o != 7 {
+		t.Fatalf("value %d - expected %d", o, 7)
+	}
+
+	expected := [][]*testTableData{
+		{{0, "A"}},
+		{{1, "B"}, {2, "C"}},
+	}
+	for _, data := range expected {
+		if !rows.NextResultSet() {
+			t.Fatalf("next result set expected: %v", rows.Err())
+		}
+		j := 0
+		for rows.Next() {
+			var i int
+			var x string
+			if err := rows.Scan(&i, &x); err != nil {
+				t.Fatal(err)
+			}
+			if i != data[j].i || x != data[j].x {
+				t.Fatalf("values %d %s - expected %d %s", i, x, data[j].i, data[j].x)
+			}
+			j++
+		}
+		if j != len(data) {
+			t.Fatalf("number of rows %d - expected %d", j, len(data))
+		}
+	}
+	if rows.NextResultSet() {
+		t.Fatal("no further result set expected")
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
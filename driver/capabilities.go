@@ -0,0 +1,39 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+// Capabilities are the features negotiated with the database server on connect.
+// Features not supported by the database server are disabled and the driver falls back
+// to the legacy protocol behavior (e.g. bulk inserts are executed row by row without array execution).
+type Capabilities struct {
+	ArrayExecution         bool // Execution of statements with multiple parameter rows (bulk insert) in one request (enabled unless disabled by the server).
+	LargeBulkOperations    bool // Bulk operations exceeding the row number limit of a single request.
+	LargeNumberOfParameter bool // Statements with more than 32767 parameters.
+	SplitBatchCommands     bool // Splitting of batch commands by the database server.
+	DataFormatVersion      int  // Effective data format version (might be lower than the requested version).
+}
+
+func (c *conn) Capabilities() Capabilities {
+	caps := c.session.Capabilities()
+	return Capabilities{
+		ArrayExecution:         caps.ArrayExecution,
+		LargeBulkOperations:    caps.LargeBulkOperations,
+		LargeNumberOfParameter: caps.LargeNumberOfParameter,
+		SplitBatchCommands:     caps.SplitBatchCommands,
+		DataFormatVersion:      caps.DataFormatVersion,
+	}
+}
@@ -0,0 +1,64 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+)
+
+/*
+CollectRows iterates rows, applies scanFn to each row and returns the results of scanFn as slice.
+Rows are closed by CollectRows. The first error of scanFn or of the iteration (e.g. a canceled context
+of the query) is returned.
+
+	type item struct {
+		ID   int
+		Name string
+	}
+
+	rows, err := db.QueryContext(ctx, "select id, name from items")
+	if err != nil {
+		...
+	}
+	items, err := driver.CollectRows(rows, func(rows *sql.Rows) (item, error) {
+		var i item
+		err := rows.Scan(&i.ID, &i.Name)
+		return i, err
+	})
+*/
+func CollectRows[T any](rows *sql.Rows, scanFn func(rows *sql.Rows) (T, error)) ([]T, error) {
+	defer rows.Close()
+
+	var r []T
+	for rows.Next() {
+		v, err := scanFn(rows)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
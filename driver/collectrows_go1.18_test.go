@@ -0,0 +1,70 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCollectRows(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	const query = "select 1, 'a' from dummy union all select 2, 'b' from dummy order by 1"
+
+	type item struct {
+		ID   int
+		Name string
+	}
+
+	scanItem := func(rows *sql.Rows) (item, error) {
+		var i item
+		err := rows.Scan(&i.ID, &i.Name)
+		return i, err
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := CollectRows(rows, scanItem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []item{{1, "a"}, {2, "b"}}
+	if !reflect.DeepEqual(items, expected) {
+		t.Fatalf("items %v - expected %v", items, expected)
+	}
+
+	// scan error
+	errScan := errors.New("scan failed")
+	if rows, err = db.Query(query); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CollectRows(rows, func(rows *sql.Rows) (item, error) { return item{}, errScan }); err != errScan {
+		t.Fatalf("error %v - expected %v", err, errScan)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/SAP/go-hdb/driver/sqltrace"
+)
+
+/*
+RowsColumnTypeComment may be implemented by driver.Rows. It extends the database/sql/driver column type
+interfaces by the comment (description) of a result column.
+
+The ok value is false if the column does not have a comment or the result column is not
+a table or view column (e.g. a calculated column).
+
+As resultset metadata do not include column comments, the comments are read from the database
+catalog (SYS.TABLE_COLUMNS, SYS.VIEW_COLUMNS) on first call for a column.
+*/
+type RowsColumnTypeComment interface {
+	driver.Rows
+	ColumnTypeComment(index int) (comment string, ok bool)
+}
+
+const columnCommentQuery = `select comments from sys.table_columns where schema_name = %[1]s and table_name = %[2]s and column_name = %[3]s
+union all
+select comments from sys.view_columns where schema_name = %[1]s and table_name = %[2]s and column_name = %[3]s`
+
+type columnComment struct {
+	comment string
+	ok      bool
+}
+
+// quoteLiteral returns s as sql string literal.
+func quoteLiteral(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+func (r *queryResult) ColumnTypeComment(idx int) (string, bool) {
+	if r.comments == nil {
+		r.comments = make([]*columnComment, r.resultFieldSet.NumField())
+	}
+
+	if r.comments[idx] == nil {
+		comment, ok, err := r.queryColumnComment(idx)
+		if err != nil {
+			sqltrace.Traceln(err)
+			return "", false // do not store: try again on next call
+		}
+		r.comments[idx] = &columnComment{comment: comment, ok: ok}
+	}
+	return r.comments[idx].comment, r.comments[idx].ok
+}
+
+func (r *queryResult) queryColumnComment(idx int) (string, bool, error) {
+	return r.queryColumnCatalog(columnCommentQuery, idx)
+}
+
+// queryColumnCatalog returns the value of a catalog query (format parameters: schema, table and column name) for column idx.
+func (r *queryResult) queryColumnCatalog(queryFormat string, idx int) (string, bool, error) {
+	f := r.resultFieldSet.Field(idx)
+
+	schemaName, tableName, columnName := f.SchemaName(), f.TableName(), f.ColumnName()
+	if schemaName == "" || tableName == "" || columnName == "" { // no table or view column
+		return "", false, nil
+	}
+
+	if r.session.IsBad() {
+		return "", false, driver.ErrBadConn
+	}
+
+	query := fmt.Sprintf(queryFormat, quoteLiteral(schemaName), quoteLiteral(tableName), quoteLiteral(columnName))
+
+	v, ok, err := queryValue(r.session, query)
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	switch v := v.(type) {
+	case []byte:
+		return string(v), true, nil
+	case string:
+		return v, true, nil
+	}
+	return "", false, nil // null value
+}
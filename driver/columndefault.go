@@ -0,0 +1,65 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+
+	"github.com/SAP/go-hdb/driver/sqltrace"
+)
+
+/*
+RowsColumnTypeDefault may be implemented by driver.Rows. It extends the database/sql/driver column type
+interfaces by the default value of a result column as defined in the table (or view) definition,
+e.g. to pre-populate input fields for inserting new rows.
+
+The ok value is false if the column does not have a default value or the result column is not
+a table or view column (e.g. a calculated column). The default value is returned as text
+as stored in the database catalog.
+
+As resultset metadata do not include column default values, the default values are read from the database
+catalog (SYS.TABLE_COLUMNS, SYS.VIEW_COLUMNS) on first call for a column.
+*/
+type RowsColumnTypeDefault interface {
+	driver.Rows
+	ColumnTypeDefault(index int) (value string, ok bool)
+}
+
+const columnDefaultQuery = `select default_value from sys.table_columns where schema_name = %[1]s and table_name = %[2]s and column_name = %[3]s
+union all
+select default_value from sys.view_columns where schema_name = %[1]s and table_name = %[2]s and column_name = %[3]s`
+
+type columnDefault struct {
+	value string
+	ok    bool
+}
+
+func (r *queryResult) ColumnTypeDefault(idx int) (string, bool) {
+	if r.defaults == nil {
+		r.defaults = make([]*columnDefault, r.resultFieldSet.NumField())
+	}
+
+	if r.defaults[idx] == nil {
+		value, ok, err := r.queryColumnCatalog(columnDefaultQuery, idx)
+		if err != nil {
+			sqltrace.Traceln(err)
+			return "", false // do not store: try again on next call
+		}
+		r.defaults[idx] = &columnDefault{value: value, ok: ok}
+	}
+	return r.defaults[idx].value, r.defaults[idx].ok
+}
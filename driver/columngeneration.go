@@ -0,0 +1,79 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"strings"
+
+	"github.com/SAP/go-hdb/driver/sqltrace"
+)
+
+/*
+RowsColumnTypeGenerated may be implemented by driver.Rows. It extends the database/sql/driver column type
+interfaces by the information whether the values of a result column are generated by the database server,
+e.g. for code generators needing to know the columns not to be set by inserts:
+
+	ColumnTypeAutoIncrement reports identity columns (GENERATED ALWAYS / BY DEFAULT AS IDENTITY).
+	ColumnTypeGenerated reports identity and calculated columns (GENERATED ALWAYS AS <expression>).
+
+The ok value is false if the result column is not a table or view column (e.g. a calculated column of the query).
+View columns are reported as not generated.
+
+As resultset metadata do not include the generation type of columns, the generation types are read from the
+database catalog (SYS.TABLE_COLUMNS) on first call for a column.
+*/
+type RowsColumnTypeGenerated interface {
+	driver.Rows
+	ColumnTypeAutoIncrement(index int) (autoIncrement, ok bool)
+	ColumnTypeGenerated(index int) (generated, ok bool)
+}
+
+const columnGenerationQuery = `select coalesce(generation_type, '') from sys.table_columns where schema_name = %[1]s and table_name = %[2]s and column_name = %[3]s
+union all
+select '' from sys.view_columns where schema_name = %[1]s and table_name = %[2]s and column_name = %[3]s`
+
+type columnGeneration struct {
+	generationType string // e.g. ALWAYS AS IDENTITY, BY DEFAULT AS IDENTITY, ALWAYS AS (empty: not generated)
+	ok             bool
+}
+
+func (r *queryResult) columnGeneration(idx int) (string, bool) {
+	if r.generations == nil {
+		r.generations = make([]*columnGeneration, r.resultFieldSet.NumField())
+	}
+
+	if r.generations[idx] == nil {
+		generationType, ok, err := r.queryColumnCatalog(columnGenerationQuery, idx)
+		if err != nil {
+			sqltrace.Traceln(err)
+			return "", false // do not store: try again on next call
+		}
+		r.generations[idx] = &columnGeneration{generationType: generationType, ok: ok}
+	}
+	return r.generations[idx].generationType, r.generations[idx].ok
+}
+
+func (r *queryResult) ColumnTypeAutoIncrement(idx int) (bool, bool) {
+	generationType, ok := r.columnGeneration(idx)
+	return strings.HasSuffix(strings.ToUpper(generationType), "IDENTITY"), ok
+}
+
+func (r *queryResult) ColumnTypeGenerated(idx int) (bool, bool) {
+	generationType, ok := r.columnGeneration(idx)
+	return generationType != "", ok
+}
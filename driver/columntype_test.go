@@ -18,7 +18,9 @@ package driver
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -211,3 +213,147 @@ func TestColumnType(t *testing.T) {
 		}
 	}
 }
+
+func TestColumnTypeComment(t *testing.T) {
+
+	const comment = "column's comment"
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("columnTypeComment_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, j integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("comment on column %s.%s.i is %s", TestSchema, table, quoteLiteral(comment))); err != nil {
+		t.Fatal(err)
+	}
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	rows, err := conn.(driver.QueryerContext).QueryContext(context.Background(), fmt.Sprintf("select i, j, i + j from %s.%s", TestSchema, table), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	commentRows, ok := rows.(RowsColumnTypeComment)
+	if !ok {
+		t.Fatal("RowsColumnTypeComment expected")
+	}
+
+	if c, ok := commentRows.ColumnTypeComment(0); !ok || c != comment {
+		t.Fatalf("column 0: comment %q %t - expected %q %t", c, ok, comment, true)
+	}
+	for _, idx := range []int{1, 2} { // j: no comment, i + j: calculated column
+		if c, ok := commentRows.ColumnTypeComment(idx); ok {
+			t.Fatalf("column %d: comment %q - no comment expected", idx, c)
+		}
+	}
+}
+
+func TestColumnTypeDefault(t *testing.T) {
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("columnTypeDefault_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer default 42, s nvarchar(10) default 'abc', j integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	rows, err := conn.(driver.QueryerContext).QueryContext(context.Background(), fmt.Sprintf("select i, s, j, i + j from %s.%s", TestSchema, table), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	defaultRows, ok := rows.(RowsColumnTypeDefault)
+	if !ok {
+		t.Fatal("RowsColumnTypeDefault expected")
+	}
+
+	for idx, value := range []string{"42", "abc"} {
+		if v, ok := defaultRows.ColumnTypeDefault(idx); !ok || strings.Trim(v, "'") != value {
+			t.Fatalf("column %d: default value %q %t - expected %q %t", idx, v, ok, value, true)
+		}
+	}
+	for _, idx := range []int{2, 3} { // j: no default value, i + j: calculated column
+		if v, ok := defaultRows.ColumnTypeDefault(idx); ok {
+			t.Fatalf("column %d: default value %q - no default value expected", idx, v)
+		}
+	}
+}
+
+func TestColumnTypeGenerated(t *testing.T) {
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("columnTypeGenerated_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (id integer generated by default as identity, i integer, j integer generated always as i * 2)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	rows, err := conn.(driver.QueryerContext).QueryContext(context.Background(), fmt.Sprintf("select id, i, j, i + j from %s.%s", TestSchema, table), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	generatedRows, ok := rows.(RowsColumnTypeGenerated)
+	if !ok {
+		t.Fatal("RowsColumnTypeGenerated expected")
+	}
+
+	for idx, expected := range []struct{ autoIncrement, generated bool }{{true, true}, {false, false}, {false, true}} {
+		autoIncrement, ok := generatedRows.ColumnTypeAutoIncrement(idx)
+		if !ok || autoIncrement != expected.autoIncrement {
+			t.Fatalf("column %d: auto increment %t %t - expected %t %t", idx, autoIncrement, ok, expected.autoIncrement, true)
+		}
+		generated, ok := generatedRows.ColumnTypeGenerated(idx)
+		if !ok || generated != expected.generated {
+			t.Fatalf("column %d: generated %t %t - expected %t %t", idx, generated, ok, expected.generated, true)
+		}
+	}
+	if _, ok := generatedRows.ColumnTypeGenerated(3); ok { // calculated column
+		t.Fatal("column 3: no table column expected")
+	}
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package driver
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"fmt"
 	"testing"
 )
 
@@ -40,3 +43,319 @@ func TestConnection(t *testing.T) {
 		t.Fatalf("dummy is %s - expected %s", dummy, "X")
 	}
 }
+
+func TestStatementRouting(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("routing_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (i integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	query := fmt.Sprintf("select * from %s.%s", TestSchema, table)
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// statement routing not enabled
+	c, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := c.(Conn).StatementRouting(query); err == nil {
+		t.Fatal("statement routing error expected")
+	}
+
+	connector.SetStatementRouting(true)
+	c, err = connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	hdbConn := c.(Conn)
+
+	topology := hdbConn.Topology()
+	if len(topology) == 0 {
+		t.Fatal("topology information expected")
+	}
+
+	hosts, err := hdbConn.StatementRouting(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the column table is located on one of the hosts
+	if len(hosts) != 1 {
+		t.Fatalf("routing hosts %v - expected one host of topology %v", hosts, topology)
+	}
+	if !containsTopologyHost(topology, hosts[0]) {
+		t.Fatalf("routing host %v not part of topology %v", hosts[0], topology)
+	}
+}
+
+func containsTopologyHost(hosts []TopologyHost, host TopologyHost) bool {
+	for _, h := range hosts {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCapabilities(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector.SetDataFormatVersion(DataFormatVersionSPS06)
+
+	c, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	caps := c.(Conn).Capabilities()
+	if caps.DataFormatVersion != DataFormatVersionBaseline && caps.DataFormatVersion != DataFormatVersionSPS06 {
+		t.Fatalf("data format version %d - expected %d or %d", caps.DataFormatVersion, DataFormatVersionBaseline, DataFormatVersionSPS06)
+	}
+	t.Logf("capabilities: %+v", caps)
+}
+
+func TestConnectionID(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	v, ok, err := queryValue(c.(*conn).session, "select current_connection from dummy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("current connection expected")
+	}
+	id, ok := v.(int64)
+	if !ok || int(id) != c.(Conn).ConnectionID() {
+		t.Fatalf("connection id %d - expected %v", c.(Conn).ConnectionID(), v)
+	}
+}
+
+func TestStatementSequenceInfo(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	table := RandomIdentifier("seqInfo_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	// driver connections (sql.Conn.Raw requires go 1.13)
+	ctx := context.Background()
+	conn1, err := connector.Connect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn1.Close()
+	conn2, err := connector.Connect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn2.Close()
+
+	if _, err := conn1.(driver.ExecerContext).ExecContext(ctx, fmt.Sprintf("insert into %s.%s values (1)", TestSchema, table), nil); err != nil {
+		t.Fatal(err)
+	}
+	seqInfo := conn1.(Conn).StatementSequenceInfo()
+	if seqInfo == nil {
+		t.Skip("statement sequence info not provided by database server")
+	}
+
+	// read on second connection at the snapshot of the first connection including the committed insert
+	conn2.(Conn).SetStatementSequenceInfo(seqInfo)
+	rows, err := conn2.(driver.QueryerContext).QueryContext(ctx, fmt.Sprintf("select count(*) from %s.%s", TestSchema, table), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	dest := make([]driver.Value, 1)
+	if err := rows.Next(dest); err != nil {
+		t.Fatal(err)
+	}
+	if count, ok := dest[0].(int64); !ok || count != 1 {
+		t.Fatalf("count %v - expected %d", dest[0], 1)
+	}
+	if conn2.(Conn).StatementSequenceInfo() == nil {
+		t.Fatal("statement sequence info of second connection expected")
+	}
+}
+
+func TestSessionContext(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector.SetSessionVariables(map[string]string{"APP_TENANT": "t1"})
+
+	c, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	hdbConn := c.(Conn)
+
+	sessionContext := func(key string) string {
+		v, _, err := queryValue(hdbConn.(*conn).session, fmt.Sprintf("select session_context(%s) from dummy", quoteLiteral(key)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch v := v.(type) {
+		case []byte:
+			return string(v)
+		case string:
+			return v
+		}
+		return "" // null value
+	}
+
+	if v := sessionContext("APP_TENANT"); v != "t1" {
+		t.Fatalf("session variable %q - expected %q", v, "t1")
+	}
+
+	if err := hdbConn.SetSessionContext("APP_USER", "o'neil"); err != nil {
+		t.Fatal(err)
+	}
+	if v := sessionContext("APP_USER"); v != "o'neil" {
+		t.Fatalf("session variable %q - expected %q", v, "o'neil")
+	}
+
+	if err := hdbConn.UnsetSessionContext("APP_USER"); err != nil {
+		t.Fatal(err)
+	}
+	if v := sessionContext("APP_USER"); v != "" {
+		t.Fatalf("session variable %q - expected %q", v, "")
+	}
+}
+
+func TestSessionSetup(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := connector.SetDefaultSchema("a\x00b"); err == nil {
+		t.Fatal("invalid schema name error expected")
+	}
+	if err := connector.SetDefaultSchema(string(TestSchema)); err != nil {
+		t.Fatal(err)
+	}
+	connector.SetSessionStatements([]string{"set transaction isolation level serializable"})
+
+	for i := 0; i < 2; i++ { // settings are applied to each new connection
+		c, err := connector.Connect(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v, _, err := queryValue(c.(*conn).session, "select current_schema from dummy")
+		c.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var schema string
+		switch v := v.(type) {
+		case []byte:
+			schema = string(v)
+		case string:
+			schema = v
+		}
+		if schema != string(TestSchema) {
+			t.Fatalf("connection %d: schema %q - expected %q", i, schema, TestSchema)
+		}
+	}
+
+	connector.SetSessionStatements([]string{"set invalid statement"})
+	if _, err := connector.Connect(context.Background()); err == nil {
+		t.Fatal("session statement error expected")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("validate_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	hdbConn := c.(Conn)
+	ctx := context.Background()
+
+	var validateData = []struct {
+		query string
+		valid bool
+	}{
+		{fmt.Sprintf("insert into %s.%s values (?)", TestSchema, table), true},
+		{fmt.Sprintf("delete from %s.%s", TestSchema, table), true},
+		{fmt.Sprintf("select j from %s.%s", TestSchema, table), false}, // invalid column
+		{fmt.Sprintf("select * from %s.%s", TestSchema, RandomIdentifier("unknown_")), false},
+		{"selct 1 from dummy", false},
+	}
+
+	for _, d := range validateData {
+		err := hdbConn.Validate(ctx, d.query)
+		if (err == nil) != d.valid {
+			t.Fatalf("query %s: error %v - expected valid %t", d.query, err, d.valid)
+		}
+	}
+
+	// no side effects: delete statement not executed
+	var i int
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (1)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	if err := hdbConn.Validate(ctx, fmt.Sprintf("delete from %s.%s", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(fmt.Sprintf("select count(*) from %s.%s", TestSchema, table)).Scan(&i); err != nil {
+		t.Fatal(err)
+	}
+	if i != 1 {
+		t.Fatalf("number of rows %d - expected %d", i, 1)
+	}
+	if n := hdbConn.(*conn).session.NumStatement(); n != 0 {
+		t.Fatalf("number of statements %d - expected %d", n, 0)
+	}
+}
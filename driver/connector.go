@@ -25,7 +25,13 @@ import (
 	"io/ioutil"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
+
+	"golang.org/x/text/encoding"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
 )
 
 /*
@@ -36,18 +42,85 @@ type Connector struct {
 	mu                             sync.RWMutex
 	host, username, password       string
 	locale                         string
+	databaseName                   string
+	maxRedirects                   int
 	bufferSize, fetchSize, timeout int
+	writeBufferSize                int
+	connectTimeout, queryTimeout   int
+	prepareTimeout                 int
+	packetSize                     int
+	autoCloseResultset             bool
+	nullAsZeroValue                bool
+	trimChar                       bool
+	emptyStringAsNull              bool
+	validateUTF8                   bool
+	serverTimezoneConversion       bool
+	charEncoding                   encoding.Encoding
+	decimalFormat                  DecimalFormat
+	decimalConverter               DecimalConverter
+	strictSecondPrecision          bool
+	dataFormatVersion              int
+	workloadClass                  string
+	asyncCommit                    bool
+	fetchRetryLimit                int
+	maxStatements                  int
+	dropStatementsImmediately      bool
+	statementRouting               bool
+	metadataCacheSize              int
+	metadataCache                  *p.MetadataCache // shared by the connections of the connector
+	clientInfo                     map[string]string
+	sessionVariables               map[string]string
+	defaultSchema                  string
+	sessionStatements              []string
 	tlsConfig                      *tls.Config
+	connEventHandler               ConnEventHandler
+	queryLogger                    QueryLogger
+	queryLogThreshold              time.Duration
+	numBadConn                     int // number of connections closed in bad state
 }
 
+// DefaultMaxRedirects is the default maximum number of connect redirects.
+const DefaultMaxRedirects = 3
+
+// DefaultBufferSize is the default size of the read buffer of a connection.
+const DefaultBufferSize = 4096
+
+// DefaultWriteBufferSize is the default size of the write buffer of a connection.
+const DefaultWriteBufferSize = 4096
+
+// minBufferSize is the minimal size of the read and write buffer of a connection.
+const minBufferSize = 512
+
+// Data format versions of date, time, boolean and decimal values (see Connector.SetDataFormatVersion).
+const (
+	// DataFormatVersionBaseline transfers DAYDATE, SECONDTIME, SECONDDATE and LONGDATE values
+	// as DATE, TIME and TIMESTAMP values of millisecond precision.
+	DataFormatVersionBaseline = 1
+	// DataFormatVersionSPS06 transfers DAYDATE, SECONDTIME, SECONDDATE and LONGDATE values natively,
+	// LONGDATE values in full precision (100 nanoseconds).
+	DataFormatVersionSPS06 = 4
+	// DataFormatVersionBoolean additionally transfers BOOLEAN values natively instead of TINYINT values.
+	DataFormatVersionBoolean = 7
+	// DataFormatVersionFixed additionally transfers DECIMAL(p,s) values as fixed size decimals (FIXED8, FIXED12, FIXED16)
+	// in the full precision of 38 digits.
+	DataFormatVersionFixed = 8
+)
+
 func newConnector() *Connector {
 	return &Connector{
-		fetchSize: DefaultFetchSize,
-		timeout:   DefaultTimeout,
+		bufferSize:         DefaultBufferSize,
+		writeBufferSize:    DefaultWriteBufferSize,
+		fetchSize:          DefaultFetchSize,
+		packetSize:         DefaultPacketSize,
+		autoCloseResultset: true,
+		timeout:            DefaultTimeout,
+		maxRedirects:       DefaultMaxRedirects,
+		dataFormatVersion:  DataFormatVersionBaseline,
 	}
 }
 
 // NewBasicAuthConnector creates a connector for basic authentication.
+// For connections via Unix domain socket the host is the socket address unix:///path/to/socket.
 func NewBasicAuthConnector(host, username, password string) *Connector {
 	c := newConnector()
 	c.host = host
@@ -66,6 +139,9 @@ func NewDSNConnector(dsn string) (*Connector, error) {
 	}
 
 	c.host = url.Host
+	if url.Host == "unix:" { // unix:///path/to/socket
+		c.host = p.UnixSocketPrefix + strings.TrimPrefix(url.Path, "//")
+	}
 
 	if url.User != nil {
 		c.username = url.User.Username()
@@ -94,6 +170,20 @@ func NewDSNConnector(dsn string) (*Connector, error) {
 				c.fetchSize = fetchSize
 			}
 
+		case DSNPacketSize:
+			if len(v) == 0 {
+				continue
+			}
+			packetSize, err := strconv.Atoi(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse packetSize: %s", v[0])
+			}
+			if packetSize < minPacketSize {
+				c.packetSize = minPacketSize
+			} else {
+				c.packetSize = packetSize
+			}
+
 		case DSNTimeout:
 			if len(v) == 0 {
 				continue
@@ -114,6 +204,12 @@ func NewDSNConnector(dsn string) (*Connector, error) {
 			}
 			c.locale = v[0]
 
+		case DSNDatabaseName:
+			if len(v) == 0 {
+				continue
+			}
+			c.databaseName = v[0]
+
 		case DSNTLSServerName:
 			if len(v) == 0 {
 				continue
@@ -197,6 +293,52 @@ func (c *Connector) SetLocale(locale string) {
 	c.mu.Unlock()
 }
 
+// DatabaseName returns the tenant database name of the connector.
+func (c *Connector) DatabaseName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.databaseName
+}
+
+/*
+SetDatabaseName sets the name of the tenant database to connect to.
+
+If set, the driver requests the host of the tenant database while connecting and transparently
+redirects the connection, if the connected host (e.g. the system database or a routing endpoint)
+does not serve the database. For the maximum number of redirects please see SetMaxRedirects.
+
+For more information please see DSNDatabaseName.
+*/
+func (c *Connector) SetDatabaseName(databaseName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.databaseName = databaseName
+	return nil
+}
+
+// MaxRedirects returns the maximum number of connect redirects.
+func (c *Connector) MaxRedirects() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.maxRedirects
+}
+
+/*
+SetMaxRedirects sets the maximum number of connect redirects (default DefaultMaxRedirects).
+
+Connecting fails, if the database server requests more redirects than the limit,
+thus preventing endless redirect loops of misconfigured systems. A limit of 0 disables redirects.
+*/
+func (c *Connector) SetMaxRedirects(max int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if max < 0 {
+		max = 0
+	}
+	c.maxRedirects = max
+	return nil
+}
+
 // FetchSize returns the fetchSize of the connector.
 func (c *Connector) FetchSize() int {
 	c.mu.RLock()
@@ -205,20 +347,677 @@ func (c *Connector) FetchSize() int {
 }
 
 /*
-SetFetchSize sets the fetchSize of the connector.
+SetFetchSize sets the fetchSize of the connector. The fetch size is limited to the range [1, 2^31-1].
 
 For more information please see DSNFetchSize.
 */
 func (c *Connector) SetFetchSize(fetchSize int) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if fetchSize < minFetchSize {
+	switch {
+	case fetchSize < minFetchSize:
 		fetchSize = minFetchSize
+	case fetchSize > maxFetchSize:
+		fetchSize = maxFetchSize
 	}
 	c.fetchSize = fetchSize
 	return nil
 }
 
+// BufferSize returns the size of the read buffer of the connections of the connector.
+func (c *Connector) BufferSize() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.bufferSize
+}
+
+/*
+SetBufferSize sets the size of the read buffer allocated per connection (default DefaultBufferSize).
+Values lower than 512 bytes are set to 512 bytes.
+
+The buffer size does not limit the size of the messages received from the database server: messages exceeding
+the buffer size are read in several chunks and the buffers holding the field values of a reply grow as needed.
+A larger buffer saves read calls on large messages, a smaller buffer saves memory for pools of mostly idle connections.
+The read buffer is allocated when a connection is opened, so that open connections keep their buffer size.
+*/
+func (c *Connector) SetBufferSize(bufferSize int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if bufferSize < minBufferSize {
+		bufferSize = minBufferSize
+	}
+	c.bufferSize = bufferSize
+	return nil
+}
+
+// WriteBufferSize returns the size of the write buffer of the connections of the connector.
+func (c *Connector) WriteBufferSize() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.writeBufferSize
+}
+
+/*
+SetWriteBufferSize sets the size of the write buffer allocated per connection (default DefaultWriteBufferSize).
+Values lower than 512 bytes are set to 512 bytes.
+
+Request data is written to the network connection whenever the write buffer is full and at the end of each request,
+so the buffer size is the flush threshold of large requests (e.g. bulk inserts of many parameter rows).
+A buffer size of a multiple of the TCP maximum segment size reduces the number of partially filled segments.
+Like the read buffer, the write buffer is allocated on connect and not resized for open connections.
+*/
+func (c *Connector) SetWriteBufferSize(writeBufferSize int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if writeBufferSize < minBufferSize {
+		writeBufferSize = minBufferSize
+	}
+	c.writeBufferSize = writeBufferSize
+	return nil
+}
+
+// PacketSize returns the packetSize of the connector.
+func (c *Connector) PacketSize() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.packetSize
+}
+
+/*
+SetPacketSize sets the packetSize of the connector.
+
+For more information please see DSNPacketSize.
+*/
+func (c *Connector) SetPacketSize(packetSize int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if packetSize < minPacketSize {
+		packetSize = minPacketSize
+	}
+	c.packetSize = packetSize
+	return nil
+}
+
+// AutoCloseResultset returns true, if resultsets are closed by the database server after the last packet was fetched.
+func (c *Connector) AutoCloseResultset() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.autoCloseResultset
+}
+
+/*
+SetAutoCloseResultset enables (default) or disables the automatic close of completely fetched resultsets.
+
+If enabled, the database server closes a resultset as soon as the last packet of the resultset was fetched,
+so that no additional round trip is needed to close the resultset. If disabled, resultsets are closed by the driver
+when closing the database/sql/driver/Rows.
+*/
+func (c *Connector) SetAutoCloseResultset(autoCloseResultset bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.autoCloseResultset = autoCloseResultset
+	return nil
+}
+
+// NullAsZeroValue returns true, if database NULL values are scanned as zero values of the column type.
+func (c *Connector) NullAsZeroValue() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.nullAsZeroValue
+}
+
+/*
+SetNullAsZeroValue enables or disables (default) reading database NULL values of resultsets as zero values
+of the column type (e.g. 0 for numeric types, the empty string for character types), so that NULL values
+can be scanned into non pointer destinations like int or string.
+
+Please note that enabling this option deviates from the database/sql default behavior, as NULL values cannot be
+distinguished from zero values anymore: scanning into sql.NullInt64 returns a valid value. NULL values
+of lob columns are not affected.
+Connections read the option once on connect, so that idle connections of a pool keep their NULL handling.
+*/
+func (c *Connector) SetNullAsZeroValue(nullAsZeroValue bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nullAsZeroValue = nullAsZeroValue
+	return nil
+}
+
+// TrimChar returns true, if trailing spaces of fixed length character values are trimmed.
+func (c *Connector) TrimChar() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.trimChar
+}
+
+/*
+SetTrimChar enables or disables (default) the trimming of trailing spaces of fixed length character values
+(CHAR, NCHAR) read from resultsets. Values of variable length character types are not affected.
+
+Please note that trimmed values cannot be distinguished from values stored with trailing spaces anymore.
+Whether values are trimmed is decided when the connection is opened; open connections are not affected.
+*/
+func (c *Connector) SetTrimChar(trimChar bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.trimChar = trimChar
+	return nil
+}
+
+// EmptyStringAsNull returns true, if empty string parameter values are bound as database NULL values.
+func (c *Connector) EmptyStringAsNull() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.emptyStringAsNull
+}
+
+/*
+SetEmptyStringAsNull enables or disables (default) binding empty string parameter values of character type
+parameters (CHAR, VARCHAR, NCHAR, NVARCHAR, ...) as database NULL values. By default an empty string is bound
+as empty string.
+
+Enabling this option eases the migration from databases not distinguishing between empty strings and NULL
+values (e.g. Oracle). Parameters of other types (e.g. binary or lob parameters) are not affected.
+The option is checked on each statement execution and therefore affects already open connections, too.
+*/
+func (c *Connector) SetEmptyStringAsNull(emptyStringAsNull bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.emptyStringAsNull = emptyStringAsNull
+	return nil
+}
+
+// ValidateUTF8 returns true, if unicode character parameter values are checked for valid UTF-8 before sending.
+func (c *Connector) ValidateUTF8() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.validateUTF8
+}
+
+/*
+SetValidateUTF8 enables or disables (default) checking string parameter values of unicode character type
+parameters (NCHAR, NVARCHAR, ...) for valid UTF-8 encoding on client side before sending them to the database server.
+Invalid values are rejected with an error naming the parameter and the byte offset of the first invalid UTF-8 sequence,
+instead of the less precise error returned by the database server.
+The option is read whenever parameter values are sent, so that it applies to already open connections as well.
+*/
+func (c *Connector) SetValidateUTF8(validateUTF8 bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validateUTF8 = validateUTF8
+	return nil
+}
+
+// ServerTimezoneConversion returns true, if date and time values are interpreted in the timezone of the database server.
+func (c *Connector) ServerTimezoneConversion() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.serverTimezoneConversion
+}
+
+/*
+SetServerTimezoneConversion enables or disables (default) interpreting the time-zone-naive database date and time
+values (e.g. TIMESTAMP) in the timezone of the database server instead of UTC.
+
+If enabled, the server timezone is read on connect (see Conn.ServerLocation): time values of query results and
+procedure output parameters are returned in the server location and time parameter values are converted to the
+server location before sending. Please note that the server location is a fixed UTC offset read on connect,
+so daylight saving time changes are not reflected by open connections. For the same reason connections
+already open (e.g. idle connections of a sql.DB pool) keep converting, or not converting, as set on connect.
+*/
+func (c *Connector) SetServerTimezoneConversion(serverTimezoneConversion bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.serverTimezoneConversion = serverTimezoneConversion
+	return nil
+}
+
+// CharEncoding returns the character encoding of non-unicode character values (nil: no conversion).
+func (c *Connector) CharEncoding() encoding.Encoding {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.charEncoding
+}
+
+/*
+SetCharEncoding sets the character encoding (code page) of non-unicode character values (CHAR, VARCHAR),
+e.g. charmap.Windows1252 of package golang.org/x/text/encoding/charmap. Values read from resultsets are
+converted from the encoding to UTF-8 and parameter values are converted from UTF-8 to the encoding.
+Unicode character values (NCHAR, NVARCHAR) and lob values are not affected.
+
+By default (nil) character values are not converted (UTF-8).
+Parameter values are encoded with the encoding set at execution time, whereas resultset values are decoded
+with the encoding set when the connection was opened.
+*/
+func (c *Connector) SetCharEncoding(enc encoding.Encoding) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.charEncoding = enc
+	return nil
+}
+
+// DecimalFormat returns the representation of decimal values read from resultsets.
+func (c *Connector) DecimalFormat() DecimalFormat {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.decimalFormat
+}
+
+/*
+SetDecimalFormat sets the representation of decimal values read from resultsets (default DecimalBinary).
+
+With DecimalTrimmed or DecimalScaled decimal values are returned as strings, which can be scanned into
+string variables or Decimal values. For DecimalScaled the fraction is padded with zeros to the scale of the
+column (e.g. "1.50" for a DECIMAL(5,2) value 1.5); values of floating point decimal columns (DECIMAL without
+precision and scale) are trimmed.
+Changing the format affects all resultsets opened afterwards, including those of open connections.
+*/
+func (c *Connector) SetDecimalFormat(format DecimalFormat) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if format < DecimalBinary || format > DecimalScaled {
+		return fmt.Errorf("invalid decimal format %d", format)
+	}
+	c.decimalFormat = format
+	return nil
+}
+
+// DecimalConverter returns the converter of decimal values read from resultsets.
+func (c *Connector) DecimalConverter() DecimalConverter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.decimalConverter
+}
+
+/*
+SetDecimalConverter sets the converter of decimal values read from resultsets (nil to remove), e.g. to scan
+DECIMAL columns into the decimal type of a decimal library. If set, the converter takes precedence over the
+decimal format (see SetDecimalFormat).
+Like the format, the converter is picked up by each resultset opened afterwards.
+*/
+func (c *Connector) SetDecimalConverter(conv DecimalConverter) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.decimalConverter = conv
+	return nil
+}
+
+// StrictSecondPrecision returns true, if binding time values with sub-second components to parameters
+// of second precision fails.
+func (c *Connector) StrictSecondPrecision() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.strictSecondPrecision
+}
+
+/*
+SetStrictSecondPrecision enables or disables (default) the precision check of time values bound to
+parameters of second precision (SECONDDATE, SECONDTIME).
+
+By default sub-second components of time values are truncated. With enabled check binding a time value
+with sub-second components fails instead. As the baseline data format version describes SECONDDATE and SECONDTIME
+parameters as TIMESTAMP and TIME parameters, connections opened after the check was enabled request at least
+DataFormatVersionSPS06 (see SetDataFormatVersion), in which parameters of second precision are reported natively.
+While the check itself is performed whenever time values are bound, the data format version is only raised on connect.
+*/
+func (c *Connector) SetStrictSecondPrecision(strict bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.strictSecondPrecision = strict
+	return nil
+}
+
+// WorkloadClass returns the workload class of statements executed on connections of the connector.
+func (c *Connector) WorkloadClass() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.workloadClass
+}
+
+/*
+SetWorkloadClass sets the workload class (default none) of statements executed on connections of the connector.
+Workload classes are defined in the database (CREATE WORKLOAD CLASS) and control resources like statement priority,
+thread and memory limits, e.g. to run batch jobs with lower priority than interactive queries.
+
+The workload class is sent to the database server as statement hint (WITH HINT(WORKLOAD_CLASS(...))) appended to
+statements supporting hints (SELECT, INSERT, UPDATE, DELETE, UPSERT, REPLACE, MERGE and CALL), which do not
+contain a hint clause yet. The class name is case sensitive and must not exceed 127 characters or contain
+double quotes or control characters. The workload class can be overridden per statement (see WithWorkloadClass).
+*/
+func (c *Connector) SetWorkloadClass(class string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := checkWorkloadClass(class); err != nil {
+		return err
+	}
+	c.workloadClass = class
+	return nil
+}
+
+// DataFormatVersion returns the data format version requested by connections of the connector
+// (at least DataFormatVersionSPS06 with enabled strict second precision check).
+func (c *Connector) DataFormatVersion() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.strictSecondPrecision && c.dataFormatVersion < DataFormatVersionSPS06 {
+		return DataFormatVersionSPS06
+	}
+	return c.dataFormatVersion
+}
+
+/*
+SetDataFormatVersion sets the data format version (default DataFormatVersionBaseline) requested by connections
+of the connector. Valid versions are DataFormatVersionBaseline, DataFormatVersionSPS06, DataFormatVersionBoolean and
+DataFormatVersionFixed.
+
+With the baseline version LONGDATE values are transferred as TIMESTAMP values and therefore truncated
+to milliseconds in both directions. Set DataFormatVersionSPS06 to read and write LONGDATE values in the full
+precision of 100 nanoseconds (time.Time values are truncated to 100 nanoseconds). Please note that with
+DataFormatVersionSPS06 resultset and parameter metadata report the native date and time types
+(e.g. ColumnTypeDatabaseTypeName returns LONGDATE instead of TIMESTAMP) and that reading ALPHANUM columns
+is not supported with this version.
+DataFormatVersionBoolean includes the date and time types of DataFormatVersionSPS06 and reports BOOLEAN columns
+and parameters natively instead of as TINYINT values; the UNKNOWN state of a BOOLEAN value is read as NULL.
+DataFormatVersionFixed includes the types of DataFormatVersionBoolean and transfers DECIMAL(p,s) values of up to
+38 digits exactly, while the decimal floating point format of the other versions is limited to 34 significant digits.
+With DecimalBinary (see SetDecimalFormat) such values exceeding 34 significant digits are read as decimal strings,
+which are scanned by Decimal, NullDecimal and BigInt like binary values.
+The version is negotiated when a connection is opened and does not change for the lifetime of the connection.
+*/
+func (c *Connector) SetDataFormatVersion(version int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch version {
+	case DataFormatVersionBaseline, DataFormatVersionSPS06, DataFormatVersionBoolean, DataFormatVersionFixed:
+	default:
+		return fmt.Errorf("invalid data format version %d", version)
+	}
+	c.dataFormatVersion = version
+	return nil
+}
+
+// ClientInfo returns a copy of the client information values of the connector.
+func (c *Connector) ClientInfo() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return copyValues(c.clientInfo)
+}
+
+/*
+SetClientInfo sets client information values (key value pairs) sent to the database server with the first
+statement execution of a connection, e.g. to identify the application in database server traces.
+
+The connector values apply to all statements of a connection. For client information values of single
+statements please see WithClientInfo.
+As the values are captured on connect, open connections keep sending the values set before.
+*/
+func (c *Connector) SetClientInfo(clientInfo map[string]string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clientInfo = copyValues(clientInfo)
+	return nil
+}
+
+// copyValues returns a copy of the key value pairs of m.
+func copyValues(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	values := make(map[string]string, len(m))
+	for k, v := range m {
+		values[k] = v
+	}
+	return values
+}
+
+// SessionVariables returns a copy of the session variables of the connector.
+func (c *Connector) SessionVariables() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return copyValues(c.sessionVariables)
+}
+
+/*
+SetSessionVariables sets session variables (key value pairs) which are set on each connection
+opened by the connector, including connections replacing connections in bad state.
+The values can be read in SQL by the function SESSION_CONTEXT (e.g. for row level security).
+
+For setting session variables of a single connection please see Conn.SetSessionContext.
+Changing the variables does not affect connections already open.
+*/
+func (c *Connector) SetSessionVariables(sessionVariables map[string]string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessionVariables = copyValues(sessionVariables)
+	return nil
+}
+
+// DefaultSchema returns the default schema of connections opened by the connector.
+func (c *Connector) DefaultSchema() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.defaultSchema
+}
+
+/*
+SetDefaultSchema sets the default schema (SET SCHEMA) of each connection opened by the connector,
+including connections replacing connections in bad state. An empty schema name (default) keeps the
+default schema of the database user.
+
+As the name is used as delimited identifier, it is case sensitive (e.g. names of schemas created with
+undelimited identifiers need to be provided in upper case).
+The schema is set once on connect and is not reapplied to open connections.
+*/
+func (c *Connector) SetDefaultSchema(schema string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if schema != "" {
+		if _, err := QuoteIdentifier(schema); err != nil {
+			return err
+		}
+	}
+	c.defaultSchema = schema
+	return nil
+}
+
+// SessionStatements returns a copy of the session statements of the connector.
+func (c *Connector) SessionStatements() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return copyStatements(c.sessionStatements)
+}
+
+/*
+SetSessionStatements sets SQL statements executed in order on each connection opened by the connector,
+including connections replacing connections in bad state, e.g. to set session defaults like
+
+	SET TRANSACTION ISOLATION LEVEL SERIALIZABLE
+
+The statements are executed after setting the default schema and the session variables
+(see SetDefaultSchema, SetSessionVariables), so that reconnected connections are set up like the
+connection they replace. Opening a connection fails, if a statement fails.
+Already open connections do not execute statements added later.
+*/
+func (c *Connector) SetSessionStatements(stmts []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessionStatements = copyStatements(stmts)
+	return nil
+}
+
+func copyStatements(stmts []string) []string {
+	if stmts == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(stmts)), stmts...)
+}
+
+// AsyncCommit returns true, if transactions are committed asynchronously.
+func (c *Connector) AsyncCommit() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.asyncCommit
+}
+
+/*
+SetAsyncCommit enables or disables (default) asynchronous commits.
+
+If enabled, sql.Tx.Commit sends the commit request to the database server without waiting for the reply,
+saving the commit round trip. The reply is read later, so that a commit error is not returned by sql.Tx.Commit
+but as *CommitError by the operation reading the reply, which is the session reset when the connection is reused
+from the sql.DB pool (Go 1.10 and later), the next statement executed on the connection (e.g. of a sql.Conn)
+or closing the connection.
+Please note that database/sql ignores session reset errors other than driver.ErrBadConn in some Go versions
+and that the error of closing pooled connections is not returned to the application.
+
+Enabling this option therefore trades durability guarantees for latency: a successful Commit does not
+ensure that the transaction was committed (persisted) by the database server, and a failed commit might
+only be noticed by an unrelated later operation or not at all. In case of a connection or server failure
+the transaction might be lost without notice. Only enable this option for workloads tolerating the loss
+of committed transactions, like ingest pipelines being able to replay data.
+*/
+func (c *Connector) SetAsyncCommit(asyncCommit bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.asyncCommit = asyncCommit
+	return nil
+}
+
+// FetchRetryLimit returns the maximum number of query re-executions on network errors while fetching resultset rows.
+func (c *Connector) FetchRetryLimit() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fetchRetryLimit
+}
+
+/*
+SetFetchRetryLimit sets the maximum number of query re-executions on network errors while fetching
+resultset rows (default 0: disabled).
+
+If enabled and the connection breaks while fetching, the query is executed again on a new connection and
+the rows already returned are skipped. Queries inside a transaction are not re-executed.
+
+Please note that this option should only be enabled if the queried data is not modified concurrently and the
+query returns the rows in a deterministic order (order by). Otherwise rows might be duplicated or missing.
+*/
+func (c *Connector) SetFetchRetryLimit(limit int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if limit < 0 {
+		limit = 0
+	}
+	c.fetchRetryLimit = limit
+	return nil
+}
+
+// MaxStatements returns the maximum number of open statements per connection.
+func (c *Connector) MaxStatements() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.maxStatements
+}
+
+/*
+SetMaxStatements sets the maximum number of open (prepared and not closed) statements per connection
+(default 0: unlimited).
+
+As the database server limits the number of prepared statements per session, preparing a statement
+on a connection having reached the limit fails with ErrMaxStatements instead of a database error.
+Setting a limit below the server limit therefore helps to detect statements which are not closed.
+*/
+func (c *Connector) SetMaxStatements(max int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if max < 0 {
+		max = 0
+	}
+	c.maxStatements = max
+	return nil
+}
+
+// DropStatementsImmediately returns true, if closed statements are released on the database server immediately.
+func (c *Connector) DropStatementsImmediately() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dropStatementsImmediately
+}
+
+/*
+SetDropStatementsImmediately enables or disables (default) releasing the database server statement handle
+of a statement immediately when the statement is closed.
+
+By default the handles of closed statements are released together with the next command executed on the
+connection, saving a round trip per statement. For workloads executing mostly one-shot statements (e.g. the DDL
+statements of migration tools) releasing the handles immediately keeps the number of statements held by the
+database server per session minimal, at the cost of a round trip per closed statement.
+The option is read whenever a statement is closed, so that it applies to already open connections as well.
+*/
+func (c *Connector) SetDropStatementsImmediately(dropStatementsImmediately bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dropStatementsImmediately = dropStatementsImmediately
+	return nil
+}
+
+// StatementRouting returns true, if the routing information of statements is requested from the database server.
+func (c *Connector) StatementRouting() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.statementRouting
+}
+
+/*
+SetStatementRouting enables or disables (default) requesting the routing information (table locations) of
+statements from the database server, which is needed by Conn.StatementRouting in scale-out systems.
+
+If enabled, connections negotiate the statement distribution mode with the database server, so that the server
+replies the volume ids of the tables accessed by prepared statements. The statements are not routed by the driver:
+choosing a connection to the returned hosts is left to the application or connection pool.
+The distribution mode is negotiated on connect, so that open connections keep the mode they were opened with.
+*/
+func (c *Connector) SetStatementRouting(statementRouting bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statementRouting = statementRouting
+	return nil
+}
+
+// MetadataCacheSize returns the maximum number of queries the connector caches prepared statement metadata for.
+func (c *Connector) MetadataCacheSize() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.metadataCacheSize
+}
+
+/*
+SetMetadataCacheSize sets the maximum number of queries the parameter and result metadata of prepared
+statements are cached for (default 0: no caching).
+
+The cache is shared by all connections of the connector, so that preparing a query already prepared
+before on any connection reuses the parsed metadata. Metadata returned by the database server differing
+from the cached metadata (e.g. after a table was altered) replace the cache entry of the query.
+Setting the size discards the currently cached metadata.
+*/
+func (c *Connector) SetMetadataCacheSize(size int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if size < 0 {
+		size = 0
+	}
+	c.metadataCacheSize = size
+	c.metadataCache = nil
+	if size > 0 {
+		c.metadataCache = p.NewMetadataCache(size)
+	}
+	return nil
+}
+
+func (c *Connector) sharedMetadataCache() *p.MetadataCache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.metadataCache
+}
+
 // Timeout returns the timeout of the connector.
 func (c *Connector) Timeout() int {
 	c.mu.RLock()
@@ -241,6 +1040,78 @@ func (c *Connector) SetTimeout(timeout int) error {
 	return nil
 }
 
+// ConnectTimeout returns the connect timeout of the connector.
+func (c *Connector) ConnectTimeout() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connectTimeout
+}
+
+/*
+SetConnectTimeout sets the connect timeout of the connector in seconds (default 0: the connector timeout is used).
+
+The connect timeout limits each attempt to establish a database connection: dialing the database server,
+the TLS handshake and the authentication. After the connection is established the connector timeout
+(see SetTimeout) applies.
+*/
+func (c *Connector) SetConnectTimeout(timeout int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if timeout < minTimeout {
+		timeout = minTimeout
+	}
+	c.connectTimeout = timeout
+	return nil
+}
+
+// QueryTimeout returns the default query timeout of the connector.
+func (c *Connector) QueryTimeout() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.queryTimeout
+}
+
+/*
+SetQueryTimeout sets the default query timeout of the connector in seconds (default 0: no query timeout).
+
+The query timeout limits the execution of statements (Exec, Query) called with a context without deadline.
+If the context passed to the statement execution has a deadline, the context deadline applies.
+The query timeout does not apply to establishing connections (see SetConnectTimeout).
+*/
+func (c *Connector) SetQueryTimeout(timeout int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if timeout < minTimeout {
+		timeout = minTimeout
+	}
+	c.queryTimeout = timeout
+	return nil
+}
+
+// PrepareTimeout returns the prepare timeout of the connector.
+func (c *Connector) PrepareTimeout() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.prepareTimeout
+}
+
+/*
+SetPrepareTimeout sets the prepare timeout of the connector in seconds (default 0: no prepare timeout).
+
+The prepare timeout limits the preparation of statements (Prepare), which includes the compilation of the
+statement on the database server, independently of the query timeout (see SetQueryTimeout) limiting the execution.
+If the context passed to the statement preparation has an earlier deadline, the context deadline applies.
+*/
+func (c *Connector) SetPrepareTimeout(timeout int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if timeout < minTimeout {
+		timeout = minTimeout
+	}
+	c.prepareTimeout = timeout
+	return nil
+}
+
 // TLSConfig returns the TLS configuration of the connector.
 func (c *Connector) TLSConfig() *tls.Config {
 	c.mu.RLock()
@@ -256,24 +1127,103 @@ func (c *Connector) SetTLSConfig(tlsConfig *tls.Config) error {
 	return nil
 }
 
+// ConnEventHandler returns the connection lifecycle event handler of the connector.
+func (c *Connector) ConnEventHandler() ConnEventHandler {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connEventHandler
+}
+
+// SetConnEventHandler sets the connection lifecycle event handler of the connector (nil to remove).
+func (c *Connector) SetConnEventHandler(h ConnEventHandler) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connEventHandler = h
+	return nil
+}
+
+// QueryLogger returns the statement execution logger of the connector.
+func (c *Connector) QueryLogger() QueryLogger {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.queryLogger
+}
+
+// SetQueryLogger sets the statement execution logger of the connector (nil to remove).
+func (c *Connector) SetQueryLogger(logger QueryLogger) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queryLogger = logger
+	return nil
+}
+
+// QueryLogThreshold returns the minimal duration of statement executions passed to the query logger.
+func (c *Connector) QueryLogThreshold() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.queryLogThreshold
+}
+
+/*
+SetQueryLogThreshold sets the minimal duration of statement executions passed to the query logger,
+so that only slow statements are logged. By default (0) all statement executions are logged.
+*/
+func (c *Connector) SetQueryLogThreshold(threshold time.Duration) error {
+	if threshold < 0 {
+		return fmt.Errorf("invalid query log threshold %s", threshold)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queryLogThreshold = threshold
+	return nil
+}
+
+// badConnClosed registers a connection closed in bad state.
+func (c *Connector) badConnClosed() {
+	c.mu.Lock()
+	c.numBadConn++
+	c.mu.Unlock()
+}
+
+// replacesBadConn returns true, if a new connection replaces a connection closed in bad state.
+func (c *Connector) replacesBadConn() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.numBadConn == 0 {
+		return false
+	}
+	c.numBadConn--
+	return true
+}
+
 // BasicAuthDSN return the connector DSN for basic authentication.
 func (c *Connector) BasicAuthDSN() string {
 	values := url.Values{}
 	if c.locale != "" {
 		values.Set(DSNLocale, c.locale)
 	}
+	if c.databaseName != "" {
+		values.Set(DSNDatabaseName, c.databaseName)
+	}
 	if c.fetchSize != 0 {
 		values.Set(DSNFetchSize, fmt.Sprintf("%d", c.fetchSize))
 	}
+	if c.packetSize != 0 {
+		values.Set(DSNPacketSize, fmt.Sprintf("%d", c.packetSize))
+	}
 	if c.timeout != 0 {
 		values.Set(DSNTimeout, fmt.Sprintf("%d", c.timeout))
 	}
-	return (&url.URL{
+	u := &url.URL{
 		Scheme:   DriverName,
 		User:     url.UserPassword(c.username, c.password),
 		Host:     c.host,
 		RawQuery: values.Encode(),
-	}).String()
+	}
+	if strings.HasPrefix(c.host, p.UnixSocketPrefix) {
+		u.Host, u.Path = "unix:", "//"+strings.TrimPrefix(c.host, p.UnixSocketPrefix)
+	}
+	return u.String()
 }
 
 // Connect implements the database/sql/driver/Connector interface.
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+// ConnEvent is the type of a connection lifecycle event.
+type ConnEvent int
+
+// Connection lifecycle events.
+const (
+	ConnEventConnect   ConnEvent = iota // A new database connection was established.
+	ConnEventReconnect                  // A new database connection was established replacing a connection closed in bad state.
+	ConnEventClose                      // A database connection was closed.
+)
+
+var connEventText = map[ConnEvent]string{
+	ConnEventConnect:   "connect",
+	ConnEventReconnect: "reconnect",
+	ConnEventClose:     "close",
+}
+
+func (e ConnEvent) String() string {
+	return connEventText[e]
+}
+
+// ConnInfo contains the metadata of a database connection passed to a ConnEventHandler.
+type ConnInfo struct {
+	Host          string // Database server host as configured in the connector.
+	ConnectionID  int    // Database server connection id.
+	ServerVersion string // Database server version (empty if not provided by the server).
+}
+
+/*
+A ConnEventHandler is called for connection lifecycle events of connections opened by a Connector.
+
+The handler is called in its own go routine after the event completed, so that it does not block the
+database connection. Therefore calls might be executed concurrently and not in event order.
+*/
+type ConnEventHandler func(event ConnEvent, info ConnInfo)
+
+func (c *conn) connInfo() ConnInfo {
+	return ConnInfo{
+		Host:          c.connector.Host(),
+		ConnectionID:  c.session.ConnectionID(),
+		ServerVersion: c.session.ServerVersion(),
+	}
+}
+
+func (c *conn) notify(event ConnEvent) {
+	if h := c.connector.ConnEventHandler(); h != nil {
+		go h(event, c.connInfo())
+	}
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package driver
 
 import (
+	"bytes"
 	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"math/big"
 	"reflect"
 	"time"
 
@@ -56,10 +60,20 @@ func checkNamedValue(prmFieldSet *p.ParameterFieldSet, nv *driver.NamedValue) er
 		return nil
 	}
 
+	if _, ok := nv.Value.(p.TypedNull); ok { // see NullOf
+		return nil
+	}
+
 	f := prmFieldSet.Field(idx)
 	dt := f.TypeCode().DataType()
 
-	value, err := convertNamedValue(idx, f, dt, nv.Value)
+	var value driver.Value
+	var err error
+	if f.TypeCode().IsFixed() { // without Valuer conversion to keep the precision of decimal values (see convertNvFixed)
+		value, err = convertNvFixed(f, nv.Value)
+	} else {
+		value, err = convertNamedValue(idx, f, dt, nv.Value)
+	}
 
 	if err != nil {
 		return err
@@ -69,6 +83,22 @@ func checkNamedValue(prmFieldSet *p.ParameterFieldSet, nv *driver.NamedValue) er
 	return nil
 }
 
+// checkSecondPrecision returns an error if the time value of nv has sub-second components, which would be
+// truncated writing the value to a parameter of second precision (SECONDDATE, SECONDTIME).
+func checkSecondPrecision(prmFieldSet *p.ParameterFieldSet, nv *driver.NamedValue) error {
+	idx := nv.Ordinal - 1
+
+	if idx >= prmFieldSet.NumInputField() {
+		return nil
+	}
+
+	f := prmFieldSet.Field(idx)
+	if t, ok := nv.Value.(time.Time); ok && f.TypeCode().SecondPrecision() && t.Nanosecond() != 0 {
+		return fmt.Errorf("argument %d: time value %s exceeds second precision of type %s", idx+1, t, f.TypeCode().TypeName())
+	}
+	return nil
+}
+
 func convertNamedValue(idx int, f *p.ParameterField, dt p.DataType, v driver.Value) (driver.Value, error) {
 	var err error
 
@@ -84,6 +114,9 @@ func convertNamedValue(idx int, f *p.ParameterField, dt p.DataType, v driver.Val
 	default:
 		return nil, fmt.Errorf("convert named value datatype error: %[1]d - %[1]s", dt)
 
+	case p.DtBoolean:
+		return convertNvBoolean(v)
+
 	case p.DtTinyint:
 		return convertNvInteger(v, minTinyint, maxTinyint)
 
@@ -103,7 +136,7 @@ func convertNamedValue(idx int, f *p.ParameterField, dt p.DataType, v driver.Val
 		return convertNvFloat(v, maxDouble)
 
 	case p.DtTime:
-		return convertNvTime(v)
+		return convertNvTime(idx, f, v)
 
 	case p.DtDecimal:
 		return convertNvDecimal(v)
@@ -127,6 +160,11 @@ func convertNvInteger(v interface{}, min, max int64) (driver.Value, error) {
 		return v, nil
 	}
 
+	// durations are represented as number of seconds
+	if d, ok := durationValue(v); ok {
+		return convertNvInteger(int64(d/time.Second), min, max)
+	}
+
 	rv := reflect.ValueOf(v)
 	switch rv.Kind() {
 
@@ -156,6 +194,33 @@ func convertNvInteger(v interface{}, min, max int64) (driver.Value, error) {
 	return nil, fmt.Errorf("unsupported integer conversion type error %[1]T %[1]v", v)
 }
 
+// boolean
+func convertNvBoolean(v interface{}) (driver.Value, error) {
+
+	if v == nil { // NULL (unknown)
+		return v, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Ptr:
+		// indirect pointers
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return convertNvBoolean(rv.Elem().Interface())
+	}
+
+	b, err := driver.Bool.ConvertValue(v) // integers 0, 1 and boolean strings
+	if err != nil {
+		return nil, fmt.Errorf("unsupported boolean conversion type error %[1]T %[1]v", v)
+	}
+	return b, nil
+}
+
 // float types
 func convertNvFloat(v interface{}, max float64) (driver.Value, error) {
 
@@ -184,7 +249,7 @@ func convertNvFloat(v interface{}, max float64) (driver.Value, error) {
 }
 
 // time
-func convertNvTime(v interface{}) (driver.Value, error) {
+func convertNvTime(idx int, f *p.ParameterField, v interface{}) (driver.Value, error) {
 
 	if v == nil {
 		return nil, nil
@@ -194,18 +259,36 @@ func convertNvTime(v interface{}) (driver.Value, error) {
 
 	case time.Time:
 		return v, nil
+
+	case string:
+		return parseTimeLiteral(idx, f, v)
+	}
+
+	// durations are represented as time of day (seconds since midnight)
+	if d, ok := durationValue(v); ok {
+		if f != nil && f.TypeCode().HasDatePart() {
+			return nil, fmt.Errorf("argument %d: duration cannot be converted to type %s", idx+1, f.TypeCode())
+		}
+		t, err := durationToTime(d)
+		if err != nil {
+			return nil, err
+		}
+		return t, nil
 	}
 
 	rv := reflect.ValueOf(v)
 
 	switch rv.Kind() {
 
+	case reflect.String:
+		return parseTimeLiteral(idx, f, rv.String())
+
 	case reflect.Ptr:
 		// indirect pointers
 		if rv.IsNil() {
 			return nil, nil
 		}
-		return convertNvTime(rv.Elem().Interface())
+		return convertNvTime(idx, f, rv.Elem().Interface())
 	}
 
 	if rv.Type().ConvertibleTo(typeOfTime) {
@@ -216,6 +299,58 @@ func convertNvTime(v interface{}) (driver.Value, error) {
 	return nil, fmt.Errorf("unsupported time conversion type error %[1]T %[1]v", v)
 }
 
+// ISO-8601 time literal layouts.
+// time.Parse accepts fractional seconds after the seconds field even if not part of the layout.
+var (
+	dateLiteralLayouts = []string{
+		"2006-01-02",
+	}
+	timeLiteralLayouts = []string{
+		"15:04:05",
+		"15:04",
+	}
+	timestampLiteralLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05Z07:00",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+)
+
+func literalLayouts(f *p.ParameterField) []string {
+	if f == nil { // type code unknown: accept all layouts
+		layouts := make([]string, 0, len(timestampLiteralLayouts)+len(timeLiteralLayouts))
+		layouts = append(layouts, timestampLiteralLayouts...)
+		return append(layouts, timeLiteralLayouts...)
+	}
+	tc := f.TypeCode()
+	switch {
+	case tc.HasDatePart() && tc.HasTimePart():
+		return timestampLiteralLayouts
+	case tc.HasDatePart():
+		return dateLiteralLayouts
+	default:
+		return timeLiteralLayouts
+	}
+}
+
+// parseTimeLiteral parses an ISO-8601 date, time or timestamp literal depending on the parameter field type.
+func parseTimeLiteral(idx int, f *p.ParameterField, s string) (time.Time, error) {
+	for _, layout := range literalLayouts(f) {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if f != nil {
+		if name := f.Name(); name != "" {
+			return time.Time{}, fmt.Errorf("invalid %s literal %q for parameter %d (%s)", f.TypeCode().TypeName(), s, idx+1, name)
+		}
+		return time.Time{}, fmt.Errorf("invalid %s literal %q for parameter %d", f.TypeCode().TypeName(), s, idx+1)
+	}
+	return time.Time{}, fmt.Errorf("invalid time literal %q for parameter %d", s, idx+1)
+}
+
 // decimal
 func convertNvDecimal(v interface{}) (driver.Value, error) {
 
@@ -223,13 +358,77 @@ func convertNvDecimal(v interface{}) (driver.Value, error) {
 		return nil, nil
 	}
 
-	if v, ok := v.([]byte); ok {
+	switch v := v.(type) {
+	case []byte:
 		return v, nil
+	case *big.Int:
+		return encodeBigInt(v)
 	}
 
 	return nil, fmt.Errorf("unsupported decimal conversion type error %[1]T %[1]v", v)
 }
 
+// convertNvFixed converts v into the unscaled value of the fixed size decimal parameter field f (see p.Fixed).
+// Decimal and integer values are converted directly, as the Valuer conversion into the decimal floating point
+// format is limited to 34 significant digits.
+func convertNvFixed(f *p.ParameterField, v interface{}) (driver.Value, error) {
+	x := new(big.Rat)
+
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case Decimal:
+		x.Set((*big.Rat)(&v))
+	case *Decimal:
+		if v == nil {
+			return nil, nil
+		}
+		x.Set((*big.Rat)(v))
+	case NullDecimal:
+		if !v.Valid {
+			return nil, nil
+		}
+		if v.Decimal == nil {
+			return nil, fmt.Errorf("invalid decimal value %v", v.Decimal)
+		}
+		x.Set((*big.Rat)(v.Decimal))
+	case *BigInt:
+		if v == nil {
+			return nil, nil
+		}
+		x.SetInt((*big.Int)(v))
+	case *big.Int:
+		if v == nil {
+			return nil, nil
+		}
+		x.SetInt(v)
+	default:
+		if _, ok := v.(driver.Valuer); ok {
+			var err error
+			if v, err = driver.DefaultParameterConverter.ConvertValue(v); err != nil {
+				return nil, err
+			}
+			if v == nil {
+				return nil, nil
+			}
+		}
+		b, ok := v.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unsupported decimal conversion type error %[1]T %[1]v", v)
+		}
+		if err := (*Decimal)(x).Scan(b); err != nil {
+			return nil, err
+		}
+	}
+
+	precision, scale, _ := f.TypePrecisionScale()
+	m, err := fixedInt(x, int(precision), int(scale))
+	if err != nil {
+		return nil, err
+	}
+	return (*p.Fixed)(m), nil
+}
+
 // string
 func convertNvString(v interface{}) (driver.Value, error) {
 
@@ -315,18 +514,20 @@ func convertNvLob(idx int, f *p.ParameterField, v interface{}) (driver.Value, er
 	}
 
 	switch v := v.(type) {
+	case []byte: // e.g. Geometry
+		return f.LobValue(bytes.NewReader(v)), nil
+	case json.RawMessage:
+		return f.LobValue(bytes.NewReader(v)), nil
 	case Lob:
 		if v.rd == nil {
 			return nil, fmt.Errorf("lob error: initial reader %[1]T %[1]v", v)
 		}
-		f.SetLobReader(v.rd)
-		return fmt.Sprintf("<lob %d", idx), nil
+		return f.LobValue(v.rd), nil
 	case *Lob:
 		if v.rd == nil {
 			return nil, fmt.Errorf("lob error: initial reader %[1]T %[1]v", v)
 		}
-		f.SetLobReader(v.rd)
-		return fmt.Sprintf("<lob %d", idx), nil
+		return f.LobValue(v.rd), nil
 	case NullLob:
 		if !v.Valid {
 			return nil, nil
@@ -334,8 +535,7 @@ func convertNvLob(idx int, f *p.ParameterField, v interface{}) (driver.Value, er
 		if v.Lob.rd == nil {
 			return nil, fmt.Errorf("lob error: initial reader %[1]T %[1]v", v)
 		}
-		f.SetLobReader(v.Lob.rd)
-		return fmt.Sprintf("<lob %d", idx), nil
+		return f.LobValue(v.Lob.rd), nil
 	case *NullLob:
 		if !v.Valid {
 			return nil, nil
@@ -343,8 +543,9 @@ func convertNvLob(idx int, f *p.ParameterField, v interface{}) (driver.Value, er
 		if v.Lob.rd == nil {
 			return nil, fmt.Errorf("lob error: initial reader %[1]T %[1]v", v)
 		}
-		f.SetLobReader(v.Lob.rd)
-		return fmt.Sprintf("<lob %d", idx), nil
+		return f.LobValue(v.Lob.rd), nil
+	case io.Reader: // stream of unknown size
+		return f.LobValue(v), nil
 	}
 
 	rv := reflect.ValueOf(v)
@@ -18,6 +18,8 @@ package driver
 
 import (
 	"bytes"
+	"database/sql"
+	"database/sql/driver"
 	"math"
 	"testing"
 	"time"
@@ -109,6 +111,37 @@ func TestConvertFloat(t *testing.T) {
 
 }
 
+func TestConvertBoolean(t *testing.T) {
+	b := true
+
+	var data = []struct {
+		v interface{}
+		r driver.Value
+	}{
+		{true, true},
+		{false, false},
+		{&b, true},
+		{1, true},
+		{sql.NullBool{Valid: true, Bool: false}, false},
+		{sql.NullBool{Valid: false, Bool: true}, nil}, // NULL (unknown)
+		{(*bool)(nil), nil},
+	}
+
+	for _, d := range data {
+		cv, err := convertNamedValue(0, nil, p.DtBoolean, d.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cv != d.r {
+			t.Fatalf("value %v: converted value %v - expected %v", d.v, cv, d.r)
+		}
+	}
+
+	if _, err := convertNamedValue(0, nil, p.DtBoolean, 2); err == nil {
+		t.Fatal("boolean conversion error expected")
+	}
+}
+
 type testCustomTime time.Time
 
 func assertEqualTime(t *testing.T, v interface{}, r time.Time) {
@@ -136,6 +169,46 @@ func TestConvertTime(t *testing.T) {
 
 }
 
+func TestConvertTimeLiteral(t *testing.T) {
+
+	// date, time and timestamp literals
+	assertEqualTime(t, "2018-01-02", time.Date(2018, time.January, 2, 0, 0, 0, 0, time.UTC))
+	assertEqualTime(t, "13:14:15", time.Date(0, time.January, 1, 13, 14, 15, 0, time.UTC))
+	assertEqualTime(t, "2018-01-02 13:14:15", time.Date(2018, time.January, 2, 13, 14, 15, 0, time.UTC))
+	assertEqualTime(t, "2018-01-02T13:14:15.1234567", time.Date(2018, time.January, 2, 13, 14, 15, 123456700, time.UTC))
+	assertEqualTime(t, "2018-01-02T13:14:15.123+01:00", time.Date(2018, time.January, 2, 12, 14, 15, 123000000, time.UTC))
+
+	// custom string data type
+	assertEqualTime(t, testCustomString("2018-01-02"), time.Date(2018, time.January, 2, 0, 0, 0, 0, time.UTC))
+
+	// malformed literals
+	for _, v := range []string{"", "2018-13-02", "2018-01-02 25:00:00", "02.01.2018"} {
+		if _, err := convertNamedValue(0, nil, p.DtTime, v); err == nil {
+			t.Fatalf("assert time literal error failed %q", v)
+		}
+	}
+}
+
+func TestConvertDuration(t *testing.T) {
+
+	d := 90*time.Minute + 30*time.Second + 500*time.Millisecond
+
+	// integer: number of seconds
+	assertEqualInt(t, p.DtInteger, d, 5430)
+	assertEqualInt(t, p.DtBigint, Duration(d), 5430)
+	assertEqualInt(t, p.DtBigint, &d, 5430)
+	assertEqualIntOutOfRangeError(t, p.DtTinyint, d)
+
+	// time: seconds since midnight
+	assertEqualTime(t, d, time.Date(1, time.January, 1, 1, 30, 30, 500000000, time.UTC))
+
+	for _, v := range []time.Duration{-time.Second, 24 * time.Hour} {
+		if _, err := convertNamedValue(0, nil, p.DtTime, v); err == nil {
+			t.Fatalf("assert duration out of range error failed %s", v)
+		}
+	}
+}
+
 type testCustomString string
 
 func assertEqualString(t *testing.T, dt p.DataType, v interface{}, r string) {
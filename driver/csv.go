@@ -0,0 +1,157 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvTimeFormats are the csv formats of the time types keyed by the database type names.
+var csvTimeFormats = map[string]string{
+	"DATE":       "2006-01-02",
+	"DAYDATE":    "2006-01-02",
+	"TIME":       "15:04:05",
+	"SECONDTIME": "15:04:05",
+	"SECONDDATE": "2006-01-02 15:04:05",
+	"TIMESTAMP":  "2006-01-02 15:04:05.0000000",
+	"LONGDATE":   "2006-01-02 15:04:05.0000000",
+}
+
+/*
+A CSVWriter writes resultsets in comma-separated values (CSV) format.
+
+The column values are formatted according to the column metadata: decimals are padded to the column scale,
+time values are formatted by type (e.g. DATE as 2006-01-02) and binary values are hex encoded.
+Rows are written one at a time, so that resultsets of any size are exported with constant memory.
+*/
+type CSVWriter struct {
+	Comma  rune   // Field delimiter (set to ',' by NewCSVWriter).
+	Null   string // Representation of NULL values (default: empty field).
+	Header bool   // If true, the column names are written as first record.
+
+	w *csv.Writer
+}
+
+// NewCSVWriter returns a new CSVWriter writing to w.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{Comma: ',', w: csv.NewWriter(w)}
+}
+
+// WriteCSV writes all remaining rows of rows to w in CSV format with default settings (see CSVWriter).
+func WriteCSV(w io.Writer, rows *sql.Rows) error {
+	return NewCSVWriter(w).Write(rows)
+}
+
+// Write writes all remaining rows of rows as CSV records. Rows are not closed by Write.
+func (w *CSVWriter) Write(rows *sql.Rows) error {
+	w.w.Comma = w.Comma
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	record := make([]string, len(columnTypes))
+
+	if w.Header {
+		for i, ct := range columnTypes {
+			record[i] = ct.Name()
+		}
+		if err := w.w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	values := make([]interface{}, len(columnTypes))
+	dest := make([]interface{}, len(columnTypes))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		for i, ct := range columnTypes {
+			if record[i], err = w.format(ct, values[i]); err != nil {
+				return err
+			}
+		}
+		if err := w.w.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// format returns the csv field of the column value v.
+func (w *CSVWriter) format(ct *sql.ColumnType, v interface{}) (string, error) {
+	if v == nil {
+		return w.Null, nil
+	}
+
+	if ct.ScanType() == scanTypeDecimal { // binary, formatted (DecimalFormat) or converted (DecimalConverter) value
+		if b, ok := v.([]byte); ok {
+			scale := int64(-1)
+			if _, s, ok := ct.DecimalSize(); ok && s != floatingDecimalScale {
+				scale = s
+			}
+			return decimalString(b, int(scale))
+		}
+	} else {
+		var err error
+		if v, err = chanValue(ct, v); err != nil {
+			return "", err
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return hex.EncodeToString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		bitSize := 64
+		if ct.ScanType() == scanTypeReal {
+			bitSize = 32
+		}
+		return strconv.FormatFloat(v, 'g', -1, bitSize), nil
+	case time.Time:
+		if format, ok := csvTimeFormats[ct.DatabaseTypeName()]; ok {
+			return v.Format(format), nil
+		}
+		return v.Format(time.RFC3339Nano), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	return fmt.Sprint(v), nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("csv_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, s nvarchar(20), d decimal(10,2), t date, b varbinary(10))", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	insert := fmt.Sprintf("insert into %s.%s values (?, ?, ?, ?, ?)", TestSchema, table)
+	if _, err := db.Exec(insert, 1, "a;b", (*Decimal)(big.NewRat(3, 2)), time.Date(2019, 3, 14, 0, 0, 0, 0, time.UTC), []byte{0xca, 0xfe}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(insert, 2, "c", nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select * from %s.%s order by i", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	b := new(bytes.Buffer)
+	w := NewCSVWriter(b)
+	w.Comma = ';'
+	w.Null = "NULL"
+	w.Header = true
+	if err := w.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+
+	const expected = "I;S;D;T;B\n1;\"a;b\";1.50;2019-03-14;cafe\n2;c;NULL;NULL;NULL\n"
+	if b.String() != expected {
+		t.Fatalf("csv %q - expected %q", b.String(), expected)
+	}
+}
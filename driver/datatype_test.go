@@ -101,6 +101,7 @@ var testStringData = []interface{}{
 	"€€",
 	"𝄞𝄞€€",
 	"𝄞𝄞𝄞€€",
+	"𝕳𝖆𝖓𝖆",
 	"aaaaaaaaaa",
 	sql.NullString{Valid: false, String: "Hello HDB"},
 	sql.NullString{Valid: true, String: "Hello HDB"},
@@ -204,6 +205,7 @@ func TestBoolean(t *testing.T) {
 		false,
 		sql.NullBool{Valid: false, Bool: true},
 		sql.NullBool{Valid: true, Bool: false},
+		sql.NullBool{Valid: true, Bool: true},
 	)
 }
 
@@ -634,3 +636,44 @@ func equalLongdate(t1, t2 time.Time) bool {
 	//HDB: nanosecond 7-digit precision
 	return equalDate(t1, t2) && equalTime(t1, t2) && (t1.Nanosecond()/100) == (t2.Nanosecond()/100)
 }
+
+func TestArray(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("array_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (i integer, a integer array)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (1, array(1, null, 3))", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select a from %s.%s where i = 1", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types[0].DatabaseTypeName() != "ARRAY" {
+		t.Fatalf("database type name %s - expected %s", types[0].DatabaseTypeName(), "ARRAY")
+	}
+
+	if !rows.Next() {
+		t.Fatal("row expected")
+	}
+	var a []interface{}
+	if err := rows.Scan(&a); err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 3 || a[0] != int64(1) || a[1] != nil || a[2] != int64(3) {
+		t.Fatalf("array %v - expected %v", a, []interface{}{int64(1), nil, int64(3)})
+	}
+}
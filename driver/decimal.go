@@ -22,7 +22,10 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
 	"sync"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
 )
 
 //bigint word size (*--> src/pkg/math/big/arith.go)
@@ -75,6 +78,44 @@ const (
 	dfUnderflow
 )
 
+// DecimalFormat defines the representation of decimal values read from resultsets (see Connector.SetDecimalFormat).
+type DecimalFormat int
+
+// DecimalFormat constants.
+const (
+	DecimalBinary  DecimalFormat = iota // binary value (scanned by Decimal)
+	DecimalTrimmed                      // string with trailing zeros of the fraction trimmed (e.g. "1.5")
+	DecimalScaled                       // string with fraction padded to column scale (e.g. "1.50")
+)
+
+/*
+A DecimalConverter converts decimal values read from resultsets (DECIMAL, SMALLDECIMAL) into a custom
+representation, e.g. the decimal type of a decimal library, avoiding intermediate conversions (see Connector.SetDecimalConverter).
+The decimal value is given by the sign, the mantissa as big-endian unsigned integer bytes and the decimal exponent:
+
+	value = (-1)^neg * mantissa * 10^exp
+
+The returned value is passed to the scan destination, which therefore needs to accept the value
+(e.g. a sql.Scanner implementation or a variable of the returned type).
+*/
+type DecimalConverter func(neg bool, mantissa []byte, exp int) (driver.Value, error)
+
+// convertDecimal converts the decimal field value b by conv.
+func convertDecimal(conv DecimalConverter, b []byte) (driver.Value, error) {
+	if len(b) != decimalSize {
+		return nil, fmt.Errorf("decimal: invalid size %d of %v - %d expected", len(b), b, decimalSize)
+	}
+	if (b[15] & 0x60) == 0x60 {
+		return nil, fmt.Errorf("decimal: format (infinity, nan, ...) not supported : %v", b)
+	}
+	m := new(big.Int)
+	neg, exp := decodeDecimal(b, m)
+	return conv(neg, m.Bytes(), exp)
+}
+
+// scale of floating point decimal fields (DECIMAL without precision and scale)
+const floatingDecimalScale = 32767
+
 // ErrDecimalOutOfRange means that a big.Rat exceeds the size of hdb decimal fields.
 var ErrDecimalOutOfRange = errors.New("decimal out of range error")
 
@@ -94,6 +135,13 @@ type Decimal big.Rat
 // Scan implements the database/sql/Scanner interface.
 func (d *Decimal) Scan(src interface{}) error {
 
+	if src, ok := src.(string); ok { // see DecimalFormat
+		if _, ok := (*big.Rat)(d).SetString(src); !ok {
+			return fmt.Errorf("decimal: invalid value %s", src)
+		}
+		return nil
+	}
+
 	b, ok := src.([]byte)
 	if !ok {
 		return fmt.Errorf("decimal: invalid data type %T", src)
@@ -107,24 +155,22 @@ func (d *Decimal) Scan(src interface{}) error {
 		return fmt.Errorf("decimal: format (infinity, nan, ...) not supported : %v", b)
 	}
 
-	v := (*big.Rat)(d)
-	p := v.Num()
-	q := v.Denom()
-
-	neg, exp := decodeDecimal(b, p)
+	// set value via SetFrac / SetInt (normalized):
+	// the denominator of a zero value big.Rat (Denom) is not a reference and cannot be set
+	m := new(big.Int)
+	neg, exp := decodeDecimal(b, m)
+	if neg {
+		m.Neg(m) // signed zero: sign is dropped
+	}
 
+	v := (*big.Rat)(d)
 	switch {
 	case exp < 0:
-		q.Set(exp10(exp * -1))
+		v.SetFrac(m, exp10(exp*-1))
 	case exp == 0:
-		q.Set(natOne)
+		v.SetInt(m)
 	case exp > 0:
-		p.Mul(p, exp10(exp))
-		q.Set(natOne)
-	}
-
-	if neg {
-		v.Neg(v)
+		v.SetInt(m.Mul(m, exp10(exp)))
 	}
 	return nil
 }
@@ -153,6 +199,27 @@ func (d Decimal) Value() (driver.Value, error) {
 	return v, err
 }
 
+// decimalString returns the decimal field value b as string. For scale >= 0 the fraction is padded to
+// scale digits, otherwise trailing zeros of the fraction are trimmed.
+func decimalString(b []byte, scale int) (string, error) {
+	var d Decimal
+	if err := d.Scan(b); err != nil {
+		return "", err
+	}
+	v := (*big.Rat)(&d)
+
+	if scale >= 0 {
+		return v.FloatString(scale), nil
+	}
+
+	_, exp := decodeDecimal(b, new(big.Int))
+	s := v.FloatString(max(-exp, 0))
+	if strings.IndexByte(s, '.') != -1 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s, nil
+}
+
 func convertRatToDecimal(x *big.Rat, m *big.Int, digits, minExp, maxExp int) (bool, int, decFlags) {
 
 	neg := x.Sign() < 0 //store sign
@@ -203,7 +270,7 @@ func convertRatToDecimal(x *big.Rat, m *big.Int, digits, minExp, maxExp int) (bo
 	switch {
 	case shift < 0:
 		a.Mul(a, exp10(shift*-1))
-	case exp > 0:
+	case shift > 0:
 		b.Mul(b, exp10(shift))
 	}
 
@@ -269,7 +336,8 @@ func digits10(p *big.Int) int {
 	k := p.BitLen() // 2^k <= p < 2^(k+1) - 1
 	//i := int(float64(k) / lg10) //minimal digits base 10
 	//i := int(float64(k) / lg10) //minimal digits base 10
-	i := k * 100 / 332
+	// lower bound of digits base 10 (log10(2) ~ 0.30103): estimate must not exceed the number of digits
+	i := (k - 1) * 30102 / 100000
 	if i < 1 {
 		i = 1
 	}
@@ -343,6 +411,101 @@ func encodeDecimal(m *big.Int, neg bool, exp int) (driver.Value, error) {
 	return b, nil
 }
 
+/*
+A BigInt is the driver representation of an integer database decimal field value (e.g. DECIMAL(38,0)) as big.Int.
+Values of type *big.Int can be bound to decimal parameters directly, scanning into a *big.Int variable i
+is supported by conversion:
+
+	db.QueryRow("select ...").Scan((*driver.BigInt)(i))
+
+Values are transferred without float conversion. In decimal floating point format integers of up to 34
+significant digits are supported (e.g. 38 digit values with trailing zeros) and binding integers of more
+significant digits fails with ErrDecimalOutOfRange. Use DataFormatVersionFixed (see Connector.SetDataFormatVersion)
+to transfer all values of DECIMAL(38,0) fields. A nil *BigInt is bound as NULL value.
+*/
+type BigInt big.Int
+
+// Scan implements the database/sql/Scanner interface.
+func (i *BigInt) Scan(src interface{}) error {
+	var d Decimal
+	if err := d.Scan(src); err != nil {
+		return err
+	}
+	v := (*big.Rat)(&d)
+	if !v.IsInt() {
+		return fmt.Errorf("decimal: value %s is not an integer", v.RatString())
+	}
+	(*big.Int)(i).Set(v.Num())
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (i *BigInt) Value() (driver.Value, error) {
+	return encodeBigInt((*big.Int)(i))
+}
+
+// encodeBigInt encodes the integer x as decimal value (nil: null value).
+func encodeBigInt(x *big.Int) (driver.Value, error) {
+	if x == nil {
+		return nil, nil
+	}
+	return encodeBigIntExp(x, 0)
+}
+
+// encodeBigIntExp encodes x * 10^exp as decimal value. Trailing zeros exceeding the mantissa size are
+// moved into the exponent.
+func encodeBigIntExp(x *big.Int, exp int) (driver.Value, error) {
+	m := new(big.Int).Abs(x)
+	if m.Cmp(maxDecimal) > 0 {
+		q, r := new(big.Int), new(big.Int)
+		for m.Cmp(maxDecimal) > 0 {
+			if q.QuoRem(m, natTen, r); r.Sign() != 0 {
+				return nil, ErrDecimalOutOfRange
+			}
+			m, q = q, m
+			exp++
+		}
+	}
+	return encodeDecimal(m, x.Sign() < 0, exp)
+}
+
+// fixedValue converts the fixed size decimal field value x of scale into the representation of decimal values
+// given by format and conv. In DecimalBinary format values exceeding the 34 significant digits of the decimal
+// floating point format are returned as exact decimal string.
+func fixedValue(x *p.Fixed, scale int, format DecimalFormat, conv DecimalConverter) (driver.Value, error) {
+	m := (*big.Int)(x)
+	if conv != nil {
+		return conv(m.Sign() < 0, new(big.Int).Abs(m).Bytes(), -scale)
+	}
+	if format == DecimalBinary {
+		if v, err := encodeBigIntExp(m, -scale); err == nil {
+			return v, nil
+		}
+	}
+	s := new(big.Rat).SetFrac(m, exp10(scale)).FloatString(scale)
+	if format == DecimalTrimmed && strings.IndexByte(s, '.') != -1 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s, nil
+}
+
+// fixedInt returns the unscaled value of x for a fixed size decimal field of precision and scale. Fractions
+// exceeding the scale are rounded (half away from zero).
+func fixedInt(x *big.Rat, precision, scale int) (*big.Int, error) {
+	q, r := new(big.Int).QuoRem(new(big.Int).Mul(x.Num(), exp10(scale)), x.Denom(), new(big.Int))
+	if r.Sign() != 0 && r.Lsh(r.Abs(r), 1).Cmp(x.Denom()) >= 0 {
+		if x.Sign() < 0 {
+			q.Sub(q, natOne)
+		} else {
+			q.Add(q, natOne)
+		}
+	}
+	if q.Sign() != 0 && digits10(new(big.Int).Abs(q)) > precision {
+		return nil, ErrDecimalOutOfRange
+	}
+	return q, nil
+}
+
 // NullDecimal represents an Decimal that may be null.
 // NullDecimal implements the Scanner interface so
 // it can be used as a scan destination, similar to NullString.
@@ -353,16 +516,17 @@ type NullDecimal struct {
 
 // Scan implements the Scanner interface.
 func (n *NullDecimal) Scan(value interface{}) error {
-	var b []byte
-
-	b, n.Valid = value.([]byte)
-	if !n.Valid {
+	switch value.(type) {
+	case []byte, string: // binary or string value (see DecimalFormat)
+		n.Valid = true
+	default:
+		n.Valid = false
 		return nil
 	}
 	if n.Decimal == nil {
 		return fmt.Errorf("invalid decimal value %v", n.Decimal)
 	}
-	return n.Decimal.Scan(b)
+	return n.Decimal.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
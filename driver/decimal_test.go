@@ -17,8 +17,14 @@ limitations under the License.
 package driver
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
 	"math/big"
+	"strings"
 	"testing"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
 )
 
 func TestDecimalInfo(t *testing.T) {
@@ -53,6 +59,8 @@ var testDigits10Data = []*testDigits10{
 	&testDigits10{new(big.Int).SetInt64(999999999), 9},
 	&testDigits10{new(big.Int).SetInt64(1000000000), 10},
 	&testDigits10{new(big.Int).SetInt64(9999999999), 10},
+	&testDigits10{exp10(dec128Bias), dec128Bias + 1},
+	&testDigits10{new(big.Int).Sub(exp10(dec128Bias), natOne), dec128Bias},
 }
 
 func TestDigits10(t *testing.T) {
@@ -113,3 +121,452 @@ func TestConvertRat(t *testing.T) {
 		}
 	}
 }
+
+type testDecimalScan struct {
+	m   int64
+	neg bool
+	exp int
+	s   string // expected value (big.Rat.FloatString with -exp digits after the decimal point)
+}
+
+var testDecimalScanData = []*testDecimalScan{
+	&testDecimalScan{0, false, 0, "0"},
+	&testDecimalScan{0, true, 0, "0"}, // signed zero
+	&testDecimalScan{0, false, -10, "0.0000000000"},
+	&testDecimalScan{0, true, -10, "0.0000000000"},
+	&testDecimalScan{0, false, 10, "0"},
+	&testDecimalScan{1, false, -10, "0.0000000001"},
+	&testDecimalScan{1, true, -10, "-0.0000000001"},
+	&testDecimalScan{12345, false, -2, "123.45"},
+	&testDecimalScan{12345, true, -2, "-123.45"},
+	&testDecimalScan{12345, false, -5, "0.12345"},
+	&testDecimalScan{12345, false, -7, "0.0012345"},
+	&testDecimalScan{12345, false, 0, "12345"},
+	&testDecimalScan{12345, false, 3, "12345000"},
+	&testDecimalScan{12345, true, 3, "-12345000"},
+	&testDecimalScan{1, false, dec128MaxExp, "1" + strings.Repeat("0", dec128MaxExp)},
+	&testDecimalScan{1, false, dec128MinExp, "0." + strings.Repeat("0", -dec128MinExp-1) + "1"},
+}
+
+func TestDecimalScan(t *testing.T) {
+	d := new(Decimal) // reuse scan destination
+
+	for i, td := range testDecimalScanData {
+		b, err := encodeDecimal(big.NewInt(td.m), td.neg, td.exp)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// scan into zero value and into used destination
+		for _, dest := range []*Decimal{new(Decimal), d} {
+			if err := dest.Scan(b); err != nil {
+				t.Fatal(err)
+			}
+			if s := (*big.Rat)(dest).FloatString(max(-td.exp, 0)); s != td.s {
+				t.Fatalf("%d: value %s - expected %s", i, s, td.s)
+			}
+		}
+
+		// round trip
+		v, err := d.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := new(Decimal)
+		if err := r.Scan(v); err != nil {
+			t.Fatal(err)
+		}
+		if (*big.Rat)(r).Cmp((*big.Rat)(d)) != 0 {
+			t.Fatalf("%d: round trip value %s - expected %s", i, (*big.Rat)(r).RatString(), (*big.Rat)(d).RatString())
+		}
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	var data = []struct {
+		m       int64
+		neg     bool
+		exp     int
+		scale   int
+		trimmed string
+		scaled  string
+	}{
+		{15, false, -1, 2, "1.5", "1.50"},
+		{150, true, -2, 2, "-1.5", "-1.50"},
+		{100, false, -2, 2, "1", "1.00"},
+		{0, false, -10, 2, "0", "0.00"},
+		{0, true, 0, 0, "0", "0"},
+		{12, false, 3, 2, "12000", "12000.00"},
+		{12345, false, -4, 4, "1.2345", "1.2345"},
+	}
+
+	for i, d := range data {
+		b, err := encodeDecimal(big.NewInt(d.m), d.neg, d.exp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range []struct {
+			scale int
+			s     string
+		}{{-1, d.trimmed}, {d.scale, d.scaled}} {
+			s, err := decimalString(b.([]byte), f.scale)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != f.s {
+				t.Fatalf("%d scale %d: value %s - expected %s", i, f.scale, s, f.s)
+			}
+		}
+
+		// scan string representation
+		var dec Decimal
+		if err := dec.Scan(d.trimmed); err != nil {
+			t.Fatal(err)
+		}
+		var v Decimal
+		if err := v.Scan(b); err != nil {
+			t.Fatal(err)
+		}
+		if (*big.Rat)(&dec).Cmp((*big.Rat)(&v)) != 0 {
+			t.Fatalf("%d: scanned value %s - expected %s", i, (*big.Rat)(&dec).RatString(), (*big.Rat)(&v).RatString())
+		}
+	}
+}
+
+func TestConvertDecimal(t *testing.T) {
+	type value struct {
+		neg      bool
+		mantissa string
+		exp      int
+	}
+
+	conv := func(neg bool, mantissa []byte, exp int) (driver.Value, error) {
+		return value{neg: neg, mantissa: new(big.Int).SetBytes(mantissa).String(), exp: exp}, nil
+	}
+
+	m, _ := new(big.Int).SetString("9999999999999999999999999999999999", 10) // 34 digits
+	var data = []struct {
+		m   *big.Int
+		neg bool
+		exp int
+	}{
+		{big.NewInt(15), false, -1},
+		{big.NewInt(150), true, -2},
+		{big.NewInt(0), false, 0},
+		{m, true, -10},
+	}
+
+	for i, d := range data {
+		b, err := encodeDecimal(d.m, d.neg, d.exp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := convertDecimal(conv, b.([]byte))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := value{neg: d.neg, mantissa: d.m.String(), exp: d.exp}
+		if v != expected {
+			t.Fatalf("%d: value %v - expected %v", i, v, expected)
+		}
+	}
+
+	if _, err := convertDecimal(conv, []byte{0x01}); err == nil {
+		t.Fatal("invalid decimal size error expected")
+	}
+}
+
+func TestDecodeArrayDecimals(t *testing.T) {
+	b, err := encodeDecimal(big.NewInt(15), false, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []DecimalFormat{DecimalBinary, DecimalTrimmed, DecimalScaled} {
+		r := &queryResult{decimalFormat: format}
+		dest := []driver.Value{[]interface{}{p.ArrayDecimal(b.([]byte)), nil}}
+		if err := r.decodeArrayDecimals(dest); err != nil {
+			t.Fatal(err)
+		}
+		values := dest[0].([]interface{})
+		if values[1] != nil {
+			t.Fatalf("format %d: value %v - expected nil", format, values[1])
+		}
+		switch v := values[0].(type) {
+		case *Decimal:
+			if format != DecimalBinary || (*big.Rat)(v).RatString() != "3/2" {
+				t.Fatalf("format %d: value %s - expected 3/2", format, (*big.Rat)(v).RatString())
+			}
+		case string:
+			if format == DecimalBinary || v != "1.5" {
+				t.Fatalf("format %d: value %s - expected 1.5", format, v)
+			}
+		default:
+			t.Fatalf("format %d: invalid value type %T", format, v)
+		}
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	if maxDecimal.String() != strings.Repeat("9", dec128Digits) {
+		t.Fatalf("max decimal %s - expected %d digits", maxDecimal, dec128Digits)
+	}
+
+	var data = []struct {
+		s   string
+		exp int // expected exponent
+	}{
+		{"0", 0},
+		{"-1", 0},
+		{"1234567890123456789012345678901234", 0},               // 34 digits
+		{"-12345678901234567890123456789012340000", 4},          // 38 digits
+		{"99999999999999999999999999999999990000", 4},           // 38 digits
+		{"10000000000000000000000000000000000000", 4},           // 38 digits
+		{"12345678901234567890123456789012340000000000000", 13}, // trailing zeros
+	}
+
+	for _, d := range data {
+		in, _ := new(big.Int).SetString(d.s, 10)
+		v, err := convertNvDecimal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := v.([]byte)
+		if _, exp := decodeDecimal(b, new(big.Int)); exp != d.exp {
+			t.Fatalf("%s: exponent %d - expected %d", d.s, exp, d.exp)
+		}
+
+		out := new(big.Int)
+		if err := (*BigInt)(out).Scan(b); err != nil {
+			t.Fatal(err)
+		}
+		if out.Cmp(in) != 0 {
+			t.Fatalf("value %s - expected %s", out, in)
+		}
+	}
+
+	// nil: null value
+	if v, err := convertNvDecimal((*big.Int)(nil)); err != nil || v != nil {
+		t.Fatalf("value %v error %v - expected nil", v, err)
+	}
+	if v, err := (*BigInt)(nil).Value(); err != nil || v != nil {
+		t.Fatalf("value %v error %v - expected nil", v, err)
+	}
+
+	// fraction
+	b, err := encodeDecimal(big.NewInt(15), false, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := new(BigInt).Scan(b); err == nil {
+		t.Fatal("no integer error expected")
+	}
+}
+
+func TestFixedValue(t *testing.T) {
+	conv := func(neg bool, mantissa []byte, exp int) (driver.Value, error) {
+		return fmt.Sprintf("%t %s %d", neg, new(big.Int).SetBytes(mantissa), exp), nil
+	}
+
+	var data = []struct {
+		m       string
+		scale   int
+		trimmed string
+		scaled  string
+	}{
+		{"150", 2, "1.5", "1.50"},
+		{"-150", 2, "-1.5", "-1.50"},
+		{"0", 3, "0", "0.000"},
+		{"12345678901234567890123456789012345678", 0, "12345678901234567890123456789012345678", "12345678901234567890123456789012345678"}, // 38 digits
+		{"-99999999999999999999999999999999999999", 10, "-9999999999999999999999999999.9999999999", "-9999999999999999999999999999.9999999999"},
+	}
+
+	for _, d := range data {
+		m, _ := new(big.Int).SetString(d.m, 10)
+		x := (*p.Fixed)(m)
+
+		for _, e := range []struct {
+			format DecimalFormat
+			s      string
+		}{{DecimalTrimmed, d.trimmed}, {DecimalScaled, d.scaled}} {
+			if v, err := fixedValue(x, d.scale, e.format, nil); err != nil || v != e.s {
+				t.Fatalf("%s scale %d format %d: value %v error %v - expected %s", d.m, d.scale, e.format, v, err, e.s)
+			}
+		}
+
+		v, err := fixedValue(x, d.scale, DecimalBinary, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := v.([]byte); !ok && len(strings.TrimLeft(d.m, "-")) <= dec128Digits {
+			t.Fatalf("%s: value type %T - expected binary value", d.m, v)
+		}
+		var dec Decimal // binary value or exact string
+		if err := dec.Scan(v); err != nil {
+			t.Fatal(err)
+		}
+		expected := new(big.Rat).SetFrac(m, exp10(d.scale))
+		if (*big.Rat)(&dec).Cmp(expected) != 0 {
+			t.Fatalf("%s scale %d: value %s - expected %s", d.m, d.scale, (*big.Rat)(&dec).RatString(), expected.RatString())
+		}
+
+		expectedConv := fmt.Sprintf("%t %s %d", m.Sign() < 0, new(big.Int).Abs(m), -d.scale)
+		if v, err := fixedValue(x, d.scale, DecimalBinary, conv); err != nil || v != expectedConv {
+			t.Fatalf("%s: value %v error %v - expected %s", d.m, v, err, expectedConv)
+		}
+	}
+}
+
+func TestFixedInt(t *testing.T) {
+	var data = []struct {
+		x         string
+		precision int
+		scale     int
+		m         string
+	}{
+		{"1.5", 5, 2, "150"},
+		{"-1.005", 5, 2, "-101"}, // rounded half away from zero
+		{"1.004", 5, 2, "100"},
+		{"0", 1, 0, "0"},
+		{"12345678901234567890123456789012345678", 38, 0, "12345678901234567890123456789012345678"},
+		{"-9999999999999999999999999999.9999999999", 38, 10, "-99999999999999999999999999999999999999"},
+	}
+
+	for _, d := range data {
+		x, _ := new(big.Rat).SetString(d.x)
+		m, err := fixedInt(x, d.precision, d.scale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.String() != d.m {
+			t.Fatalf("%s precision %d scale %d: value %s - expected %s", d.x, d.precision, d.scale, m, d.m)
+		}
+	}
+
+	// exceeding precision
+	for _, d := range []struct {
+		x         string
+		precision int
+		scale     int
+	}{
+		{"100000000000000000000000000000000000000", 38, 0}, // 39 digits
+		{"999.995", 5, 2}, // rounded to 1000.00
+	} {
+		x, _ := new(big.Rat).SetString(d.x)
+		if _, err := fixedInt(x, d.precision, d.scale); err != ErrDecimalOutOfRange {
+			t.Fatalf("%s: error %v - expected %v", d.x, err, ErrDecimalOutOfRange)
+		}
+	}
+}
+
+func TestNullDecimalScan(t *testing.T) {
+	for _, v := range []interface{}{"-1.25", nil} {
+		n := NullDecimal{Decimal: new(Decimal)}
+		if err := n.Scan(v); err != nil {
+			t.Fatal(err)
+		}
+		if n.Valid != (v != nil) {
+			t.Fatalf("%v: valid %t - expected %t", v, n.Valid, v != nil)
+		}
+		if n.Valid && (*big.Rat)(n.Decimal).RatString() != "-5/4" {
+			t.Fatalf("value %s - expected -5/4", (*big.Rat)(n.Decimal).RatString())
+		}
+	}
+}
+
+func TestBigIntDecimal(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("bigInt_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, d decimal(38,0))", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	values := []string{"1234567890123456789012345678901234", "-12345678901234567890123456789012340000"}
+	for i, s := range values {
+		in, _ := new(big.Int).SetString(s, 10)
+		if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), i, in); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, s := range values {
+		out := new(big.Int)
+		if err := db.QueryRow(fmt.Sprintf("select d from %s.%s where i = ?", TestSchema, table), i).Scan((*BigInt)(out)); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != s {
+			t.Fatalf("value %s - expected %s", out, s)
+		}
+	}
+}
+
+func TestBigIntDecimalFixed(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := connector.SetDataFormatVersion(DataFormatVersionFixed); err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	table := RandomIdentifier("bigIntFixed_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, d decimal(38,0), f decimal(38,10))", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	values := []struct {
+		d string
+		f string
+	}{
+		{"12345678901234567890123456789012345678", "1234567890123456789012345678.9012345678"}, // 38 digits
+		{"-99999999999999999999999999999999999999", "-9999999999999999999999999999.9999999999"},
+		{"", ""}, // null value
+	}
+	for i, v := range values {
+		var d *big.Int
+		var f *Decimal
+		if v.d != "" {
+			d, _ = new(big.Int).SetString(v.d, 10)
+			r, _ := new(big.Rat).SetString(v.f)
+			f = (*Decimal)(r)
+		}
+		if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?, ?)", TestSchema, table), i, (*BigInt)(d), f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, v := range values {
+		d := NullDecimal{Decimal: new(Decimal)}
+		f := NullDecimal{Decimal: new(Decimal)}
+		if err := db.QueryRow(fmt.Sprintf("select d, f from %s.%s where i = ?", TestSchema, table), i).Scan(&d, &f); err != nil {
+			t.Fatal(err)
+		}
+		if v.d == "" {
+			if d.Valid || f.Valid {
+				t.Fatalf("row %d: null value expected", i)
+			}
+			continue
+		}
+		if s := (*big.Rat)(d.Decimal).RatString(); s != v.d {
+			t.Fatalf("row %d: value %s - expected %s", i, s, v.d)
+		}
+		if s := (*big.Rat)(f.Decimal).FloatString(10); s != v.f {
+			t.Fatalf("row %d: value %s - expected %s", i, s, v.f)
+		}
+
+		out := new(big.Int)
+		if err := db.QueryRow(fmt.Sprintf("select d from %s.%s where i = ?", TestSchema, table), i).Scan((*BigInt)(out)); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != v.d {
+			t.Fatalf("row %d: value %s - expected %s", i, out, v.d)
+		}
+	}
+}
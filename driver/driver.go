@@ -24,6 +24,8 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/SAP/go-hdb/driver/sqltrace"
@@ -70,14 +72,181 @@ var ErrUnsupportedIsolationLevel = errors.New("Unsupported isolation level")
 // ErrNestedTransaction is the error raised if a tranasction is created within a transaction as this is not supported by hdb.
 var ErrNestedTransaction = errors.New("Nested transactions are not supported")
 
+// ErrNoTransaction is the error raised if transaction information is requested outside of a transaction.
+var ErrNoTransaction = errors.New("Connection is not in a transaction")
+
+// ErrMaxStatements is the error raised if a statement is prepared while the connection reached the
+// maximum number of open statements (see Connector.SetMaxStatements).
+var ErrMaxStatements = errors.New("Maximum number of open statements reached")
+
+// defaultLockWaitTimeout is the hdb default lock wait timeout (indexserver.ini: transaction/lock_wait_timeout).
+// The default value is assumed as session lock wait timeout, if the lock wait timeout was not set in the
+// session (no LOCK_WAIT_TIMEOUT entry in M_SESSION_CONTEXT).
+const defaultLockWaitTimeout = 1800000 * time.Millisecond
+
+// ErrInvalidLockWaitTimeout is the error raised if a transaction is started with a negative lock wait timeout.
+var ErrInvalidLockWaitTimeout = errors.New("Invalid lock wait timeout")
+
+type ctxKey int
+
+const (
+	lockWaitTimeoutCtxKey ctxKey = iota
+	fetchSizeCtxKey
+	clientInfoCtxKey
+	rowDecoderCtxKey
+	holdCursorCtxKey
+	scrollableCursorCtxKey
+	workloadClassCtxKey
+	callerCtxKey
+)
+
+// WithLockWaitTimeout returns a copy of ctx with a lock wait timeout (millisecond precision) for transactions
+// started with the returned context (sql.DB.BeginTx).
+// The session lock wait timeout is set for the duration of the transaction and restored when the transaction
+// is committed or rolled back. The restored value is the session value effective when the transaction was started.
+func WithLockWaitTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, lockWaitTimeoutCtxKey, timeout)
+}
+
+// WithFetchSize returns a copy of ctx with a fetch size overriding the connector fetch size for queries
+// executed with the returned context. The fetch size is limited to the range [1, 2^31-1].
+//
+// For more information please see DSNFetchSize.
+func WithFetchSize(ctx context.Context, fetchSize int) context.Context {
+	switch {
+	case fetchSize < minFetchSize:
+		fetchSize = minFetchSize
+	case fetchSize > maxFetchSize:
+		fetchSize = maxFetchSize
+	}
+	return context.WithValue(ctx, fetchSizeCtxKey, fetchSize)
+}
+
+// ctxFetchSize returns the fetch size of ctx or 0 (connector fetch size) if not set.
+func ctxFetchSize(ctx context.Context) int {
+	fetchSize, _ := ctx.Value(fetchSizeCtxKey).(int)
+	return fetchSize
+}
+
+// WithClientInfo returns a copy of ctx with the client information value of key, which is sent to the database
+// server with statements executed with the returned context (e.g. a trace or correlation id appearing in
+// expensive statement traces). The value applies to the single statement only and overrides the connector
+// client information value of key (see Connector.SetClientInfo).
+func WithClientInfo(ctx context.Context, key, value string) context.Context {
+	parent, _ := ctx.Value(clientInfoCtxKey).(map[string]string)
+	values := make(map[string]string, len(parent)+1)
+	for k, v := range parent {
+		values[k] = v
+	}
+	values[key] = value
+	return context.WithValue(ctx, clientInfoCtxKey, values)
+}
+
+// ctxClientInfo returns the client information values of ctx or nil if not set.
+func ctxClientInfo(ctx context.Context) map[string]string {
+	values, _ := ctx.Value(clientInfoCtxKey).(map[string]string)
+	return values
+}
+
+/*
+WithHoldCursor returns a copy of ctx requesting the resultset of the query executed with the returned context
+to be kept open over commits (cursor holdability). By default the database server closes open resultsets
+of a connection on commit.
+
+Holding the resultset allows to commit intermediate work on the connection while still iterating the rows
+of a large resultset, e.g. by executing statements in autocommit mode on the same sql.Conn.
+Please note that sql.Tx closes all rows of the transaction on commit regardless of the cursor holdability.
+*/
+func WithHoldCursor(ctx context.Context) context.Context {
+	return context.WithValue(ctx, holdCursorCtxKey, true)
+}
+
+// ctxHoldCursor returns true, if cursor holdability is requested by ctx.
+func ctxHoldCursor(ctx context.Context) bool {
+	holdCursor, _ := ctx.Value(holdCursorCtxKey).(bool)
+	return holdCursor
+}
+
+// ResultsetType defines the cursor type of query resultsets (see WithResultsetType).
+type ResultsetType int
+
+// ResultsetType constants.
+const (
+	ResultsetForwardOnly ResultsetType = iota // rows can only be iterated forward (default)
+	ResultsetScrollable                       // rows can be iterated forward and backward (see RowsScrollable)
+)
+
+/*
+WithScrollableCursor returns a copy of ctx requesting a scrollable cursor for the resultset of the query executed
+with the returned context. The rows of a resultset opened with a scrollable cursor can be iterated backwards
+starting from the last row (see RowsScrollable), e.g. for paging backwards without sorting the rows on client side.
+*/
+func WithScrollableCursor(ctx context.Context) context.Context {
+	return WithResultsetType(ctx, ResultsetScrollable)
+}
+
+/*
+WithResultsetType returns a copy of ctx requesting the resultset type for the query executed with the returned context.
+Forward only resultsets are the default and should be preferred, as scrollable cursors are kept open on the database
+server until the rows are closed. ResultsetForwardOnly resets a scrollable cursor requested by a parent context.
+*/
+func WithResultsetType(ctx context.Context, resultsetType ResultsetType) context.Context {
+	return context.WithValue(ctx, scrollableCursorCtxKey, resultsetType)
+}
+
+// ctxScrollableCursor returns true, if a scrollable cursor is requested by ctx.
+func ctxScrollableCursor(ctx context.Context) bool {
+	resultsetType, _ := ctx.Value(scrollableCursorCtxKey).(ResultsetType)
+	return resultsetType == ResultsetScrollable
+}
+
+// withQueryTimeout returns a copy of ctx with the connector query timeout, if set and ctx does not have a deadline.
+func withQueryTimeout(ctx context.Context, connector *Connector) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || connector == nil {
+		return ctx, func() {}
+	}
+	timeout := connector.QueryTimeout()
+	if timeout == 0 {
+		return ctx, func() {}
+	}
+	// keep the caller context for re-executions of the query after the timeout context was canceled (see queryRetry)
+	return context.WithTimeout(context.WithValue(ctx, callerCtxKey, ctx), time.Duration(timeout)*time.Second)
+}
+
+// ctxCaller returns the context ctx was derived from by withQueryTimeout, or ctx itself.
+func ctxCaller(ctx context.Context) context.Context {
+	if caller, ok := ctx.Value(callerCtxKey).(context.Context); ok {
+		return caller
+	}
+	return ctx
+}
+
+// withPrepareTimeout returns a copy of ctx with the connector prepare timeout, if set.
+func withPrepareTimeout(ctx context.Context, connector *Connector) (context.Context, context.CancelFunc) {
+	if connector == nil {
+		return ctx, func() {}
+	}
+	timeout := connector.PrepareTimeout()
+	if timeout == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, time.Duration(timeout)*time.Second) // an earlier context deadline is kept
+}
+
 // needed for testing
 const driverDataFormatVersion = 1
 
 // queries
 const (
-	pingQuery          = "select 1 from dummy"
-	isolationLevelStmt = "set transaction isolation level %s"
-	accessModeStmt     = "set transaction %s"
+	pingQuery            = "select 1 from dummy"
+	isolationLevelStmt   = "set transaction isolation level %s"
+	accessModeStmt       = "set transaction %s"
+	lockWaitTimeoutStmt  = "set transaction lock wait timeout %d"
+	lockWaitTimeoutQuery = "select value from sys.m_session_context where connection_id = current_connection and key = 'LOCK_WAIT_TIMEOUT'"
+	transactionIDQuery   = "select transaction_id from sys.m_transactions where connection_id = current_connection"
+	setSessionContext    = "set %s = %s"
+	unsetSessionContext  = "unset %s"
+	setSchemaStmt        = "set schema %s"
 )
 
 // bulk statement
@@ -86,6 +255,23 @@ const noFlush = "$nf"
 // NoFlush is to be used as parameter in bulk inserts.
 var NoFlush = sql.Named(noFlush, nil)
 
+const abortBulk = "$ab"
+
+/*
+AbortBulk is to be used as the only parameter of a bulk insert statement execution to abort the bulk operation.
+
+Buffered rows not yet sent to the database are discarded and the enclosing transaction is rolled back,
+so that rows of already sent batches are not committed. The RowsAffected value of
+the returned result reports the number of rows which had been sent to the database by the statement
+before the abort. As the transaction is rolled back, a subsequent commit of the transaction fails,
+so that statements executed after the abort are not committed either. The transaction needs to be rolled back.
+
+As batches sent in auto commit mode are committed immediately, an abort of a bulk insert
+statement executed outside of a transaction returns an error reporting the number of already committed rows.
+All-or-nothing loads should therefore execute the bulk insert statement in the context of a transaction.
+*/
+var AbortBulk = sql.Named(abortBulk, nil)
+
 var drv = &hdbDrv{}
 
 func init() {
@@ -125,27 +311,128 @@ var (
 	// QueryContext is needed for stored procedures with table output parameters.
 	_ driver.Queryer           = (*conn)(nil)
 	_ driver.NamedValueChecker = (*conn)(nil)
+	_ Conn                     = (*conn)(nil)
 )
 
+/*
+Conn enhances a database/sql/driver connection by hdb specific methods.
+The driver connection of a database/sql connection can be accessed via sql.Conn.Raw.
+*/
+type Conn interface {
+	driver.Conn
+	// TransactionID returns the database transaction id of the current transaction
+	// (see monitoring view M_TRANSACTIONS). It returns ErrNoTransaction if the connection
+	// is not in a transaction.
+	TransactionID() (int64, error)
+	// ConnectionID returns the database server connection id as provided by the database server on connect
+	// (see monitoring view M_CONNECTIONS), e.g. to correlate client logs with server side monitoring or to
+	// cancel the session (ALTER SYSTEM CANCEL SESSION).
+	ConnectionID() int
+	// StatementSequenceInfo returns the statement sequence info returned by the database server with the last
+	// statement, identifying the snapshot (commit sequence) the statement was executed on. It is nil if the
+	// database server did not return a sequence info.
+	StatementSequenceInfo() []byte
+	// SetStatementSequenceInfo sets the statement sequence info sent with the next statement executed on the
+	// connection, e.g. the sequence info of another connection after a commit: the database server executes the
+	// statement on a snapshot including the changes of that commit, enabling consistent reads across connections
+	// (where supported by the database server).
+	SetStatementSequenceInfo(info []byte)
+	// StatementContext returns the server execution information (execution time, CPU time, memory usage)
+	// of the last statement executed on the connection.
+	StatementContext() StatementContext
+	// ServerLocation returns the timezone of the database server as fixed UTC offset (read from the database server
+	// on first call or on connect, if the server timezone conversion is enabled, see Connector.SetServerTimezoneConversion).
+	ServerLocation() (*time.Location, error)
+	// Topology returns the hosts of the database system as provided by the database server on connect.
+	Topology() []TopologyHost
+	// Capabilities returns the features negotiated with the database server on connect (e.g. for diagnostics).
+	Capabilities() Capabilities
+	// StatementRouting returns the hosts owning the tables accessed by query (scale-out statement routing).
+	// As statements are executed most efficiently on these hosts, a connection pool may use the hosts
+	// to choose a connection for executing the statement. The result is empty if no routing information
+	// is available (e.g. single host systems). Statement routing needs to be enabled by
+	// Connector.SetStatementRouting, otherwise an error is returned.
+	StatementRouting(query string) ([]TopologyHost, error)
+	// SetSessionContext sets the session variable key to value, which can be read in SQL by the function SESSION_CONTEXT.
+	// Session variables set by SetSessionContext are lost if the connection is replaced by a new connection.
+	// For session variables set on each connection please see Connector.SetSessionVariables.
+	SetSessionContext(key, value string) error
+	// UnsetSessionContext removes the session variable key.
+	UnsetSessionContext(key string) error
+	// ExecDirect executes query without preparing it in a single round trip to the database server
+	// (e.g. dynamically built DDL statements of administration scripts). Query parameters are not supported:
+	// an error is returned if query contains parameter markers.
+	ExecDirect(ctx context.Context, query string) (driver.Result, error)
+	// QueryBatch executes independent queries without query parameters (e.g. the queries of a dashboard) pipelined:
+	// the queries are sent to the database server before the replies are read, saving a round trip per query.
+	// The rows are returned in the order of the queries. If queries of the batch failed, the error is a
+	// *QueryBatchError reporting the error of each query, and the rows of the successful queries are returned
+	// as well. All returned rows need to be closed.
+	QueryBatch(ctx context.Context, queries []string) ([]driver.Rows, error)
+	// Validate checks query by preparing it on the database server without executing it (dry run):
+	// syntax errors and invalid references to database objects (e.g. unknown tables or columns) are returned
+	// as database errors. Query parameters are not bound.
+	Validate(ctx context.Context, query string) error
+}
+
 type conn struct {
-	session *p.Session
+	connector      *Connector
+	session        *p.Session
+	serverLocation *time.Location // nil: not read yet
 }
 
 func newConn(ctx context.Context, c *Connector) (driver.Conn, error) {
+	conn, err := openConn(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if c.replacesBadConn() {
+		conn.notify(ConnEventReconnect)
+	} else {
+		conn.notify(ConnEventConnect)
+	}
+	return conn, nil
+}
+
+// openConn opens a database session and sets it up as configured by the connector
+// (see setupSession) without notifying connection events.
+func openConn(ctx context.Context, c *Connector) (*conn, error) {
 	session, err := p.NewSession(ctx, c)
 	if err != nil {
 		return nil, err
 	}
-	return &conn{session: session}, nil
+	session.SetMetadataCache(c.sharedMetadataCache())
+	conn := &conn{connector: c, session: session}
+	if err := conn.setupSession(c); err != nil {
+		session.Close()
+		return nil, err
+	}
+	return conn, nil
 }
 
 func (c *conn) Prepare(query string) (driver.Stmt, error) {
 	panic("deprecated")
 }
 
+// checkMaxStatements returns ErrMaxStatements, if the number of open statements reached the connector limit.
+func (c *conn) checkMaxStatements() error {
+	if max := c.connector.MaxStatements(); max > 0 && c.session.NumStatement() >= max {
+		return ErrMaxStatements
+	}
+	return nil
+}
+
 func (c *conn) Close() error {
+	var err error
+	if !c.session.IsBad() {
+		err = c.session.ReadPendingCommitReply() // report a failed asynchronous commit
+	}
 	c.session.Close()
-	return nil
+	if c.session.IsBad() {
+		c.connector.badConnClosed()
+	}
+	c.notify(ConnEventClose)
+	return err
 }
 
 func (c *conn) Begin() (driver.Tx, error) {
@@ -167,8 +454,21 @@ func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (tx driver.Tx
 		return nil, ErrUnsupportedIsolationLevel
 	}
 
+	lockWaitTimeout, setLockWaitTimeout := ctx.Value(lockWaitTimeoutCtxKey).(time.Duration)
+	if setLockWaitTimeout && lockWaitTimeout < 0 {
+		return nil, ErrInvalidLockWaitTimeout
+	}
+
 	done := make(chan struct{})
 	go func() {
+		// read the session lock wait timeout to be restored at the end of the transaction
+		var restoreLockWaitTimeout time.Duration
+		if setLockWaitTimeout {
+			if restoreLockWaitTimeout, err = c.sessionLockWaitTimeout(); err != nil {
+				goto done
+			}
+			setLockWaitTimeout = lockWaitTimeout != restoreLockWaitTimeout
+		}
 		// set isolation level
 		if _, err = c.ExecContext(ctx, fmt.Sprintf(isolationLevelStmt, level), nil); err != nil {
 			goto done
@@ -177,8 +477,14 @@ func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (tx driver.Tx
 		if _, err = c.ExecContext(ctx, fmt.Sprintf(accessModeStmt, readOnly[opts.ReadOnly]), nil); err != nil {
 			goto done
 		}
+		// set lock wait timeout
+		if setLockWaitTimeout {
+			if err = c.setLockWaitTimeout(lockWaitTimeout); err != nil {
+				goto done
+			}
+		}
 		c.session.SetInTx(true)
-		tx = newTx(c.session)
+		tx = newTx(c, setLockWaitTimeout, restoreLockWaitTimeout)
 	done:
 		close(done)
 	}()
@@ -191,6 +497,128 @@ func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (tx driver.Tx
 	}
 }
 
+func (c *conn) ConnectionID() int {
+	return c.session.ConnectionID()
+}
+
+func (c *conn) StatementSequenceInfo() []byte {
+	return c.session.StatementSequenceInfo()
+}
+
+func (c *conn) SetStatementSequenceInfo(info []byte) {
+	c.session.SetStatementSequenceInfo(info)
+}
+
+func (c *conn) StatementContext() StatementContext {
+	return newStatementContext(c.session.StatementContext())
+}
+
+func (c *conn) TransactionID() (int64, error) {
+	if c.session.IsBad() {
+		return 0, driver.ErrBadConn
+	}
+
+	if !c.session.InTx() {
+		return 0, ErrNoTransaction
+	}
+
+	v, ok, err := queryValue(c.session, transactionIDQuery)
+	if err != nil {
+		return 0, err
+	}
+	id, isInt := v.(int64)
+	if !ok || !isInt {
+		return 0, fmt.Errorf("invalid transaction id %v", v)
+	}
+	return id, nil
+}
+
+// setupSession applies the session settings of the connector (default schema, session variables and
+// session statements) on a new connection.
+func (c *conn) setupSession(connector *Connector) error {
+	if schema := connector.DefaultSchema(); schema != "" {
+		if _, err := c.session.ExecDirect(fmt.Sprintf(setSchemaStmt, quoteIdentifier(schema))); err != nil {
+			return err
+		}
+	}
+	if err := c.setSessionVariables(connector.SessionVariables()); err != nil {
+		return err
+	}
+	for _, stmt := range connector.SessionStatements() {
+		if _, err := c.session.ExecDirect(stmt); err != nil {
+			return fmt.Errorf("session statement %q: %s", stmt, err)
+		}
+	}
+	if connector.ServerTimezoneConversion() {
+		loc, err := c.ServerLocation()
+		if err != nil {
+			return err
+		}
+		c.session.SetTimeLocation(loc)
+	}
+	return nil
+}
+
+// setSessionVariables sets the session variables in key order.
+func (c *conn) setSessionVariables(sessionVariables map[string]string) error {
+	keys := make([]string, 0, len(sessionVariables))
+	for k := range sessionVariables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, err := c.session.ExecDirect(fmt.Sprintf(setSessionContext, quoteLiteral(k), quoteLiteral(sessionVariables[k]))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *conn) SetSessionContext(key, value string) error {
+	if c.session.IsBad() {
+		return driver.ErrBadConn
+	}
+	_, err := c.session.ExecDirect(fmt.Sprintf(setSessionContext, quoteLiteral(key), quoteLiteral(value)))
+	return err
+}
+
+func (c *conn) UnsetSessionContext(key string) error {
+	if c.session.IsBad() {
+		return driver.ErrBadConn
+	}
+	_, err := c.session.ExecDirect(fmt.Sprintf(unsetSessionContext, quoteLiteral(key)))
+	return err
+}
+
+func (c *conn) setLockWaitTimeout(timeout time.Duration) error {
+	_, err := c.session.ExecDirect(fmt.Sprintf(lockWaitTimeoutStmt, int64(timeout/time.Millisecond)))
+	return err
+}
+
+// sessionLockWaitTimeout returns the effective lock wait timeout of the session.
+func (c *conn) sessionLockWaitTimeout() (time.Duration, error) {
+	v, ok, err := queryValue(c.session, lockWaitTimeoutQuery)
+	if err != nil {
+		return 0, err
+	}
+	if !ok { // not set in session
+		return defaultLockWaitTimeout, nil
+	}
+	var s string
+	switch v := v.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid session lock wait timeout %v", v)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
 // Exec implements the database/sql/driver/Execer interface.
 // delete after go 1.9 compatibility is given up.
 func (c *conn) Exec(query string, args []driver.Value) (driver.Result, error) {
@@ -207,8 +635,24 @@ func (c *conn) ExecContext(ctx context.Context, query string, args []driver.Name
 		return nil, driver.ErrSkip //fast path not possible (prepare needed)
 	}
 
+	return c.execDirect(ctx, query)
+}
+
+// execDirect executes query without parameters in a single round trip (no prepare).
+func (c *conn) execDirect(ctx context.Context, query string) (r driver.Result, err error) {
+	query, err = c.hintQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	ql := newQueryLog(c.connector, query)
+	defer func() { ql.logExec(r, err) }()
+
 	sqltrace.Traceln(query)
 
+	ctx, cancel := withQueryTimeout(ctx, c.connector)
+	defer cancel()
+	c.session.SetClientInfo(ctxClientInfo(ctx))
+
 	done := make(chan struct{})
 	go func() {
 		r, err = c.session.ExecDirect(query)
@@ -270,12 +714,19 @@ var (
 )
 
 type tx struct {
+	conn    *conn
 	session *p.Session
+	// session lock wait timeout to be restored at the end of the transaction
+	restoreLockWaitTimeout bool
+	lockWaitTimeout        time.Duration
 }
 
-func newTx(session *p.Session) *tx {
+func newTx(conn *conn, restoreLockWaitTimeout bool, lockWaitTimeout time.Duration) *tx {
 	return &tx{
-		session: session,
+		conn:                   conn,
+		session:                conn.session,
+		restoreLockWaitTimeout: restoreLockWaitTimeout,
+		lockWaitTimeout:        lockWaitTimeout,
 	}
 }
 
@@ -284,7 +735,7 @@ func (t *tx) Commit() error {
 		return driver.ErrBadConn
 	}
 
-	return t.session.Commit()
+	return t.end(t.session.Commit())
 }
 
 func (t *tx) Rollback() error {
@@ -292,7 +743,18 @@ func (t *tx) Rollback() error {
 		return driver.ErrBadConn
 	}
 
-	return t.session.Rollback()
+	return t.end(t.session.Rollback())
+}
+
+// end restores the session lock wait timeout independent of the commit or rollback result.
+func (t *tx) end(err error) error {
+	if !t.restoreLockWaitTimeout || t.session.IsBad() {
+		return err
+	}
+	if rerr := t.conn.setLockWaitTimeout(t.lockWaitTimeout); err == nil {
+		err = rerr
+	}
+	return err
 }
 
 //statement
@@ -303,10 +765,12 @@ var (
 	_ driver.StmtExecContext   = (*stmt)(nil)
 	_ driver.StmtQueryContext  = (*stmt)(nil)
 	_ driver.NamedValueChecker = (*stmt)(nil)
+	_ StmtParamTypes           = (*stmt)(nil)
 )
 
 type stmt struct {
 	qt             p.QueryType
+	connector      *Connector
 	session        *p.Session
 	query          string
 	id             uint64
@@ -314,16 +778,25 @@ type stmt struct {
 	resultFieldSet *p.ResultFieldSet
 }
 
-func newStmt(qt p.QueryType, session *p.Session, query string, id uint64, prmFieldSet *p.ParameterFieldSet, resultFieldSet *p.ResultFieldSet) (*stmt, error) {
-	return &stmt{qt: qt, session: session, query: query, id: id, prmFieldSet: prmFieldSet, resultFieldSet: resultFieldSet}, nil
+func newStmt(qt p.QueryType, connector *Connector, session *p.Session, query string, id uint64, prmFieldSet *p.ParameterFieldSet, resultFieldSet *p.ResultFieldSet) (*stmt, error) {
+	return &stmt{qt: qt, connector: connector, session: session, query: query, id: id, prmFieldSet: prmFieldSet, resultFieldSet: resultFieldSet}, nil
 }
 
 func (s *stmt) Close() error {
 	return s.session.DropStatementID(s.id)
 }
 
+// NumInput returns -1 if the number of arguments is checked by the driver:
+// sql.Out arguments are not input parameters and a single struct argument might be bound to more than one input parameter.
 func (s *stmt) NumInput() int {
-	return s.prmFieldSet.NumInputField()
+	if s.qt == p.QtProcedureCall || isAnonymousBlock(s.query) { // sql.Out arguments are not input parameters
+		return -1
+	}
+	numField := s.prmFieldSet.NumInputField()
+	if numField > 1 { // struct argument (see bindStructArg)
+		return -1
+	}
+	return numField
 }
 
 func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
@@ -335,16 +808,34 @@ func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (r dri
 		return nil, driver.ErrBadConn
 	}
 
-	numField := s.prmFieldSet.NumInputField()
-	if len(args) != numField {
-		return nil, fmt.Errorf("invalid number of arguments %d - %d expected", len(args), numField)
+	args, err = s.bindStructArg(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNumInputArgs(s.prmFieldSet, args); err != nil {
+		return nil, err
 	}
 
+	ql := newQueryLog(s.connector, s.query)
+	defer func() { ql.logExec(r, err) }()
+
 	sqltrace.Tracef("%s %v", s.query, args)
 
+	ctx, cancel := withQueryTimeout(ctx, s.connector)
+	defer cancel()
+	s.session.SetClientInfo(ctxClientInfo(ctx))
+	s.session.SetWriteLobDone(ctx.Done())
+
 	done := make(chan struct{})
 	go func() {
-		r, err = s.session.Exec(s.id, s.prmFieldSet, args)
+		if s.qt == p.QtProcedureCall {
+			r, err = s.execCall(args)
+		} else {
+			r, err = s.session.Exec(s.id, s.prmFieldSet, args)
+		}
+		if err == nil {
+			r = &execResult{Result: r, stmtCtx: newStatementContext(s.session.StatementContext())}
+		}
 		close(done)
 	}()
 
@@ -356,6 +847,16 @@ func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (r dri
 	}
 }
 
+// checkNumInputArgs checks the number of input arguments (excluding sql.Out arguments) against the number of
+// input parameters of the statement, so that a wrong number of arguments is reported before the statement is executed.
+func checkNumInputArgs(prmFieldSet *p.ParameterFieldSet, args []driver.NamedValue) error {
+	inArgs, _ := splitOutArgs(args)
+	if numField := prmFieldSet.NumInputField(); len(inArgs) != numField {
+		return fmt.Errorf("invalid number of arguments %d - %d expected", len(inArgs), numField)
+	}
+	return nil
+}
+
 func (s *stmt) Query(args []driver.Value) (rows driver.Rows, err error) {
 	panic("deprecated")
 }
@@ -373,7 +874,32 @@ func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
 
 		return driver.ErrRemoveArgument
 	}
-	return checkNamedValue(s.prmFieldSet, nv)
+	if nv.Name == abortBulk {
+		return fmt.Errorf("abort bulk argument %d: statement %s is not a bulk insert statement", nv.Ordinal, s.query)
+	}
+	if nv.Ordinal == 1 && nv.Name == "" && isStructArg(nv.Value) { // bound by bindStructArg, if it is the only argument
+		return nil
+	}
+	if out, ok := nv.Value.(sql.Out); ok {
+		if s.qt != p.QtProcedureCall && !isAnonymousBlock(s.query) {
+			return fmt.Errorf("sql.Out argument %d: output arguments are only supported for procedure calls", nv.Ordinal)
+		}
+		if out.In { // see splitOutArgs
+			return fmt.Errorf("sql.Out argument %d: input output arguments (In: true) are not supported", nv.Ordinal)
+		}
+		return nil
+	}
+	literal := isLiteral(nv.Value)
+	if err := checkNamedValue(s.prmFieldSet, nv); err != nil {
+		return err
+	}
+	if loc := s.session.TimeLocation(); loc != nil && !literal {
+		timeArgInLocation(nv, loc)
+	}
+	if s.connector.StrictSecondPrecision() {
+		return checkSecondPrecision(s.prmFieldSet, nv)
+	}
+	return nil
 }
 
 // driver.Rows drop-in replacement if driver Query or QueryRow is used for statements that doesn't return rows
@@ -405,6 +931,10 @@ var (
 	_ driver.RowsColumnTypeNullable         = (*queryResult)(nil) // go 1.8
 	_ driver.RowsColumnTypePrecisionScale   = (*queryResult)(nil) // go 1.8
 	_ driver.RowsColumnTypeScanType         = (*queryResult)(nil) // go 1.8
+	_ RowsColumnTypeComment                 = (*queryResult)(nil)
+	_ RowsColumnTypeDefault                 = (*queryResult)(nil)
+	_ RowsStatementContext                  = (*queryResult)(nil)
+	_ RowsResult                            = (*queryResult)(nil)
 )
 
 type queryResult struct {
@@ -415,10 +945,24 @@ type queryResult struct {
 	pos            int
 	attrs          p.PartAttributes
 	columns        []string
+	comments       []*columnComment    // column comments read from the database catalog
+	defaults       []*columnDefault    // column default values read from the database catalog
+	generations    []*columnGeneration // column generation types read from the database catalog
 	lastErr        error
+	stmtCtx        StatementContext
+	rowsAffected   int64
+	fetchSize      int              // 0: connector fetch size
+	decimalFormat  DecimalFormat    // representation of decimal values
+	decimalConv    DecimalConverter // nil: representation by decimalFormat
+	numRow         int              // number of rows returned by Next
+	skip           int              // number of rows to skip after re-execution of the query
+	retry          *queryRetry      // nil: no re-execution on network errors
+	scrollable     bool             // resultset opened with a scrollable cursor
+	queryLog       *queryLog        // nil: no query logging
+	timeLocation   *time.Location   // nil: time values in UTC
 }
 
-func newQueryResult(session *p.Session, id uint64, resultFieldSet *p.ResultFieldSet, fieldValues *p.FieldValues, attrs p.PartAttributes) (driver.Rows, error) {
+func newQueryResult(session *p.Session, id uint64, resultFieldSet *p.ResultFieldSet, fieldValues *p.FieldValues, attrs p.PartAttributes, fetchSize int, scrollable bool, decimalFormat DecimalFormat, decimalConv DecimalConverter, retry *queryRetry) (driver.Rows, error) {
 	columns := make([]string, resultFieldSet.NumField())
 	for i := 0; i < len(columns); i++ {
 		columns[i] = resultFieldSet.Field(i).Name()
@@ -431,14 +975,54 @@ func newQueryResult(session *p.Session, id uint64, resultFieldSet *p.ResultField
 		fieldValues:    fieldValues,
 		attrs:          attrs,
 		columns:        columns,
+		stmtCtx:        newStatementContext(session.StatementContext()),
+		rowsAffected:   session.RowsAffected(),
+		fetchSize:      fetchSize,
+		scrollable:     scrollable,
+		decimalFormat:  decimalFormat,
+		decimalConv:    decimalConv,
+		retry:          retry,
+		timeLocation:   session.TimeLocation(),
 	}, nil
 }
 
+// queryValue executes a query without parameters and returns the first value of the resultset.
+// The ok value is false if the resultset is empty.
+func queryValue(session *p.Session, query string) (driver.Value, bool, error) {
+
+	sqltrace.Traceln(query)
+
+	id, resultFieldSet, fieldValues, attrs, err := session.QueryDirect(query)
+	if err != nil {
+		return nil, false, err
+	}
+	if !attrs.ResultsetClosed() {
+		defer session.CloseResultsetID(id)
+	}
+
+	if fieldValues.NumRow() == 0 {
+		return nil, false, nil
+	}
+
+	dest := make([]driver.Value, resultFieldSet.NumField())
+	fieldValues.Row(0, dest)
+	return dest[0], true, nil
+}
+
 func (r *queryResult) Columns() []string {
 	return r.columns
 }
 
 func (r *queryResult) Close() error {
+	defer r.queryLog.log(false, int64(r.numRow), r.lastErr)
+	if r.retry != nil {
+		defer r.retry.close()
+	}
+	defer func() { // rows are closed: field values can be reused
+		r.fieldValues.Release()
+		r.fieldValues = nil
+	}()
+
 	// if lastError is set, attrs are nil
 	if r.lastErr != nil {
 		return r.lastErr
@@ -455,29 +1039,125 @@ func (r *queryResult) Next(dest []driver.Value) error {
 		return driver.ErrBadConn
 	}
 
-	if r.pos >= r.fieldValues.NumRow() {
-		if r.attrs.LastPacket() {
-			return io.EOF
+	for {
+		// continue fetching until rows are available (parts might not contain any rows)
+		for r.pos >= r.fieldValues.NumRow() {
+			if r.attrs.LastPacket() || r.attrs.ResultsetClosed() { // resultset complete: do not fetch
+				return io.EOF
+			}
+			if err := r.fetchNext(); err != nil {
+				return err
+			}
+		}
+
+		if r.skip == 0 {
+			break
 		}
+		// query was re-executed: skip rows returned before
+		n := r.fieldValues.NumRow() - r.pos
+		if n > r.skip {
+			n = r.skip
+		}
+		r.pos += n
+		r.skip -= n
+	}
 
-		var err error
+	idx := r.pos
+	r.pos++
+	r.numRow++
+	return r.readRow(idx, dest)
+}
 
-		if r.attrs, err = r.session.FetchNext(r.id, r.resultFieldSet, r.fieldValues); err != nil {
-			r.lastErr = err //fieldValues and attrs are nil
+// formatDecimals converts the decimal values of a row to strings (see DecimalFormat) or by the decimal converter
+// and the fixed size decimal values into the representation of decimal values (see fixedValue).
+func (r *queryResult) formatDecimals(dest []driver.Value) error {
+	for i, v := range dest {
+		if x, ok := v.(*p.Fixed); ok {
+			_, scale, _ := r.resultFieldSet.Field(i).TypePrecisionScale()
+			var err error
+			if dest[i], err = fixedValue(x, int(scale), r.decimalFormat, r.decimalConv); err != nil {
+				return err
+			}
+			continue
+		}
+		b, ok := v.([]byte)
+		if !ok || (r.decimalFormat == DecimalBinary && r.decimalConv == nil) { // null value or no decimal conversion
+			continue
+		}
+		_, scale, ok := r.resultFieldSet.Field(i).TypePrecisionScale()
+		if !ok { // no decimal field
+			continue
+		}
+		if r.decimalConv != nil {
+			var err error
+			if dest[i], err = convertDecimal(r.decimalConv, b); err != nil {
+				return err
+			}
+			continue
+		}
+		if r.decimalFormat == DecimalTrimmed || scale == floatingDecimalScale {
+			scale = -1
+		}
+		s, err := decimalString(b, int(scale))
+		if err != nil {
 			return err
 		}
+		dest[i] = s
+	}
+	return nil
+}
 
-		if r.attrs.NoRows() {
-			return io.EOF
+// decodeArrayDecimals converts the decimal elements of array values to *Decimal values, to strings (see DecimalFormat)
+// or by the decimal converter. As the scale of array elements is not known, DecimalScaled formats like DecimalTrimmed.
+func (r *queryResult) decodeArrayDecimals(dest []driver.Value) error {
+	for _, v := range dest {
+		values, ok := v.([]interface{})
+		if !ok {
+			continue
+		}
+		for i, e := range values {
+			b, ok := e.(p.ArrayDecimal)
+			if !ok {
+				continue
+			}
+			var err error
+			switch {
+			case r.decimalConv != nil:
+				values[i], err = convertDecimal(r.decimalConv, b)
+			case r.decimalFormat == DecimalBinary:
+				d := new(Decimal)
+				err = d.Scan([]byte(b))
+				values[i] = d
+			default:
+				values[i], err = decimalString(b, -1)
+			}
+			if err != nil {
+				return err
+			}
 		}
+	}
+	return nil
+}
 
-		r.pos = 0
+func (r *queryResult) fetchNext() error {
+	var err error
 
+	if r.attrs, err = r.session.FetchNext(r.id, r.fetchSize, r.scrollable, r.resultFieldSet, r.fieldValues); err != nil {
+		// network error: re-execute query if enabled
+		if r.retry != nil && r.session.IsBad() {
+			if err = r.retry.reexecute(r); err == nil {
+				return nil
+			}
+		}
+		r.lastErr = err //fieldValues and attrs are nil
+		return err
 	}
 
-	r.fieldValues.Row(r.pos, dest)
-	r.pos++
+	if r.attrs.NoRows() {
+		return io.EOF
+	}
 
+	r.pos = 0
 	return nil
 }
 
@@ -499,6 +1179,7 @@ func (r *queryResult) ColumnTypeNullable(idx int) (bool, bool) {
 
 var (
 	scanTypeUnknown  = reflect.TypeOf(new(interface{})).Elem()
+	scanTypeBoolean  = reflect.TypeOf(false)
 	scanTypeTinyint  = reflect.TypeOf(uint8(0))
 	scanTypeSmallint = reflect.TypeOf(int16(0))
 	scanTypeInteger  = reflect.TypeOf(int32(0))
@@ -510,12 +1191,15 @@ var (
 	scanTypeBytes    = reflect.TypeOf([]byte{})
 	scanTypeDecimal  = reflect.TypeOf(Decimal{})
 	scanTypeLob      = reflect.TypeOf(Lob{})
+	scanTypeArray    = reflect.TypeOf([]interface{}{})
 )
 
 func (r *queryResult) ColumnTypeScanType(idx int) reflect.Type {
 	switch r.resultFieldSet.Field(idx).TypeCode().DataType() {
 	default:
 		return scanTypeUnknown
+	case p.DtBoolean:
+		return scanTypeBoolean
 	case p.DtTinyint:
 		return scanTypeTinyint
 	case p.DtSmallint:
@@ -538,5 +1222,7 @@ func (r *queryResult) ColumnTypeScanType(idx int) reflect.Type {
 		return scanTypeBytes
 	case p.DtLob:
 		return scanTypeLob
+	case p.DtArray:
+		return scanTypeArray
 	}
 }
@@ -35,6 +35,18 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (stmt driver.St
 		return nil, driver.ErrBadConn
 	}
 
+	if err := c.checkMaxStatements(); err != nil {
+		return nil, err
+	}
+
+	query, err = c.hintQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withPrepareTimeout(ctx, c.connector)
+	defer cancel()
+
 	done := make(chan struct{})
 	go func() {
 		var (
@@ -50,9 +62,10 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (stmt driver.St
 		select {
 		default:
 		case <-ctx.Done():
+			c.session.DropStatementID(id) // release statement of canceled prepare
 			return
 		}
-		stmt, err = newStmt(qt, c.session, query, id, prmFieldSet, resultFieldSet)
+		stmt, err = newStmt(qt, c.connector, c.session, query, id, prmFieldSet, resultFieldSet)
 	done:
 		close(done)
 	}()
@@ -65,6 +78,28 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (stmt driver.St
 	}
 }
 
+func (c *conn) Validate(ctx context.Context, query string) (err error) {
+	if c.session.IsBad() {
+		return driver.ErrBadConn
+	}
+
+	ctx, cancel := withPrepareTimeout(ctx, c.connector)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		err = c.session.Validate(query)
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return err
+	}
+}
+
 // QueryContext implements the database/sql/driver/QueryerContext interface.
 func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
 	if c.session.IsBad() {
@@ -81,9 +116,27 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 	//	if checkCallProcedure(query) {
 	//		return nil, driver.ErrSkip
 	//	}
+	// anonymous blocks may return several implicit resultsets
+	if isAnonymousBlock(query) {
+		return nil, driver.ErrSkip
+	}
+
+	query, err = c.hintQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	ql := newQueryLog(c.connector, query)
+	defer func() { ql.logQuery(rows, err) }()
 
 	sqltrace.Traceln(query)
 
+	ctx, cancel := withQueryTimeout(ctx, c.connector)
+	defer cancel()
+	c.session.SetClientInfo(ctxClientInfo(ctx))
+	c.session.SetRowDecoder(ctxRowDecoder(ctx))
+	c.session.SetHoldCursor(ctxHoldCursor(ctx))
+	c.session.SetScrollableCursor(ctxScrollableCursor(ctx))
+
 	done := make(chan struct{})
 	go func() {
 		var (
@@ -102,9 +155,10 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 			return
 		}
 		if id == 0 { // non select query
+			fieldValues.Release()
 			rows = noResult
 		} else {
-			rows, err = newQueryResult(c.session, id, resultFieldSet, fieldValues, attributes)
+			rows, err = newQueryResult(c.session, id, resultFieldSet, fieldValues, attributes, ctxFetchSize(ctx), ctxScrollableCursor(ctx), c.connector.DecimalFormat(), c.connector.DecimalConverter(), newQueryRetry(ctx, c.connector, c.session, query, nil))
 		}
 	done:
 		close(done)
@@ -126,9 +180,31 @@ func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows
 		return nil, driver.ErrBadConn
 	}
 
+	args, err = s.bindStructArg(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNumInputArgs(s.prmFieldSet, args); err != nil {
+		return nil, err
+	}
+
+	ql := newQueryLog(s.connector, s.query)
+	defer func() { ql.logQuery(rows, err) }()
+
+	ctx, cancel := withQueryTimeout(ctx, s.connector)
+	defer cancel()
+	s.session.SetClientInfo(ctxClientInfo(ctx))
+	s.session.SetRowDecoder(ctxRowDecoder(ctx))
+	s.session.SetHoldCursor(ctxHoldCursor(ctx))
+	s.session.SetScrollableCursor(ctxScrollableCursor(ctx))
+
 	done := make(chan struct{})
 	go func() {
-		rows, err = s.defaultQuery(ctx, args)
+		if isAnonymousBlock(s.query) {
+			rows, err = s.anonymousBlock(ctx, args)
+		} else {
+			rows, err = s.defaultQuery(ctx, args)
+		}
 		close(done)
 	}()
 
@@ -144,7 +220,7 @@ func (s *stmt) defaultQuery(ctx context.Context, args []driver.NamedValue) (driv
 
 	sqltrace.Tracef("%s %v", s.query, args)
 
-	rid, values, attributes, err := s.session.Query(s.id, s.prmFieldSet, s.resultFieldSet, args)
+	rid, resultFieldSet, values, attributes, err := s.session.Query(s.id, s.prmFieldSet, s.resultFieldSet, args)
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +232,8 @@ func (s *stmt) defaultQuery(ctx context.Context, args []driver.NamedValue) (driv
 	}
 
 	if rid == 0 { // non select query
+		values.Release()
 		return noResult, nil
 	}
-	return newQueryResult(s.session, rid, s.resultFieldSet, values, attributes)
+	return newQueryResult(s.session, rid, resultFieldSet, values, attributes, ctxFetchSize(ctx), ctxScrollableCursor(ctx), s.connector.DecimalFormat(), s.connector.DecimalConverter(), newQueryRetry(ctx, s.connector, s.session, s.query, args))
 }
@@ -19,6 +19,7 @@ limitations under the License.
 package driver
 
 import (
+	"context"
 	"database/sql/driver"
 )
 
@@ -30,3 +31,17 @@ var _ driver.DriverContext = (*hdbDrv)(nil)
 func (d *hdbDrv) OpenConnector(dsn string) (driver.Connector, error) {
 	return NewDSNConnector(dsn)
 }
+
+// conn
+
+// check if conn implements all required interfaces
+var _ driver.SessionResetter = (*conn)(nil)
+
+// ResetSession implements the driver.SessionResetter interface. The reply of an asynchronous commit is read
+// before the connection is reused, so that a commit error is not reported by an unrelated statement.
+func (c *conn) ResetSession(ctx context.Context) error {
+	if c.session.IsBad() {
+		return driver.ErrBadConn
+	}
+	return c.session.ReadPendingCommitReply()
+}
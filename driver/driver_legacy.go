@@ -54,9 +54,21 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (stmt driver.St
 		return nil, driver.ErrBadConn
 	}
 
+	if err := c.checkMaxStatements(); err != nil {
+		return nil, err
+	}
+
+	prepareQuery, bulkInsert := checkBulkInsert(query)
+	prepareQuery, err = c.hintQuery(ctx, prepareQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withPrepareTimeout(ctx, c.connector)
+	defer cancel()
+
 	done := make(chan struct{})
 	go func() {
-		prepareQuery, bulkInsert := checkBulkInsert(query)
 		var (
 			qt             p.QueryType
 			id             uint64
@@ -70,12 +82,13 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (stmt driver.St
 		select {
 		default:
 		case <-ctx.Done():
+			c.session.DropStatementID(id) // release statement of canceled prepare
 			return
 		}
 		if bulkInsert {
-			stmt, err = newBulkInsertStmt(c.session, prepareQuery, id, prmFieldSet)
+			stmt, err = newBulkInsertStmt(c.connector, c.session, prepareQuery, id, prmFieldSet)
 		} else {
-			stmt, err = newStmt(qt, c.session, prepareQuery, id, prmFieldSet, resultFieldSet)
+			stmt, err = newStmt(qt, c.connector, c.session, prepareQuery, id, prmFieldSet, resultFieldSet)
 		}
 	done:
 		close(done)
@@ -89,6 +102,29 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (stmt driver.St
 	}
 }
 
+func (c *conn) Validate(ctx context.Context, query string) (err error) {
+	if c.session.IsBad() {
+		return driver.ErrBadConn
+	}
+
+	ctx, cancel := withPrepareTimeout(ctx, c.connector)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		prepareQuery, _ := checkBulkInsert(query)
+		err = c.session.Validate(prepareQuery)
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return err
+	}
+}
+
 // QueryContext implements the database/sql/driver/QueryerContext interface.
 func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
 	if c.session.IsBad() {
@@ -105,6 +141,10 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 	if checkCallProcedure(query) {
 		return nil, driver.ErrSkip
 	}
+	// anonymous blocks may return several implicit resultsets
+	if isAnonymousBlock(query) {
+		return nil, driver.ErrSkip
+	}
 
 	sqltrace.Traceln(query)
 
@@ -117,6 +157,20 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 		return r.tableRows(int(idx))
 	}
 
+	query, err = c.hintQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	ql := newQueryLog(c.connector, query)
+	defer func() { ql.logQuery(rows, err) }()
+
+	ctx, cancel := withQueryTimeout(ctx, c.connector)
+	defer cancel()
+	c.session.SetClientInfo(ctxClientInfo(ctx))
+	c.session.SetRowDecoder(ctxRowDecoder(ctx))
+	c.session.SetHoldCursor(ctxHoldCursor(ctx))
+	c.session.SetScrollableCursor(ctxScrollableCursor(ctx))
+
 	done := make(chan struct{})
 	go func() {
 		var (
@@ -135,9 +189,10 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 			return
 		}
 		if id == 0 { // non select query
+			fieldValues.Release()
 			rows = noResult
 		} else {
-			rows, err = newQueryResult(c.session, id, resultFieldSet, fieldValues, attributes)
+			rows, err = newQueryResult(c.session, id, resultFieldSet, fieldValues, attributes, ctxFetchSize(ctx), ctxScrollableCursor(ctx), c.connector.DecimalFormat(), c.connector.DecimalConverter(), newQueryRetry(ctx, c.connector, c.session, query, nil))
 		}
 	done:
 		close(done)
@@ -159,12 +214,32 @@ func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows
 		return nil, driver.ErrBadConn
 	}
 
+	args, err = s.bindStructArg(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNumInputArgs(s.prmFieldSet, args); err != nil {
+		return nil, err
+	}
+
+	ql := newQueryLog(s.connector, s.query)
+	defer func() { ql.logQuery(rows, err) }()
+
+	ctx, cancel := withQueryTimeout(ctx, s.connector)
+	defer cancel()
+	s.session.SetClientInfo(ctxClientInfo(ctx))
+	s.session.SetRowDecoder(ctxRowDecoder(ctx))
+	s.session.SetHoldCursor(ctxHoldCursor(ctx))
+	s.session.SetScrollableCursor(ctxScrollableCursor(ctx))
+
 	done := make(chan struct{})
 	go func() {
-		switch s.qt {
+		switch {
 		default:
 			rows, err = s.defaultQuery(ctx, args)
-		case p.QtProcedureCall:
+		case isAnonymousBlock(s.query):
+			rows, err = s.anonymousBlock(ctx, args)
+		case s.qt == p.QtProcedureCall:
 			rows, err = s.procedureCall(ctx, args)
 		}
 		close(done)
@@ -182,7 +257,7 @@ func (s *stmt) defaultQuery(ctx context.Context, args []driver.NamedValue) (driv
 
 	sqltrace.Tracef("%s %v", s.query, args)
 
-	rid, values, attributes, err := s.session.Query(s.id, s.prmFieldSet, s.resultFieldSet, args)
+	rid, resultFieldSet, values, attributes, err := s.session.Query(s.id, s.prmFieldSet, s.resultFieldSet, args)
 	if err != nil {
 		return nil, err
 	}
@@ -194,16 +269,20 @@ func (s *stmt) defaultQuery(ctx context.Context, args []driver.NamedValue) (driv
 	}
 
 	if rid == 0 { // non select query
+		values.Release()
 		return noResult, nil
 	}
-	return newQueryResult(s.session, rid, s.resultFieldSet, values, attributes)
+	return newQueryResult(s.session, rid, resultFieldSet, values, attributes, ctxFetchSize(ctx), ctxScrollableCursor(ctx), s.connector.DecimalFormat(), s.connector.DecimalConverter(), newQueryRetry(ctx, s.connector, s.session, s.query, args))
 }
 
 func (s *stmt) procedureCall(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
 
 	sqltrace.Tracef("%s %v", s.query, args)
 
-	fieldValues, tableResults, err := s.session.Call(s.id, s.prmFieldSet, args)
+	inArgs, outArgs := splitOutArgs(args)
+
+	s.session.SetWriteLobDone(ctx.Done())
+	fieldValues, tableResults, err := s.session.Call(s.id, s.prmFieldSet, inArgs)
 	if err != nil {
 		return nil, err
 	}
@@ -214,7 +293,11 @@ func (s *stmt) procedureCall(ctx context.Context, args []driver.NamedValue) (dri
 		return nil, ctx.Err()
 	}
 
-	return newProcedureCallResult(s.session, s.prmFieldSet, fieldValues, tableResults)
+	if err := assignOutArgs(s.prmFieldSet, fieldValues, outArgs, s.session.TimeLocation()); err != nil {
+		return nil, err
+	}
+
+	return newProcedureCallResult(s.session, s.prmFieldSet, fieldValues, tableResults, s.connector.DecimalFormat(), s.connector.DecimalConverter())
 }
 
 // bulk insert statement
@@ -225,19 +308,22 @@ var (
 	_ driver.StmtExecContext   = (*bulkInsertStmt)(nil)
 	_ driver.StmtQueryContext  = (*bulkInsertStmt)(nil)
 	_ driver.NamedValueChecker = (*bulkInsertStmt)(nil)
+	_ StmtParamTypes           = (*bulkInsertStmt)(nil)
 )
 
 type bulkInsertStmt struct {
+	connector   *Connector
 	session     *p.Session
 	query       string
 	id          uint64
 	prmFieldSet *p.ParameterFieldSet
 	numArg      int
 	args        []driver.NamedValue
+	numSent     int64 // number of rows sent to the database
 }
 
-func newBulkInsertStmt(session *p.Session, query string, id uint64, prmFieldSet *p.ParameterFieldSet) (*bulkInsertStmt, error) {
-	return &bulkInsertStmt{session: session, query: query, id: id, prmFieldSet: prmFieldSet, args: make([]driver.NamedValue, 0)}, nil
+func newBulkInsertStmt(connector *Connector, session *p.Session, query string, id uint64, prmFieldSet *p.ParameterFieldSet) (*bulkInsertStmt, error) {
+	return &bulkInsertStmt{connector: connector, session: session, query: query, id: id, prmFieldSet: prmFieldSet, args: make([]driver.NamedValue, 0)}, nil
 }
 
 func (s *bulkInsertStmt) Close() error {
@@ -248,6 +334,11 @@ func (s *bulkInsertStmt) NumInput() int {
 	return -1
 }
 
+// ParamTypes implements the StmtParamTypes interface.
+func (s *bulkInsertStmt) ParamTypes() []ParamType {
+	return newParamTypes(s.prmFieldSet)
+}
+
 func (s *bulkInsertStmt) Exec(args []driver.Value) (driver.Result, error) {
 	panic("deprecated")
 }
@@ -260,11 +351,16 @@ func (s *bulkInsertStmt) ExecContext(ctx context.Context, args []driver.NamedVal
 
 	sqltrace.Tracef("%s %v", s.query, args)
 
+	s.session.SetWriteLobDone(ctx.Done())
+
 	done := make(chan struct{})
 	go func() {
-		if args == nil || len(args) == 0 {
+		switch {
+		case args == nil || len(args) == 0:
 			r, err = s.execFlush()
-		} else {
+		case len(args) == 1 && args[0].Name == abortBulk:
+			r, err = s.execAbort()
+		default:
 			r, err = s.execBuffer(args)
 		}
 		close(done)
@@ -287,16 +383,42 @@ func (s *bulkInsertStmt) execFlush() (driver.Result, error) {
 	sqltrace.Traceln("execFlush")
 
 	result, err := s.session.Exec(s.id, s.prmFieldSet, s.args)
+	if err == nil {
+		s.numSent += int64(s.numArg)
+	}
 	s.args = s.args[:0]
 	s.numArg = 0
 	return result, err
 }
 
+// execAbort discards the buffered rows and rolls back the enclosing transaction.
+func (s *bulkInsertStmt) execAbort() (driver.Result, error) {
+
+	sqltrace.Traceln("execAbort")
+
+	numSent := s.numSent
+	s.args = s.args[:0]
+	s.numArg = 0
+	s.numSent = 0
+
+	if !s.session.InTx() {
+		if numSent == 0 {
+			return driver.RowsAffected(0), nil
+		}
+		return nil, fmt.Errorf("bulk insert aborted outside of transaction: %d rows already committed", numSent)
+	}
+
+	// the enclosing transaction (sql.Tx) is still open: a commit of the transaction fails
+	if err := s.session.AbortTx(fmt.Errorf("bulk insert aborted: %d rows rolled back", numSent)); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(numSent), nil
+}
+
 func (s *bulkInsertStmt) execBuffer(args []driver.NamedValue) (driver.Result, error) {
 
-	numField := s.prmFieldSet.NumInputField()
-	if len(args) != numField {
-		return nil, fmt.Errorf("invalid number of arguments %d - %d expected", len(args), numField)
+	if err := checkNumInputArgs(s.prmFieldSet, args); err != nil {
+		return nil, err
 	}
 
 	var result driver.Result = driver.ResultNoRows
@@ -326,7 +448,20 @@ func (s *bulkInsertStmt) QueryContext(ctx context.Context, args []driver.NamedVa
 
 // CheckNamedValue implements NamedValueChecker interface.
 func (s *bulkInsertStmt) CheckNamedValue(nv *driver.NamedValue) error {
-	return checkNamedValue(s.prmFieldSet, nv)
+	if nv.Name == abortBulk {
+		return nil
+	}
+	literal := isLiteral(nv.Value)
+	if err := checkNamedValue(s.prmFieldSet, nv); err != nil {
+		return err
+	}
+	if loc := s.session.TimeLocation(); loc != nil && !literal {
+		timeArgInLocation(nv, loc)
+	}
+	if s.connector.StrictSecondPrecision() {
+		return checkSecondPrecision(s.prmFieldSet, nv)
+	}
+	return nil
 }
 
 //call result store
@@ -389,7 +524,10 @@ var procedureCallResultStore = new(callResultStore)
 //procedure call result
 
 //  check if procedureCallResult implements all required interfaces
-var _ driver.Rows = (*procedureCallResult)(nil)
+var (
+	_ driver.Rows              = (*procedureCallResult)(nil)
+	_ driver.RowsNextResultSet = (*procedureCallResult)(nil)
+)
 
 type procedureCallResult struct {
 	id          uint64
@@ -399,9 +537,10 @@ type procedureCallResult struct {
 	_tableRows  []driver.Rows
 	columns     []string
 	eof         error
+	resultSet   int // 0: output parameters, i > 0: table output parameter i-1
 }
 
-func newProcedureCallResult(session *p.Session, prmFieldSet *p.ParameterFieldSet, fieldValues *p.FieldValues, tableResults []*p.TableResult) (driver.Rows, error) {
+func newProcedureCallResult(session *p.Session, prmFieldSet *p.ParameterFieldSet, fieldValues *p.FieldValues, tableResults []*p.TableResult, decimalFormat DecimalFormat, decimalConv DecimalConverter) (driver.Rows, error) {
 
 	fieldIdx := prmFieldSet.NumOutputField()
 	columns := make([]string, fieldIdx+len(tableResults))
@@ -414,7 +553,7 @@ func newProcedureCallResult(session *p.Session, prmFieldSet *p.ParameterFieldSet
 	for i, tableResult := range tableResults {
 		var err error
 
-		if tableRows[i], err = newQueryResult(session, tableResult.ID(), tableResult.FieldSet(), tableResult.FieldValues(), tableResult.Attrs()); err != nil {
+		if tableRows[i], err = newQueryResult(session, tableResult.ID(), tableResult.FieldSet(), tableResult.FieldValues(), tableResult.Attrs(), 0, false, decimalFormat, decimalConv, nil); err != nil {
 			return nil, err
 		}
 
@@ -437,9 +576,28 @@ func newProcedureCallResult(session *p.Session, prmFieldSet *p.ParameterFieldSet
 }
 
 func (r *procedureCallResult) Columns() []string {
+	if r.resultSet != 0 {
+		return r._tableRows[r.resultSet-1].Columns()
+	}
 	return r.columns
 }
 
+// HasNextResultSet implements the driver.RowsNextResultSet interface.
+// The table output parameters are provided as result sets in the order returned by the database server,
+// which is the declaration order of the procedure parameters.
+func (r *procedureCallResult) HasNextResultSet() bool {
+	return r.resultSet < len(r._tableRows)
+}
+
+// NextResultSet implements the driver.RowsNextResultSet interface.
+func (r *procedureCallResult) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+	r.resultSet++
+	return nil
+}
+
 func (r *procedureCallResult) Close() error {
 	procedureCallResultStore.del(r.id)
 	return nil
@@ -450,6 +608,10 @@ func (r *procedureCallResult) Next(dest []driver.Value) error {
 		return driver.ErrBadConn
 	}
 
+	if r.resultSet != 0 {
+		return r._tableRows[r.resultSet-1].Next(dest)
+	}
+
 	if r.eof != nil {
 		return r.eof
 	}
@@ -461,6 +623,12 @@ func (r *procedureCallResult) Next(dest []driver.Value) error {
 
 	if r.fieldValues.NumRow() != 0 {
 		r.fieldValues.Row(0, dest)
+		if loc := r.session.TimeLocation(); loc != nil {
+			timesInLocation(dest, loc)
+		}
+		if err := outFixedValues(r.prmFieldSet, dest); err != nil {
+			return err
+		}
 	}
 
 	i := r.prmFieldSet.NumOutputField()
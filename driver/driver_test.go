@@ -19,8 +19,12 @@ package driver
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"io"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestPing(t *testing.T) {
@@ -219,3 +223,407 @@ func checkAffectedRows(t *testing.T, result sql.Result, rowsExpected int64) {
 		t.Fatalf("rows affected %d - expected %d", rowsAffected, rowsExpected)
 	}
 }
+
+func TestDropStatement(t *testing.T) {
+	const numStmt = 10
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	marker := RandomIdentifier("drop_")
+	query := fmt.Sprintf("select '%s' from dummy where 1 = ?", marker)
+
+	for i := 0; i < numStmt; i++ {
+		stmt, err := conn.PrepareContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// closing statements does not need a round trip
+		if err := stmt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// statements are dropped before executing the next command
+	var count int
+	if err := conn.QueryRowContext(ctx, "select count(*) from sys.m_prepared_statements where connection_id = current_connection and statement_string = ?", query).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatalf("number of prepared statements %d - expected %d", count, 0)
+	}
+}
+
+func TestEmptyResultset(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("empty_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, s nvarchar(20))", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select i, s from %s.%s", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	// metadata available for empty resultset
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(columns) != 2 || columns[0] != "I" || columns[1] != "S" {
+		t.Fatalf("columns %v - expected %v", columns, []string{"I", "S"})
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types[1].DatabaseTypeName() != "NVARCHAR" {
+		t.Fatalf("database type name %s - expected %s", types[1].DatabaseTypeName(), "NVARCHAR")
+	}
+
+	if rows.Next() {
+		t.Fatal("no rows expected")
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDDLRowsAffected(t *testing.T) {
+	const numRow = 5
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	checkRowsAffected := func(result sql.Result, expected int64) {
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rowsAffected != expected {
+			t.Fatalf("rows affected %d - expected %d", rowsAffected, expected)
+		}
+	}
+
+	table := RandomIdentifier("ddl_")
+
+	// plain ddl
+	result, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer)", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRowsAffected(result, 0)
+
+	for i := 0; i < numRow; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?)", TestSchema, table), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ddl after dml statement (rows affected must not be taken from previous statement)
+	result, err = db.Exec(fmt.Sprintf("alter table %s.%s add (j integer)", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRowsAffected(result, 0)
+
+	// create table as select
+	result, err = db.Exec(fmt.Sprintf("create table %s.%s as (select * from %s.%s)", TestSchema, RandomIdentifier("ctas_"), TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRowsAffected(result, numRow)
+}
+
+func TestWithFetchSize(t *testing.T) {
+	ctx := context.Background()
+
+	if fetchSize := ctxFetchSize(ctx); fetchSize != 0 {
+		t.Fatalf("fetch size %d - expected %d", fetchSize, 0)
+	}
+
+	tests := []struct {
+		fetchSize, expected int
+	}{
+		{0, minFetchSize},
+		{10000, 10000},
+		{-1, minFetchSize},
+		{maxFetchSize, maxFetchSize},
+	}
+	for _, test := range tests {
+		if fetchSize := ctxFetchSize(WithFetchSize(ctx, test.fetchSize)); fetchSize != test.expected {
+			t.Fatalf("fetch size %d - expected %d", fetchSize, test.expected)
+		}
+	}
+}
+
+func TestQueryTimeoutCaller(t *testing.T) {
+	connector := newConnector()
+	connector.SetQueryTimeout(60)
+
+	ctx := context.Background()
+	queryCtx, cancel := withQueryTimeout(ctx, connector)
+	cancel() // query execution done: rows are fetched afterwards
+
+	if queryCtx.Err() == nil {
+		t.Fatal("query timeout context not canceled")
+	}
+	if caller := ctxCaller(queryCtx); caller != ctx || caller.Err() != nil {
+		t.Fatalf("caller context %v - expected %v", caller, ctx)
+	}
+	if caller := ctxCaller(ctx); caller != ctx {
+		t.Fatalf("caller context %v - expected %v", caller, ctx)
+	}
+}
+
+func TestWithClientInfo(t *testing.T) {
+	ctx := context.Background()
+
+	if values := ctxClientInfo(ctx); values != nil {
+		t.Fatalf("client info %v - expected nil", values)
+	}
+
+	ctx1 := WithClientInfo(ctx, "APPLICATION", "app")
+	ctx2 := WithClientInfo(ctx1, "TRACEID", "4711")
+
+	if values, expected := ctxClientInfo(ctx1), map[string]string{"APPLICATION": "app"}; !reflect.DeepEqual(values, expected) {
+		t.Fatalf("client info %v - expected %v", values, expected)
+	}
+	if values, expected := ctxClientInfo(ctx2), map[string]string{"APPLICATION": "app", "TRACEID": "4711"}; !reflect.DeepEqual(values, expected) {
+		t.Fatalf("client info %v - expected %v", values, expected)
+	}
+}
+
+func TestHoldCursor(t *testing.T) {
+	const numRow = 10
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("holdCursor_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < numRow; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?)", TestSchema, table), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// fetch one row per round trip and commit (autocommit) after each row
+	rows, err := conn.QueryContext(WithFetchSize(WithHoldCursor(ctx), 1), fmt.Sprintf("select i from %s.%s order by i", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("update %s.%s set i = i where i = ?", TestSchema, table), n); err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != numRow {
+		t.Fatalf("number of rows %d - expected %d", n, numRow)
+	}
+
+	if !ctxHoldCursor(WithHoldCursor(ctx)) || ctxHoldCursor(ctx) {
+		t.Fatal("invalid cursor holdability of context")
+	}
+}
+
+func TestScrollableCursor(t *testing.T) {
+	const numRow = 10
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("scrollableCursor_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < numRow; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?)", TestSchema, table), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	query := fmt.Sprintf("select i from %s.%s order by i", TestSchema, table)
+	dest := make([]driver.Value, 1)
+
+	// forward only cursor
+	rows, err := conn.(driver.QueryerContext).QueryContext(context.Background(), query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.(RowsScrollable).FetchLast(dest); err != ErrCursorNotScrollable {
+		t.Fatalf("error %v - expected %v", err, ErrCursorNotScrollable)
+	}
+	rows.Close()
+
+	// scrollable cursor: iterate from last to first row (fetch size > 1: first rows are read from client buffer)
+	rows, err = conn.(driver.QueryerContext).QueryContext(WithFetchSize(WithScrollableCursor(context.Background()), 4), query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	scrollRows := rows.(RowsScrollable)
+
+	if err := scrollRows.Prev(dest); err != io.EOF { // no current row
+		t.Fatalf("error %v - expected %v", err, io.EOF)
+	}
+	if err := scrollRows.FetchLast(dest); err != nil {
+		t.Fatal(err)
+	}
+	i := numRow - 1
+	for {
+		if dest[0].(int32) != int32(i) {
+			t.Fatalf("value %v - expected %d", dest[0], i)
+		}
+		err := scrollRows.Prev(dest)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		i--
+	}
+	if i != 0 {
+		t.Fatalf("last row %d - expected %d", i, 0)
+	}
+
+	// absolute positioning
+	for _, d := range []struct{ pos, i int }{{3, 2}, {-2, numRow - 2}, {1, 0}} {
+		if err := scrollRows.FetchAbsolute(d.pos, dest); err != nil {
+			t.Fatal(err)
+		}
+		if dest[0].(int32) != int32(d.i) {
+			t.Fatalf("position %d: value %v - expected %d", d.pos, dest[0], d.i)
+		}
+	}
+	if err := scrollRows.FetchAbsolute(numRow+1, dest); err != io.EOF {
+		t.Fatalf("error %v - expected %v", err, io.EOF)
+	}
+}
+
+func TestWithResultsetType(t *testing.T) {
+	ctx := context.Background()
+	if ctxScrollableCursor(ctx) {
+		t.Fatal("forward only resultset expected by default")
+	}
+	ctx = WithScrollableCursor(ctx)
+	if !ctxScrollableCursor(ctx) {
+		t.Fatal("scrollable resultset expected")
+	}
+	if ctxScrollableCursor(WithResultsetType(ctx, ResultsetForwardOnly)) {
+		t.Fatal("forward only resultset expected")
+	}
+}
+
+func TestWithQueryTimeout(t *testing.T) {
+	connector := newConnector()
+
+	// no query timeout
+	ctx, cancel := withQueryTimeout(context.Background(), connector)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("no deadline expected")
+	}
+
+	connector.SetQueryTimeout(10)
+	ctx, cancel = withQueryTimeout(context.Background(), connector)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatal("deadline expected")
+	}
+
+	// context deadline has precedence
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel = context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	ctx, cancel = withQueryTimeout(ctx, connector)
+	defer cancel()
+	if d, _ := ctx.Deadline(); !d.Equal(deadline) {
+		t.Fatalf("deadline %s - expected %s", d, deadline)
+	}
+}
+
+func TestWithPrepareTimeout(t *testing.T) {
+	connector := newConnector()
+
+	// no prepare timeout
+	ctx, cancel := withPrepareTimeout(context.Background(), connector)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("no deadline expected")
+	}
+
+	// prepare timeout independent of query timeout
+	connector.SetQueryTimeout(3600)
+	connector.SetPrepareTimeout(10)
+	ctx, cancel = withPrepareTimeout(context.Background(), connector)
+	defer cancel()
+	if d, ok := ctx.Deadline(); !ok || time.Until(d) > 10*time.Second {
+		t.Fatalf("deadline %s - expected prepare timeout deadline", d)
+	}
+
+	// earlier context deadline has precedence
+	deadline := time.Now().Add(time.Second)
+	ctx, cancel = context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	ctx, cancel = withPrepareTimeout(ctx, connector)
+	defer cancel()
+	if d, _ := ctx.Deadline(); !d.Equal(deadline) {
+		t.Fatalf("deadline %s - expected %s", d, deadline)
+	}
+}
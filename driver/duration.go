@@ -0,0 +1,97 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"time"
+)
+
+const maxTimeDuration = 24 * time.Hour
+
+/*
+A Duration is a time.Duration scan destination for integer and time (TIME, SECONDTIME) database values.
+
+time.Duration values are bound to integer fields as number of seconds and to time fields
+as seconds since midnight (fractional seconds are truncated for integer fields).
+As database/sql would scan an integer value into a time.Duration as number of nanoseconds,
+Duration reverses the conversion:
+
+	var d driver.Duration
+	err := db.QueryRow("select secs from ...").Scan(&d)
+	...
+	fmt.Println(time.Duration(d))
+*/
+type Duration time.Duration
+
+// Scan implements the database/sql/Scanner interface.
+func (d *Duration) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case int64:
+		*d = Duration(time.Duration(src) * time.Second)
+		return nil
+	case time.Time:
+		*d = Duration(timeToDuration(src))
+		return nil
+	}
+	return fmt.Errorf("duration: invalid scan type %T", src)
+}
+
+// NullDuration represents a Duration that may be null.
+// NullDuration implements the Scanner interface so
+// it can be used as a scan destination, similar to NullString.
+type NullDuration struct {
+	Duration Duration
+	Valid    bool // Valid is true if Duration is not NULL
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (n *NullDuration) Scan(src interface{}) error {
+	if src == nil {
+		n.Valid = false
+		return nil
+	}
+	if err := n.Duration.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// durationValue returns v as time.Duration if v is a time.Duration or Duration value.
+func durationValue(v interface{}) (time.Duration, bool) {
+	switch v := v.(type) {
+	case time.Duration:
+		return v, true
+	case Duration:
+		return time.Duration(v), true
+	}
+	return 0, false
+}
+
+// durationToTime returns the time of day at 0001-01-01 for a duration since midnight.
+func durationToTime(d time.Duration) (time.Time, error) {
+	if d < 0 || d >= maxTimeDuration {
+		return time.Time{}, fmt.Errorf("duration %s out of range of time of day", d)
+	}
+	return time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC).Add(d), nil
+}
+
+// timeToDuration returns the duration since midnight of the time of day of t.
+func timeToDuration(t time.Time) time.Duration {
+	return time.Duration((t.Hour()*60+t.Minute())*60+t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDurationScan(t *testing.T) {
+	var data = []struct {
+		src interface{}
+		d   time.Duration
+	}{
+		{int64(5430), 90*time.Minute + 30*time.Second},
+		{time.Date(1, time.January, 1, 1, 30, 30, 0, time.UTC), 90*time.Minute + 30*time.Second},
+	}
+
+	for _, d := range data {
+		var out Duration
+		if err := out.Scan(d.src); err != nil {
+			t.Fatal(err)
+		}
+		if time.Duration(out) != d.d {
+			t.Fatalf("duration %s - expected %s", time.Duration(out), d.d)
+		}
+	}
+
+	if err := new(Duration).Scan("text"); err == nil {
+		t.Fatal("invalid scan type error expected")
+	}
+
+	var n NullDuration
+	if err := n.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if n.Valid {
+		t.Fatal("null duration expected")
+	}
+}
+
+func TestDuration(t *testing.T) {
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("duration_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, s seconddate, t secondtime)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	in := 13*time.Hour + 14*time.Minute + 15*time.Second
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s (i, t) values (?, ?)", TestSchema, table), in, in); err != nil {
+		t.Fatal(err)
+	}
+
+	var i, tm Duration
+	if err := db.QueryRow(fmt.Sprintf("select i, t from %s.%s", TestSchema, table)).Scan(&i, &tm); err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(i) != in {
+		t.Fatalf("integer duration %s - expected %s", time.Duration(i), in)
+	}
+	if time.Duration(tm) != in {
+		t.Fatalf("secondtime duration %s - expected %s", time.Duration(tm), in)
+	}
+
+	// date part: not supported
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s (s) values (?)", TestSchema, table), in); err == nil {
+		t.Fatal("duration conversion error expected")
+	}
+}
@@ -16,6 +16,66 @@ limitations under the License.
 
 package driver
 
+import (
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+// ErrServerOOM is matched by database errors reporting that the server ran out of memory executing
+// a statement (see IsServerOOM). Such errors are transient: the statement might succeed after backing off.
+var ErrServerOOM = p.ErrServerOOM
+
+// IsServerOOM reports whether err, or an error wrapped by err, is a database error matching ErrServerOOM.
+func IsServerOOM(err error) bool {
+	for err != nil {
+		if is, ok := err.(interface{ Is(error) bool }); ok && is.Is(ErrServerOOM) {
+			return true
+		}
+		err = unwrap(err)
+	}
+	return false
+}
+
+// MultiError carries all errors returned by the database server in one reply, e.g. the errors of the
+// failed rows of a bulk statement. Database errors can be inspected by AsMultiError:
+//
+//	if multiErr, ok := driver.AsMultiError(err); ok {
+//		for _, e := range multiErr.Errors {
+//			... e.Code, e.Level, e.Text, e.StmtNo
+//		}
+//	}
+type MultiError = p.MultiError
+
+// AsMultiError returns the MultiError of err, if err, or an error wrapped by err, is a database error.
+func AsMultiError(err error) (*MultiError, bool) {
+	for err != nil {
+		var multiErr *MultiError
+		if as, ok := err.(interface{ As(interface{}) bool }); ok && as.As(&multiErr) {
+			return multiErr, true
+		}
+		err = unwrap(err)
+	}
+	return nil, false
+}
+
+// unwrap returns the error wrapped by err or nil.
+func unwrap(err error) error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// ErrorDetail is a single database error of a MultiError.
+type ErrorDetail = p.ErrorDetail
+
+// CommitError is the error of an asynchronous commit (see Connector.SetAsyncCommit). It is returned by the
+// operation reading the commit reply instead of by sql.Tx.Commit:
+//
+//	if commitErr, ok := err.(*driver.CommitError); ok {
+//		... the transaction committed before was not committed: commitErr.Unwrap() is the database error
+//	}
+type CommitError = p.CommitError
+
 // HDB error levels.
 const (
 	HdbWarning    = 0
@@ -37,3 +97,15 @@ type Error interface {
 	IsError() bool   // IsError returns true if the HDB error level equals 1.
 	IsFatal() bool   // IsFatal returns true if the HDB error level equals 2.
 }
+
+// FieldError represents errors reading the value of a resultset or output parameter field
+// (e.g. invalid CESU-8 encoded data or lob read errors), identifying the erroneous field.
+type FieldError interface {
+	Error() string     // Implements the golang error interface.
+	FieldIndex() int   // FieldIndex returns the index of the field (column) in the row.
+	FieldName() string // FieldName returns the name of the field (column display name or parameter name).
+	Unwrap() error     // Unwrap returns the underlying error.
+}
+
+// check if protocol field errors implement FieldError
+var _ FieldError = (*p.FieldError)(nil)
@@ -0,0 +1,81 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// testDBError mimics the Is and As methods of database errors.
+type testDBError struct{ oom bool }
+
+func (e *testDBError) Error() string { return "database error" }
+
+func (e *testDBError) Is(target error) bool { return e.oom && target == ErrServerOOM }
+
+func (e *testDBError) As(target interface{}) bool {
+	t, ok := target.(**MultiError)
+	if ok {
+		*t = &MultiError{Errors: []ErrorDetail{{Code: 301}}}
+	}
+	return ok
+}
+
+// testWrapError wraps an error.
+type testWrapError struct{ err error }
+
+func (e *testWrapError) Error() string { return fmt.Sprintf("wrapped: %s", e.err) }
+func (e *testWrapError) Unwrap() error { return e.err }
+
+func TestIsServerOOM(t *testing.T) {
+	for _, d := range []struct {
+		err error
+		oom bool
+	}{
+		{nil, false},
+		{errors.New("other error"), false},
+		{&testDBError{}, false},
+		{&testDBError{oom: true}, true},
+		{&testWrapError{&testDBError{oom: true}}, true},
+	} {
+		if IsServerOOM(d.err) != d.oom {
+			t.Fatalf("error %v: is server oom %t - expected %t", d.err, !d.oom, d.oom)
+		}
+	}
+}
+
+func TestAsMultiError(t *testing.T) {
+	for _, d := range []struct {
+		err error
+		ok  bool
+	}{
+		{nil, false},
+		{errors.New("other error"), false},
+		{&testDBError{}, true},
+		{&testWrapError{&testDBError{}}, true},
+	} {
+		multiErr, ok := AsMultiError(d.err)
+		if ok != d.ok {
+			t.Fatalf("error %v: multi error %t - expected %t", d.err, ok, d.ok)
+		}
+		if ok && (len(multiErr.Errors) != 1 || multiErr.Errors[0].Code != 301) {
+			t.Fatalf("error %v: multi error %v - expected code %d", d.err, multiErr, 301)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+func (c *conn) ExecDirect(ctx context.Context, query string) (driver.Result, error) {
+	if c.session.IsBad() {
+		return nil, driver.ErrBadConn
+	}
+	if pos := parameterMarkerPos(query); pos != -1 {
+		return nil, fmt.Errorf("exec direct: query parameters are not supported (parameter marker at position %d)", pos)
+	}
+	return c.execDirect(ctx, query)
+}
+
+// parameterMarkerPos returns the byte position of the first parameter marker (?) of query or -1,
+// if query does not contain parameter markers. String literals, quoted identifiers and comments are skipped.
+func parameterMarkerPos(query string) int {
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '?':
+			return i
+		case '\'', '"':
+			j := strings.IndexByte(query[i+1:], query[i]) // escaped quotes are skipped as empty literal
+			if j == -1 {
+				return -1
+			}
+			i += j + 1
+		case '-':
+			if strings.HasPrefix(query[i:], "--") {
+				j := strings.IndexByte(query[i:], '\n')
+				if j == -1 {
+					return -1
+				}
+				i += j
+			}
+		case '/':
+			if strings.HasPrefix(query[i:], "/*") {
+				j := strings.Index(query[i+2:], "*/")
+				if j == -1 {
+					return -1
+				}
+				i += j + 3
+			}
+		}
+	}
+	return -1
+}
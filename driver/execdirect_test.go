@@ -0,0 +1,75 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestParameterMarkerPos(t *testing.T) {
+	var data = []struct {
+		query string
+		pos   int
+	}{
+		{"create table t (i integer)", -1},
+		{"insert into t values (?)", 22},
+		{"select 'a?''?' from \"x?\" where i = ?", 35},
+		{"select 1 from dummy -- why?\n where 1 = 1", -1},
+		{"select 1 /* why? */ from dummy where i = ?", 41},
+		{"select 'unterminated?", -1},
+		{"select 1 - 2 from dummy where i = ?", 34},
+	}
+
+	for i, d := range data {
+		if pos := parameterMarkerPos(d.query); pos != d.pos {
+			t.Fatalf("%d %q: position %d - expected %d", i, d.query, pos, d.pos)
+		}
+	}
+}
+
+func TestExecDirect(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	hdbConn := c.(Conn)
+	ctx := context.Background()
+
+	table := RandomIdentifier("execDirect_")
+	if _, err := hdbConn.ExecDirect(ctx, fmt.Sprintf("create column table %s.%s (i integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	r, err := hdbConn.ExecDirect(ctx, fmt.Sprintf("insert into %s.%s values (1)", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := r.RowsAffected(); err != nil || n != 1 {
+		t.Fatalf("rows affected %d %v - expected %d", n, err, 1)
+	}
+
+	if _, err := hdbConn.ExecDirect(ctx, fmt.Sprintf("insert into %s.%s values (?)", TestSchema, table)); err == nil {
+		t.Fatal("query parameter error expected")
+	}
+}
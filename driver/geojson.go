@@ -0,0 +1,135 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// wkb geometry types
+const (
+	wkbPointType      = 1
+	wkbLineStringType = 2
+	wkbPolygonType    = 3
+)
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+/*
+GeometryToGeoJSON converts a geometry in (extended) well-known binary format (e.g. selected by ST_AsEWKB or ST_AsBinary)
+to a GeoJSON geometry object (RFC 7946):
+
+	{"type":"Point","coordinates":[8.642,49.293]}
+
+Supported geometry types are Point, LineString and Polygon with two dimensional coordinates.
+As GeoJSON coordinates refer to WGS 84 (SRID 4326), the SRID of the geometry is not part of the GeoJSON object.
+*/
+func GeometryToGeoJSON(b []byte) ([]byte, error) {
+	g := new(Geometry)
+	if err := g.SetEWKB(b); err != nil {
+		return nil, err
+	}
+
+	bo, err := wkbByteOrder(g.WKB[0])
+	if err != nil {
+		return nil, err
+	}
+	rd := &wkbReader{b: g.WKB[wkbHeaderSize:]}
+
+	var geometry geoJSONGeometry
+
+	switch geomType := bo.Uint32(g.WKB[1:]); geomType {
+	case wkbPointType:
+		geometry.Type, geometry.Coordinates = "Point", rd.readPoint(bo)
+	case wkbLineStringType:
+		geometry.Type, geometry.Coordinates = "LineString", rd.readPoints(bo)
+	case wkbPolygonType:
+		geometry.Type, geometry.Coordinates = "Polygon", rd.readRings(bo)
+	default:
+		return nil, fmt.Errorf("geojson: unsupported wkb geometry type %d", geomType)
+	}
+
+	if rd.err != nil {
+		return nil, rd.err
+	}
+	if len(rd.b) != 0 {
+		return nil, fmt.Errorf("geojson: invalid wkb size - %d bytes left", len(rd.b))
+	}
+	return json.Marshal(geometry)
+}
+
+// wkbReader reads the coordinates of a well-known binary geometry (after the header).
+type wkbReader struct {
+	b   []byte
+	err error
+}
+
+func (rd *wkbReader) next(size int) []byte {
+	if rd.err != nil {
+		return nil
+	}
+	if len(rd.b) < size {
+		rd.err = fmt.Errorf("geojson: invalid wkb size - %d bytes expected", size)
+		return nil
+	}
+	b := rd.b[:size]
+	rd.b = rd.b[size:]
+	return b
+}
+
+func (rd *wkbReader) readCount(bo binary.ByteOrder) int {
+	b := rd.next(4)
+	if b == nil {
+		return 0
+	}
+	n := bo.Uint32(b)
+	if uint64(n) > uint64(len(rd.b)) { // each element needs at least one byte
+		rd.err = fmt.Errorf("geojson: invalid wkb element count %d", n)
+		return 0
+	}
+	return int(n)
+}
+
+func (rd *wkbReader) readPoint(bo binary.ByteOrder) []float64 {
+	b := rd.next(16)
+	if b == nil {
+		return nil
+	}
+	return []float64{math.Float64frombits(bo.Uint64(b)), math.Float64frombits(bo.Uint64(b[8:]))}
+}
+
+func (rd *wkbReader) readPoints(bo binary.ByteOrder) [][]float64 {
+	points := make([][]float64, rd.readCount(bo))
+	for i := range points {
+		points[i] = rd.readPoint(bo)
+	}
+	return points
+}
+
+func (rd *wkbReader) readRings(bo binary.ByteOrder) [][][]float64 {
+	rings := make([][][]float64, rd.readCount(bo))
+	for i := range rings {
+		rings[i] = rd.readPoints(bo)
+	}
+	return rings
+}
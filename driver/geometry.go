@@ -0,0 +1,165 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+)
+
+// (E)WKB
+const (
+	wkbXDR        = 0 // big endian
+	wkbNDR        = 1 // little endian
+	ewkbSRIDFlag  = 0x20000000
+	wkbHeaderSize = 5 // byte order + geometry type
+	sridSize      = 4
+)
+
+/*
+A Geometry is the driver representation of a database spatial (ST_GEOMETRY, ST_POINT) value
+carrying the spatial reference system identifier (SRID) and the geometry in well-known binary (WKB) format.
+
+A Geometry is transferred to and from the database in extended well-known binary (EWKB) format,
+which includes the SRID:
+
+	select ST_AsEWKB(shape) from ...
+	insert into ... values (ST_GeomFromEWKB(?))
+*/
+type Geometry struct {
+	SRID int32  // Spatial reference system identifier (0: no SRID).
+	WKB  []byte // Geometry in well-known binary format.
+}
+
+// EWKB returns the geometry in extended well-known binary format.
+func (g *Geometry) EWKB() ([]byte, error) {
+	if len(g.WKB) < wkbHeaderSize {
+		return nil, fmt.Errorf("invalid wkb size %d", len(g.WKB))
+	}
+
+	if g.SRID == 0 { // wkb
+		return g.WKB, nil
+	}
+
+	bo, err := wkbByteOrder(g.WKB[0])
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, len(g.WKB)+sridSize)
+	b[0] = g.WKB[0]
+	bo.PutUint32(b[1:], bo.Uint32(g.WKB[1:])|ewkbSRIDFlag)
+	bo.PutUint32(b[wkbHeaderSize:], uint32(g.SRID))
+	copy(b[wkbHeaderSize+sridSize:], g.WKB[wkbHeaderSize:])
+	return b, nil
+}
+
+// SetEWKB sets the SRID and the well-known binary geometry of g from the extended well-known binary format.
+func (g *Geometry) SetEWKB(b []byte) error {
+	if len(b) < wkbHeaderSize {
+		return fmt.Errorf("invalid ewkb size %d", len(b))
+	}
+
+	bo, err := wkbByteOrder(b[0])
+	if err != nil {
+		return err
+	}
+
+	geomType := bo.Uint32(b[1:])
+
+	if geomType&ewkbSRIDFlag == 0 { // wkb
+		g.SRID = 0
+		g.WKB = append(g.WKB[:0], b...)
+		return nil
+	}
+
+	if len(b) < wkbHeaderSize+sridSize {
+		return fmt.Errorf("invalid ewkb size %d", len(b))
+	}
+
+	g.SRID = int32(bo.Uint32(b[wkbHeaderSize:]))
+	g.WKB = append(g.WKB[:0], b[:wkbHeaderSize]...)
+	bo.PutUint32(g.WKB[1:], geomType&^ewkbSRIDFlag)
+	g.WKB = append(g.WKB, b[wkbHeaderSize+sridSize:]...)
+	return nil
+}
+
+// Scan implements the database/sql/Scanner interface.
+// Source values could be binary values or lobs (ST_AsEWKB).
+func (g *Geometry) Scan(src interface{}) error {
+
+	switch src := src.(type) {
+
+	case []byte:
+		return g.SetEWKB(src)
+
+	case writerSetter:
+		b := new(bytes.Buffer)
+		if err := src.SetWriter(b); err != nil {
+			return err
+		}
+		return g.SetEWKB(b.Bytes())
+
+	}
+	return fmt.Errorf("geometry: invalid scan type %T", src)
+}
+
+// Value implements the database/sql/Valuer interface.
+func (g Geometry) Value() (driver.Value, error) {
+	return g.EWKB()
+}
+
+// NullGeometry represents a Geometry that may be null.
+// NullGeometry implements the Scanner interface so
+// it can be used as a scan destination, similar to NullString.
+type NullGeometry struct {
+	Geometry Geometry
+	Valid    bool // Valid is true if Geometry is not NULL
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (n *NullGeometry) Scan(src interface{}) error {
+	if src == nil {
+		n.Valid = false
+		return nil
+	}
+	if err := n.Geometry.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (n NullGeometry) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Geometry.Value()
+}
+
+func wkbByteOrder(b byte) (binary.ByteOrder, error) {
+	switch b {
+	case wkbXDR:
+		return binary.BigEndian, nil
+	case wkbNDR:
+		return binary.LittleEndian, nil
+	}
+	return nil, fmt.Errorf("invalid wkb byte order %d", b)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// wkbPoint returns the well-known binary representation of point (x y).
+func wkbPoint(bo binary.ByteOrder, x, y float64) []byte {
+	b := make([]byte, 21)
+	if bo == binary.LittleEndian {
+		b[0] = wkbNDR
+	}
+	bo.PutUint32(b[1:], 1) // point
+	bo.PutUint64(b[5:], math.Float64bits(x))
+	bo.PutUint64(b[13:], math.Float64bits(y))
+	return b
+}
+
+func TestGeometryEWKB(t *testing.T) {
+
+	for _, bo := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		for _, srid := range []int32{0, 4326} {
+
+			in := &Geometry{SRID: srid, WKB: wkbPoint(bo, 8.642, 49.293)}
+
+			ewkb, err := in.EWKB()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			size := 21
+			if srid != 0 {
+				size += sridSize
+			}
+			if len(ewkb) != size {
+				t.Fatalf("%s srid %d: ewkb size %d - expected %d", bo, srid, len(ewkb), size)
+			}
+
+			out := new(Geometry)
+			if err := out.Scan(ewkb); err != nil {
+				t.Fatal(err)
+			}
+			if out.SRID != in.SRID {
+				t.Fatalf("%s: srid %d - expected %d", bo, out.SRID, in.SRID)
+			}
+			if !bytes.Equal(out.WKB, in.WKB) {
+				t.Fatalf("%s srid %d: wkb %x - expected %x", bo, srid, out.WKB, in.WKB)
+			}
+		}
+	}
+
+	if err := new(Geometry).SetEWKB([]byte{2, 0, 0, 0, 1}); err == nil {
+		t.Fatal("invalid byte order error expected")
+	}
+}
+
+// wkbGeometry returns the well-known binary representation of a line string (one ring) or polygon (several rings).
+func wkbGeometry(bo binary.ByteOrder, geomType uint32, rings ...[]float64) []byte {
+	b := make([]byte, wkbHeaderSize, 64)
+	if bo == binary.LittleEndian {
+		b[0] = wkbNDR
+	}
+	bo.PutUint32(b[1:], geomType)
+
+	putUint32 := func(v uint32) {
+		b = append(b, 0, 0, 0, 0)
+		bo.PutUint32(b[len(b)-4:], v)
+	}
+	if geomType == wkbPolygonType {
+		putUint32(uint32(len(rings)))
+	}
+	for _, ring := range rings {
+		putUint32(uint32(len(ring) / 2))
+		for _, f := range ring {
+			b = append(b, 0, 0, 0, 0, 0, 0, 0, 0)
+			bo.PutUint64(b[len(b)-8:], math.Float64bits(f))
+		}
+	}
+	return b
+}
+
+func TestGeometryToGeoJSON(t *testing.T) {
+	for _, bo := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+
+		ewkb, err := (&Geometry{SRID: 4326, WKB: wkbPoint(bo, 8.642, 49.293)}).EWKB()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testData := []struct {
+			b       []byte
+			geoJSON string
+		}{
+			{wkbPoint(bo, 8.642, 49.293), `{"type":"Point","coordinates":[8.642,49.293]}`},
+			{ewkb, `{"type":"Point","coordinates":[8.642,49.293]}`},
+			{wkbGeometry(bo, wkbLineStringType, []float64{0, 0, 1, 1, 2, 0}), `{"type":"LineString","coordinates":[[0,0],[1,1],[2,0]]}`},
+			{wkbGeometry(bo, wkbPolygonType, []float64{0, 0, 4, 0, 4, 4, 0, 0}, []float64{1, 1, 2, 1, 2, 2, 1, 1}), `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,0]],[[1,1],[2,1],[2,2],[1,1]]]}`},
+		}
+
+		for _, d := range testData {
+			geoJSON, err := GeometryToGeoJSON(d.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(geoJSON) != d.geoJSON {
+				t.Fatalf("%s: geojson %s - expected %s", bo, geoJSON, d.geoJSON)
+			}
+		}
+
+		// invalid geometries
+		for _, b := range [][]byte{
+			wkbPoint(bo, 8.642, 49.293)[:15],                               // truncated point
+			wkbGeometry(bo, 4, []float64{0, 0}),                            // multi point
+			append(wkbGeometry(bo, wkbLineStringType, []float64{0, 0}), 0), // trailing byte
+		} {
+			if _, err := GeometryToGeoJSON(b); err == nil {
+				t.Fatalf("%s: invalid geometry %x: error expected", bo, b)
+			}
+		}
+	}
+}
+
+func TestGeometry(t *testing.T) {
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("geometry_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (i integer, g st_geometry(4326))", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	in := Geometry{SRID: 4326, WKB: wkbPoint(binary.LittleEndian, 8.642, 49.293)}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, st_geomfromewkb(?))", TestSchema, table), 1, in); err != nil {
+		t.Fatal(err)
+	}
+
+	var out Geometry
+	if err := db.QueryRow(fmt.Sprintf("select g.st_asewkb() from %s.%s where i = 1", TestSchema, table)).Scan(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.SRID != in.SRID {
+		t.Fatalf("srid %d - expected %d", out.SRID, in.SRID)
+	}
+	if !bytes.Equal(out.WKB, in.WKB) {
+		t.Fatalf("wkb %x - expected %x", out.WKB, in.WKB)
+	}
+}
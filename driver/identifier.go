@@ -21,7 +21,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
-	"strconv"
+	"strings"
 )
 
 var reSimple = regexp.MustCompile("^[_A-Z][_#$A-Z0-9]*$")
@@ -45,5 +45,27 @@ func (i Identifier) String() string {
 	if reSimple.MatchString(s) {
 		return s
 	}
-	return strconv.Quote(s)
+	return quoteIdentifier(s)
+}
+
+/*
+QuoteIdentifier returns name as hdb delimited (double quoted) identifier, whereby double quotes contained
+in name are escaped by doubling them. Use QuoteIdentifier to use names like table or column names
+in dynamic SQL statements, where query parameters cannot be used.
+
+As delimited identifiers are case sensitive, names of database objects created with undelimited identifiers
+need to be provided in upper case. An error is returned if name is empty or contains null bytes.
+*/
+func QuoteIdentifier(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("invalid identifier: empty name")
+	}
+	if strings.IndexByte(name, 0) != -1 {
+		return "", fmt.Errorf("invalid identifier %q: null byte", name)
+	}
+	return quoteIdentifier(name), nil
+}
+
+func quoteIdentifier(s string) string {
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
 }
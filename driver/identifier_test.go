@@ -36,6 +36,7 @@ var testIdentifierData = []*testIdentifier{
 	&testIdentifier{"testTransaction", `"testTransaction"`},
 	&testIdentifier{"a.b.c", `"a.b.c"`},
 	&testIdentifier{"AAA.BBB.CCC", `"AAA.BBB.CCC"`},
+	&testIdentifier{`a"b`, `"a""b"`},
 }
 
 func TestIdentifierStringer(t *testing.T) {
@@ -45,3 +46,31 @@ func TestIdentifierStringer(t *testing.T) {
 		}
 	}
 }
+
+func TestQuoteIdentifier(t *testing.T) {
+	testData := []struct {
+		name, quoted string
+	}{
+		{"TABLE", `"TABLE"`},
+		{"table", `"table"`},
+		{`a"b`, `"a""b"`},
+		{`""`, `""""""`},
+		{`x"; drop table t; --`, `"x""; drop table t; --"`},
+	}
+
+	for _, d := range testData {
+		quoted, err := QuoteIdentifier(d.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if quoted != d.quoted {
+			t.Fatalf("name %s: quoted %s - expected %s", d.name, quoted, d.quoted)
+		}
+	}
+
+	for _, name := range []string{"", "a\x00b"} {
+		if _, err := QuoteIdentifier(name); err == nil {
+			t.Fatalf("name %q: error expected", name)
+		}
+	}
+}
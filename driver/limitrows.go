@@ -0,0 +1,92 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrRowLimitExceeded is returned by LimitedRows.Err if a resultset contains more rows than the limit.
+var ErrRowLimitExceeded = errors.New("row limit exceeded")
+
+/*
+LimitedRows wraps sql.Rows limiting the number of rows per resultset. Please see LimitRows.
+*/
+type LimitedRows struct {
+	*sql.Rows
+	max int
+	n   int // number of rows of the current resultset
+	err error
+}
+
+/*
+LimitRows returns rows limited to max rows per resultset (query result or procedure table output parameter)
+to guard against unexpectedly large results.
+
+If a resultset contains more than max rows, Next returns false, the underlying rows are closed (so that the
+database cursor is released) and Err returns ErrRowLimitExceeded:
+
+	rows, err := db.Query(...)
+	if err != nil {
+		...
+	}
+	limitedRows := driver.LimitRows(rows, 1000)
+	defer limitedRows.Close()
+	for limitedRows.Next() {
+		...
+	}
+	if err := limitedRows.Err(); err == driver.ErrRowLimitExceeded {
+		...
+	}
+*/
+func LimitRows(rows *sql.Rows, max int) *LimitedRows {
+	return &LimitedRows{Rows: rows, max: max}
+}
+
+// Next prepares the next result row for reading with the Scan method (see sql.Rows.Next).
+func (r *LimitedRows) Next() bool {
+	if r.err != nil || !r.Rows.Next() {
+		return false
+	}
+	if r.n >= r.max {
+		r.err = ErrRowLimitExceeded
+		r.Rows.Close()
+		return false
+	}
+	r.n++
+	return true
+}
+
+// NextResultSet prepares the next resultset for reading (see sql.Rows.NextResultSet).
+// The row limit applies to each resultset.
+func (r *LimitedRows) NextResultSet() bool {
+	if r.err != nil || !r.Rows.NextResultSet() {
+		return false
+	}
+	r.n = 0
+	return true
+}
+
+// Err returns ErrRowLimitExceeded if the row limit was exceeded, otherwise the error of the
+// underlying rows (see sql.Rows.Err).
+func (r *LimitedRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Rows.Err()
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestLimitRows(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	const query = "select 1 from dummy union all select 2 from dummy union all select 3 from dummy"
+
+	var data = []struct {
+		max    int
+		numRow int
+		err    error
+	}{
+		{3, 3, nil},
+		{10, 3, nil},
+		{2, 2, ErrRowLimitExceeded},
+		{0, 0, ErrRowLimitExceeded},
+	}
+
+	for _, d := range data {
+		rows, err := db.Query(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		limitedRows := LimitRows(rows, d.max)
+
+		numRow := 0
+		for limitedRows.Next() {
+			numRow++
+		}
+		if numRow != d.numRow || limitedRows.Err() != d.err {
+			t.Fatalf("max %d: number of rows %d error %v - expected %d %v", d.max, numRow, limitedRows.Err(), d.numRow, d.err)
+		}
+		if err := limitedRows.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// cursor released: connection reusable
+	if stats := db.Stats(); stats.InUse != 0 {
+		t.Fatalf("connections in use %d - expected %d", stats.InUse, 0)
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package driver
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -26,9 +29,18 @@ import (
 // A Lob object uses an io.Writer object as destination for reading content from a database lob field.
 // A Lob can be created by contructor method NewLob with io.Reader and io.Writer as parameters or
 // created by new, setting io.Reader and io.Writer by SetReader and SetWriter methods.
+//
+// Alternatively an io.Reader can be used as lob parameter directly. As the lob content is written to
+// the database in chunks until the end of the reader, the size of the lob content does not need to be known
+// in advance (e.g. streaming from a network connection). A reader error fails the statement.
+//
+// The content of character based lob fields (NCLOB, TEXT) is decoded from the database CESU-8 encoding into UTF-8,
+// whereas the content of binary lob fields (BLOB, CLOB, BINTEXT) is written to the io.Writer as raw bytes.
+// Text mining markup of TEXT and BINTEXT fields is not interpreted by the driver.
 type Lob struct {
 	rd io.Reader
 	wr io.Writer
+	ra io.ReaderAt // database lob set by Scan (see ReadAt)
 }
 
 // NewLob creates a new Lob instance with the io.Reader and io.Writer given as parameters.
@@ -54,27 +66,65 @@ type writerSetter interface {
 	SetWriter(w io.Writer) error
 }
 
+// lobBytes reads the content of a database lob field into b. As a NULL lob field is represented by a nil value,
+// the content of an empty lob is returned as zero-length, non nil slice.
+func lobBytes(ws writerSetter, b []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(b[:0])
+	if err := ws.SetWriter(buf); err != nil {
+		return nil, err
+	}
+	if b := buf.Bytes(); b != nil {
+		return b, nil
+	}
+	return []byte{}, nil
+}
+
 // Scan implements the database/sql/Scanner interface.
+// The lob content is written to the io.Writer of the Lob. For binary lob fields (BLOB, CLOB, BINTEXT)
+// the io.Writer can be omitted to read ranges of the lob content by ReadAt.
 func (l *Lob) Scan(src interface{}) error {
 
-	if l.wr == nil {
-		return fmt.Errorf("lob error: initial reader %[1]T %[1]v", l)
-	}
-
 	ws, ok := src.(writerSetter)
 	if !ok {
 		return fmt.Errorf("lob: invalid scan type %T", src)
 	}
 
+	l.ra, _ = src.(io.ReaderAt)
+
+	if l.wr == nil {
+		if l.ra != nil {
+			return nil // content is read by ReadAt
+		}
+		return fmt.Errorf("lob error: initial reader %[1]T %[1]v", l)
+	}
+
 	if err := ws.SetWriter(l.wr); err != nil {
 		return err
 	}
 	return nil
 }
 
+/*
+ReadAt implements the io.ReaderAt interface for binary lob fields (BLOB, CLOB, BINTEXT) scanned into the Lob.
+It reads len(p) bytes of the lob content starting at byte offset off, whereby only the requested range is read
+from the database (e.g. reading a page of a large document). Like defined by io.ReaderAt, the error is io.EOF
+if less than len(p) bytes are read because of the end of the lob content.
+
+The lob can be read as long as the database lob locator is valid, i.e. until the rows of the query are closed
+or the transaction is ended. Lobs of the same connection may be read by different goroutines concurrently,
+whereby the read requests are serialized on the connection.
+*/
+func (l *Lob) ReadAt(p []byte, off int64) (int, error) {
+	if l.ra == nil {
+		return 0, fmt.Errorf("lob: no binary database lob scanned for ReadAt")
+	}
+	return l.ra.ReadAt(p, off)
+}
+
 // NullLob represents an Lob that may be null.
 // NullLob implements the Scanner interface so
 // it can be used as a scan destination, similar to NullString.
+// An empty database lob field is valid (Valid is true), whereas a NULL lob field is not.
 type NullLob struct {
 	Lob   *Lob
 	Valid bool // Valid is true if Lob is not NULL
@@ -92,3 +142,90 @@ func (l *NullLob) Scan(src interface{}) error {
 	l.Valid = true
 	return nil
 }
+
+/*
+A JSONLob is a json.RawMessage which can be used as scan destination for database text lob fields (CLOB, NCLOB)
+containing JSON documents. As database/sql does not support scanning driver lob values into []byte based types,
+a json.RawMessage needs to be converted to a *JSONLob for scanning:
+
+	var m json.RawMessage
+	err := row.Scan((*JSONLob)(&m))
+
+Writing a json.RawMessage to a lob field does not require a conversion.
+*/
+type JSONLob json.RawMessage
+
+// Scan implements the database/sql/Scanner interface.
+func (l *JSONLob) Scan(src interface{}) error {
+
+	switch src := src.(type) {
+
+	case nil:
+		*l = nil
+		return nil
+
+	case []byte:
+		*l = append((*l)[:0], src...)
+		return nil
+
+	case string:
+		*l = append((*l)[:0], src...)
+		return nil
+
+	case writerSetter:
+		b, err := lobBytes(src, *l)
+		if err != nil {
+			return err
+		}
+		*l = b
+		return nil
+
+	}
+	return fmt.Errorf("json lob: invalid scan type %T", src)
+}
+
+// Value implements the database/sql/Valuer interface.
+func (l JSONLob) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return []byte(l), nil
+}
+
+/*
+A JSONMap is a JSON object which is marshaled to JSON text when written to and unmarshaled when read from
+database character fields (NVARCHAR, CLOB, NCLOB). JSON handling is only applied to JSONMap values, so that
+plain string values are not affected.
+
+	_, err := db.Exec("insert into documents values (?)", JSONMap{"name": "hdb"})
+
+	var m JSONMap
+	err = row.Scan(&m)
+*/
+type JSONMap map[string]interface{}
+
+// Scan implements the database/sql/Scanner interface.
+func (m *JSONMap) Scan(src interface{}) error {
+	var l JSONLob
+	if err := l.Scan(src); err != nil {
+		return fmt.Errorf("json map: invalid scan type %T", src)
+	}
+	if l == nil {
+		*m = nil
+		return nil
+	}
+	v := make(map[string]interface{})
+	if err := json.Unmarshal(l, &v); err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (m JSONMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(map[string]interface{}(m))
+}
@@ -0,0 +1,476 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testJSONDocument returns a json document of at least size bytes.
+func testJSONDocument(t *testing.T, size int, text string) json.RawMessage {
+	items := make([]map[string]interface{}, 0)
+	for i, n := 0, 0; n < size; i++ {
+		item := map[string]interface{}{"id": i, "text": text}
+		items = append(items, item)
+		n += len(text) + 32
+	}
+	b, err := json.Marshal(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return json.RawMessage(b)
+}
+
+func TestJSONLobScan(t *testing.T) {
+	in := []byte(`{"a":1}`)
+
+	var m json.RawMessage
+	if err := (*JSONLob)(&m).Scan(in); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m, in) {
+		t.Fatalf("json %s - expected %s", m, in)
+	}
+
+	if err := (*JSONLob)(&m).Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if m != nil {
+		t.Fatalf("json %s - expected nil", m)
+	}
+
+	if err := (*JSONLob)(&m).Scan(int64(1)); err == nil {
+		t.Fatal("invalid scan type error expected")
+	}
+}
+
+func TestJSONLob(t *testing.T) {
+	const size = 1 << 20 // 1MB
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testData := []struct {
+		dataType string
+		text     string
+	}{
+		{"clob", strings.Repeat("ascii text ", 8)},
+		{"nclob", strings.Repeat("unicode text äöü 漢字 ", 8)},
+	}
+
+	for _, d := range testData {
+		table := RandomIdentifier(fmt.Sprintf("json%s_", d.dataType))
+		if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, x %s)", TestSchema, table, d.dataType)); err != nil {
+			t.Fatal(err)
+		}
+
+		in := testJSONDocument(t, size, d.text)
+
+		// SQL Error 596 - LOB streaming is not permitted in auto-commit mode
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), 1, in); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+
+		var out json.RawMessage
+		if err := db.QueryRow(fmt.Sprintf("select x from %s.%s where i = 1", TestSchema, table)).Scan((*JSONLob)(&out)); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(out, in) {
+			t.Fatalf("%s: json document size %d - expected size %d", d.dataType, len(out), len(in))
+		}
+		if !json.Valid(out) {
+			t.Fatalf("%s: invalid json document", d.dataType)
+		}
+	}
+}
+
+func TestJSONMapScan(t *testing.T) {
+	in := JSONMap{"a": float64(1), "b": "text", "c": []interface{}{true, nil}}
+
+	v, err := in.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out JSONMap
+	if err := out.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("json map %v - expected %v", out, in)
+	}
+
+	if err := out.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if out != nil {
+		t.Fatalf("json map %v - expected nil", out)
+	}
+	if err := out.Scan([]byte(`[1]`)); err == nil {
+		t.Fatal("json unmarshal error expected")
+	}
+
+	if v, err := JSONMap(nil).Value(); err != nil || v != nil {
+		t.Fatalf("value %v %v - expected nil", v, err)
+	}
+}
+
+func TestJSONMap(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	in := JSONMap{"name": "hdb", "text": "unicode text äöü 漢字", "values": []interface{}{float64(1), float64(2)}}
+
+	for _, dataType := range []string{"nvarchar(500)", "nclob"} {
+		table := RandomIdentifier("jsonmap_")
+		if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, x %s)", TestSchema, table, dataType)); err != nil {
+			t.Fatal(err)
+		}
+
+		// SQL Error 596 - LOB streaming is not permitted in auto-commit mode
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), 1, in); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+
+		var out JSONMap
+		if err := db.QueryRow(fmt.Sprintf("select x from %s.%s where i = 1", TestSchema, table)).Scan(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Fatalf("%s: json map %v - expected %v", dataType, out, in)
+		}
+	}
+}
+
+// testWriterSetter simulates a database lob field.
+type testWriterSetter []byte
+
+func (ws testWriterSetter) SetWriter(w io.Writer) error {
+	_, err := w.Write(ws)
+	return err
+}
+
+func TestLobBytesEmpty(t *testing.T) {
+	var m json.RawMessage
+	if err := (*JSONLob)(&m).Scan(testWriterSetter{}); err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || len(m) != 0 {
+		t.Fatalf("empty lob %v - expected zero-length value", m)
+	}
+}
+
+func TestLobNullAndEmpty(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("lobNullEmpty_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, b blob, c nclob)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	// SQL Error 596 - LOB streaming is not permitted in auto-commit mode
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("insert into %s.%s values (?, ?, ?)", TestSchema, table), 0, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("insert into %s.%s values (?, ?, ?)", TestSchema, table), 1, []byte{}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	ch, err := QueryChan(context.Background(), db, fmt.Sprintf("select b, c from %s.%s order by i", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows [][]interface{}
+	for r := range ch {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+		rows = append(rows, r.Row)
+	}
+
+	expected := [][]interface{}{{nil, nil}, {[]byte{}, ""}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("rows %v - expected %v", rows, expected)
+	}
+}
+
+func TestTextLob(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("textLob_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (i integer, t text)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	// multibyte characters crossing lob chunk boundaries
+	in := strings.Repeat("a€𝕳", 5000)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), 1, NewLob(strings.NewReader(in), nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer)
+	if err := db.QueryRow(fmt.Sprintf("select t from %s.%s where i = 1", TestSchema, table)).Scan(NewLob(nil, out)); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != in {
+		t.Fatalf("text size %d - expected %d", out.Len(), len(in))
+	}
+}
+
+func TestLobReader(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("lobReader_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (i integer, b blob)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	// stream of unknown size spanning several lob chunks
+	in := bytes.Repeat([]byte("lob reader"), 100000)
+	pr, pw := io.Pipe()
+	go func() {
+		for b := in; len(b) != 0; b = b[1000:] {
+			pw.Write(b[:1000])
+		}
+		pw.Close()
+	}()
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), 1, pr); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer)
+	if err := db.QueryRow(fmt.Sprintf("select b from %s.%s where i = 1", TestSchema, table)).Scan(NewLob(nil, out)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), in) {
+		t.Fatalf("lob size %d - expected %d", out.Len(), len(in))
+	}
+
+	// reader error fails the statement
+	pr, pw = io.Pipe()
+	go func() {
+		pw.Write(in[:1000])
+		pw.CloseWithError(errors.New("stream failed"))
+	}()
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), 2, pr); err == nil || !strings.Contains(err.Error(), "stream failed") {
+		t.Fatalf("error %v - expected reader error", err)
+	}
+
+	var cnt int
+	if err := db.QueryRow(fmt.Sprintf("select count(*) from %s.%s where i = 2", TestSchema, table)).Scan(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	if cnt != 0 {
+		t.Fatalf("number of rows %d - expected %d", cnt, 0)
+	}
+}
+
+func TestLobReadAt(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("lobReadAt_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (i integer, b blob)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	in := make([]byte, 100000)
+	for i := range in {
+		in[i] = byte(i)
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), 1, in); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin() // lob locator valid within transaction
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(fmt.Sprintf("select b from %s.%s where i = 1", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		t.Fatal(rows.Err())
+	}
+	lob := new(Lob)
+	if err := rows.Scan(lob); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range []struct {
+		off  int64
+		size int
+		n    int
+		err  error
+	}{
+		{0, 10, 10, nil},
+		{50000, 10000, 10000, nil},
+		{99990, 100, 10, io.EOF},
+		{100000, 10, 0, io.EOF},
+	} {
+		p := make([]byte, d.size)
+		n, err := lob.ReadAt(p, d.off)
+		if n != d.n || err != d.err {
+			t.Fatalf("offset %d: read %d bytes error %v - expected %d %v", d.off, n, err, d.n, d.err)
+		}
+		if !bytes.Equal(p[:n], in[d.off:d.off+int64(n)]) {
+			t.Fatalf("offset %d: invalid content", d.off)
+		}
+	}
+}
+
+func TestLobConcurrentRead(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("lobConcurrentRead_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (i integer, b blob)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	const numLob = 4
+	in := make([][]byte, numLob)
+	for i := range in {
+		in[i] = bytes.Repeat([]byte{byte(i)}, 200000)
+		if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), i, in[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tx, err := db.Begin() // lob locators valid within transaction
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(fmt.Sprintf("select b from %s.%s order by i", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	lobs := make([]*Lob, 0, numLob)
+	for rows.Next() {
+		lob := new(Lob)
+		if err := rows.Scan(lob); err != nil {
+			t.Fatal(err)
+		}
+		lobs = append(lobs, lob)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// read the lobs of the same connection concurrently
+	errs := make([]error, len(lobs))
+	var wg sync.WaitGroup
+	for i, lob := range lobs {
+		wg.Add(1)
+		go func(i int, lob *Lob) {
+			defer wg.Done()
+			p := make([]byte, 10000)
+			for off := int64(0); off < int64(len(in[i])); off += int64(len(p)) {
+				n, err := lob.ReadAt(p, off)
+				if err != nil && err != io.EOF {
+					errs[i] = err
+					return
+				}
+				if !bytes.Equal(p[:n], in[i][off:off+int64(n)]) {
+					errs[i] = fmt.Errorf("lob %d offset %d: invalid content", i, off)
+					return
+				}
+			}
+		}(i, lob)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
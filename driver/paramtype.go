@@ -0,0 +1,95 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+/*
+StmtParamTypes may be implemented by driver.Stmt. It provides the metadata of the statement parameters
+as returned by the database server when preparing the statement, e.g. for generic parameter marshaling layers
+adapting the arguments to the database schema before executing the statement.
+
+As database/sql does not provide access to the driver statement, the statement needs to be
+prepared on the driver connection (see sql.Conn.Raw).
+*/
+type StmtParamTypes interface {
+	driver.Stmt
+	ParamTypes() []ParamType
+}
+
+// A ParamType represents the metadata of a statement parameter. The methods correspond to
+// the methods of sql.ColumnType.
+type ParamType struct {
+	f *p.ParameterField
+}
+
+// Name returns the name of the parameter (procedure calls) or the empty string.
+func (t ParamType) Name() string {
+	return t.f.Name()
+}
+
+// DatabaseTypeName returns the database type name of the parameter (name of the type code, e.g. "NVARCHAR").
+func (t ParamType) DatabaseTypeName() string {
+	return t.f.TypeCode().TypeName()
+}
+
+// Length returns the length of variable length parameter types (strings and binary). ok is false for other types.
+func (t ParamType) Length() (length int64, ok bool) {
+	return t.f.TypeLength()
+}
+
+// DecimalSize returns the precision and scale of decimal parameter types. ok is false for other types.
+func (t ParamType) DecimalSize() (precision, scale int64, ok bool) {
+	return t.f.TypePrecisionScale()
+}
+
+// Nullable returns true, if the parameter may be null.
+func (t ParamType) Nullable() bool {
+	return t.f.Nullable()
+}
+
+// HasDefault returns true, if the parameter has a default value.
+func (t ParamType) HasDefault() bool {
+	return t.f.HasDefault()
+}
+
+// In returns true, if the parameter is an input (IN or INOUT) parameter.
+func (t ParamType) In() bool {
+	return t.f.In()
+}
+
+// Out returns true, if the parameter is an output (OUT or INOUT) parameter.
+func (t ParamType) Out() bool {
+	return t.f.Out()
+}
+
+func newParamTypes(prmFieldSet *p.ParameterFieldSet) []ParamType {
+	types := make([]ParamType, prmFieldSet.NumField())
+	for i := range types {
+		types[i] = ParamType{f: prmFieldSet.Field(i)}
+	}
+	return types
+}
+
+// ParamTypes implements the StmtParamTypes interface.
+func (s *stmt) ParamTypes() []ParamType {
+	return newParamTypes(s.prmFieldSet)
+}
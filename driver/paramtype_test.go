@@ -0,0 +1,91 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+func TestParamTypes(t *testing.T) {
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("paramType_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer not null, s nvarchar(20), d decimal(10,2))", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stmt, err := conn.(driver.ConnPrepareContext).PrepareContext(ctx, fmt.Sprintf("insert into %s.%s values (?, ?, ?)", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	types := stmt.(StmtParamTypes).ParamTypes()
+	if len(types) != 3 {
+		t.Fatalf("number of parameters %d - expected %d", len(types), 3)
+	}
+
+	var data = []struct {
+		typeName         string
+		length           int64
+		precision, scale int64
+		nullable         bool
+	}{
+		{"INTEGER", 0, 0, 0, false},
+		{"NVARCHAR", 20, 0, 0, true},
+		{"DECIMAL", 0, 10, 2, true},
+	}
+
+	for i, d := range data {
+		pt := types[i]
+		if pt.DatabaseTypeName() != d.typeName {
+			t.Fatalf("parameter %d: type %s - expected %s", i, pt.DatabaseTypeName(), d.typeName)
+		}
+		if length, _ := pt.Length(); length != d.length {
+			t.Fatalf("parameter %d: length %d - expected %d", i, length, d.length)
+		}
+		if precision, scale, _ := pt.DecimalSize(); precision != d.precision || scale != d.scale {
+			t.Fatalf("parameter %d: precision %d scale %d - expected %d %d", i, precision, scale, d.precision, d.scale)
+		}
+		if pt.Nullable() != d.nullable {
+			t.Fatalf("parameter %d: nullable %t - expected %t", i, pt.Nullable(), d.nullable)
+		}
+		if !pt.In() || pt.Out() {
+			t.Fatalf("parameter %d: in %t out %t - expected input parameter", i, pt.In(), pt.Out())
+		}
+	}
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+/*
+Stored procedure output parameters
+
+Scalar output parameters of a stored procedure can be read by passing sql.Out arguments:
+
+	var out int
+	_, err := db.Exec("call myproc(?, ?)", 1, sql.Out{Dest: &out})
+
+The sql.Out arguments are assigned to the scalar output parameters in declaration order. All other arguments
+are assigned to the input parameters in declaration order. sql.Out arguments with In set to true are rejected:
+the value of an INOUT parameter is passed as input argument and read by an output argument.
+*/
+
+// splitOutArgs splits the arguments of a procedure call into input and sql.Out arguments.
+// sql.Out arguments with In set to true are rejected before by stmt.CheckNamedValue.
+func splitOutArgs(args []driver.NamedValue) ([]driver.NamedValue, []sql.Out) {
+	var outArgs []sql.Out
+	inArgs := make([]driver.NamedValue, 0, len(args))
+	for _, arg := range args {
+		if out, ok := arg.Value.(sql.Out); ok {
+			outArgs = append(outArgs, out)
+			continue
+		}
+		inArgs = append(inArgs, arg)
+	}
+	return inArgs, outArgs
+}
+
+// assignOutArgs assigns the values of the scalar output parameters to the sql.Out arguments.
+// Time values are interpreted in location loc, if not nil (see Connector.SetServerTimezoneConversion).
+func assignOutArgs(prmFieldSet *p.ParameterFieldSet, fieldValues *p.FieldValues, outArgs []sql.Out, loc *time.Location) error {
+	if len(outArgs) == 0 {
+		return nil
+	}
+
+	numField := prmFieldSet.NumOutputField()
+	if len(outArgs) > numField {
+		return fmt.Errorf("invalid number of output arguments %d - maximum %d expected", len(outArgs), numField)
+	}
+	if fieldValues.NumRow() == 0 {
+		return fmt.Errorf("output parameter values missing")
+	}
+
+	values := make([]driver.Value, numField)
+	fieldValues.Row(0, values)
+	if loc != nil {
+		timesInLocation(values, loc)
+	}
+	if err := outFixedValues(prmFieldSet, values); err != nil {
+		return err
+	}
+
+	for i, out := range outArgs {
+		if err := assignOut(out.Dest, values[i]); err != nil {
+			return fmt.Errorf("output parameter %s: %s", prmFieldSet.OutputField(i).Name(), err)
+		}
+	}
+	return nil
+}
+
+// outFixedValues converts the fixed size decimal values of the output parameters into binary decimal values
+// (see fixedValue).
+func outFixedValues(prmFieldSet *p.ParameterFieldSet, values []driver.Value) error {
+	for i := 0; i < prmFieldSet.NumOutputField(); i++ {
+		x, ok := values[i].(*p.Fixed)
+		if !ok {
+			continue
+		}
+		_, scale, _ := prmFieldSet.OutputField(i).TypePrecisionScale()
+		var err error
+		if values[i], err = fixedValue(x, int(scale), DecimalBinary, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignOut assigns an output parameter value to dest.
+func assignOut(dest interface{}, v driver.Value) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(v)
+	}
+
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("invalid destination %T - non nil pointer expected", dest)
+	}
+	rv = rv.Elem()
+
+	if v == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+
+	sv := reflect.ValueOf(v)
+	if sv.Type().AssignableTo(rv.Type()) {
+		if b, ok := v.([]byte); ok { // copy: field value buffers are reused
+			sv = reflect.ValueOf(append([]byte(nil), b...))
+		}
+		rv.Set(sv)
+		return nil
+	}
+
+	// conversions like database/sql Rows.Scan: numbers are formatted and parsed, lossy conversions are errors
+	switch rk := rv.Kind(); {
+	case rk == reflect.String:
+		switch v := v.(type) {
+		case string:
+			rv.SetString(v)
+		case []byte:
+			rv.SetString(string(v))
+		case int64:
+			rv.SetString(strconv.FormatInt(v, 10))
+		case float64:
+			rv.SetString(strconv.FormatFloat(v, 'g', -1, 64))
+		case bool:
+			rv.SetString(strconv.FormatBool(v))
+		case time.Time:
+			rv.SetString(v.Format(time.RFC3339Nano))
+		default:
+			return fmt.Errorf("cannot assign %T to %T", v, dest)
+		}
+	case rk == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		s, err := stringValue(v) // copy: field value buffers are reused
+		if err != nil {
+			return fmt.Errorf("cannot assign %T to %T", v, dest)
+		}
+		rv.SetBytes([]byte(s))
+	case isIntegerKind(rk):
+		switch {
+		case isIntegerKind(sv.Kind()):
+			return assignInteger(rv, sv)
+		case sv.Kind() == reflect.Float64:
+			f := sv.Float()
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return fmt.Errorf("cannot assign %g to %T without loss", f, dest)
+			}
+			return assignInteger(rv, reflect.ValueOf(int64(f)))
+		}
+		s, err := stringValue(v)
+		if err != nil {
+			return fmt.Errorf("cannot assign %T to %T", v, dest)
+		}
+		i64, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("cannot assign %q to %T: %s", s, dest, err)
+		}
+		return assignInteger(rv, reflect.ValueOf(i64))
+	case rk == reflect.Float32 || rk == reflect.Float64:
+		var f float64
+		switch {
+		case sv.Kind() == reflect.Float64:
+			f = sv.Float()
+		case sv.Kind() == reflect.Int64:
+			i64 := sv.Int()
+			if f = float64(i64); int64(f) != i64 {
+				return fmt.Errorf("cannot assign %d to %T without loss", i64, dest)
+			}
+		default:
+			s, err := stringValue(v)
+			if err != nil {
+				return fmt.Errorf("cannot assign %T to %T", v, dest)
+			}
+			if f, err = strconv.ParseFloat(s, rv.Type().Bits()); err != nil {
+				return fmt.Errorf("cannot assign %q to %T: %s", s, dest, err)
+			}
+		}
+		if rv.OverflowFloat(f) {
+			return fmt.Errorf("float %g out of range of %s", f, rv.Type())
+		}
+		rv.SetFloat(f)
+	case rk == reflect.Bool:
+		s, err := stringValue(v)
+		if err != nil {
+			return fmt.Errorf("cannot assign %T to %T", v, dest)
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("cannot assign %q to %T: %s", s, dest, err)
+		}
+		rv.SetBool(b)
+	default:
+		return fmt.Errorf("cannot assign %T to %T", v, dest)
+	}
+	return nil
+}
+
+// stringValue returns the string or byte slice value v as string.
+func stringValue(v driver.Value) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+	return "", fmt.Errorf("invalid string value type %T", v)
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// assignInteger assigns the integer sv to the integer destination rv. In contrast to reflect.Value.Convert
+// values exceeding the range of the destination type are not truncated but return an error.
+func assignInteger(rv, sv reflect.Value) error {
+	switch sv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i64 := sv.Int()
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if rv.OverflowInt(i64) {
+				return fmt.Errorf("integer %d out of range of %s", i64, rv.Type())
+			}
+			rv.SetInt(i64)
+		default:
+			if i64 < 0 || rv.OverflowUint(uint64(i64)) {
+				return fmt.Errorf("integer %d out of range of %s", i64, rv.Type())
+			}
+			rv.SetUint(uint64(i64))
+		}
+	default:
+		u64 := sv.Uint()
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if u64 > math.MaxInt64 || rv.OverflowInt(int64(u64)) {
+				return fmt.Errorf("integer %d out of range of %s", u64, rv.Type())
+			}
+			rv.SetInt(int64(u64))
+		default:
+			if rv.OverflowUint(u64) {
+				return fmt.Errorf("integer %d out of range of %s", u64, rv.Type())
+			}
+			rv.SetUint(u64)
+		}
+	}
+	return nil
+}
+
+// execCall executes a procedure call statement and assigns the scalar output parameters.
+func (s *stmt) execCall(args []driver.NamedValue) (driver.Result, error) {
+	inArgs, outArgs := splitOutArgs(args)
+
+	fieldValues, tableResults, err := s.session.Call(s.id, s.prmFieldSet, inArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	// table output parameters are not accessible via Exec
+	for _, tableResult := range tableResults {
+		if !tableResult.Attrs().ResultsetClosed() {
+			if err := s.session.CloseResultsetID(tableResult.ID()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := assignOutArgs(s.prmFieldSet, fieldValues, outArgs, s.session.TimeLocation()); err != nil {
+		return nil, err
+	}
+	return driver.ResultNoRows, nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"testing"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+func TestSplitOutArgs(t *testing.T) {
+	var o int
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: int64(1)},
+		{Ordinal: 2, Value: sql.Out{Dest: &o}},
+		{Ordinal: 3, Value: "a"},
+	}
+	inArgs, outArgs := splitOutArgs(args)
+	if len(inArgs) != 2 || inArgs[0].Value != int64(1) || inArgs[1].Value != "a" {
+		t.Fatalf("input arguments %v", inArgs)
+	}
+	if len(outArgs) != 1 || outArgs[0].Dest != &o {
+		t.Fatalf("output arguments %v", outArgs)
+	}
+}
+
+func TestCheckNamedValueInOut(t *testing.T) {
+	var o int
+	s := &stmt{qt: p.QtProcedureCall, query: "call myproc(?)"}
+
+	if err := s.CheckNamedValue(&driver.NamedValue{Ordinal: 1, Value: sql.Out{Dest: &o}}); err != nil {
+		t.Fatal(err)
+	}
+	// input output argument
+	if err := s.CheckNamedValue(&driver.NamedValue{Ordinal: 1, Value: sql.Out{Dest: &o, In: true}}); err == nil {
+		t.Fatal("input output argument error expected")
+	}
+}
+
+func TestAssignOut(t *testing.T) {
+	var (
+		i  int
+		s  string
+		b  []byte
+		ns sql.NullString
+	)
+
+	src := []byte("text")
+	tests := []struct {
+		dest interface{}
+		v    driver.Value
+	}{
+		{&i, int64(42)},
+		{&s, src},
+		{&b, src},
+		{&ns, "null string"},
+	}
+	for _, test := range tests {
+		if err := assignOut(test.dest, test.v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src[0] = 'n' // field value buffers are reused
+	if i != 42 || s != "text" || string(b) != "text" || !ns.Valid || ns.String != "null string" {
+		t.Fatalf("values %d %q %q %v", i, s, b, ns)
+	}
+
+	if err := assignOut(&i, nil); err != nil || i != 0 {
+		t.Fatalf("value %d %v - expected zero value", i, err)
+	}
+	if err := assignOut(i, int64(1)); err == nil {
+		t.Fatal("invalid destination error expected")
+	}
+	if err := assignOut(&i, "text"); err == nil {
+		t.Fatal("assign error expected")
+	}
+}
+
+func TestAssignOutConversion(t *testing.T) {
+	type text string
+	var (
+		s   string
+		txt text
+		i   int
+		f32 float32
+		f64 float64
+		b   bool
+	)
+
+	tests := []struct {
+		dest     interface{}
+		v        driver.Value
+		expected interface{}
+	}{
+		{&s, int64(65), "65"},
+		{&s, float64(1.5), "1.5"},
+		{&s, true, "true"},
+		{&txt, "text", text("text")},
+		{&i, "42", 42},
+		{&i, float64(3), 3},
+		{&f64, int64(7), float64(7)},
+		{&f64, "2.5", float64(2.5)},
+		{&f32, float64(0.5), float32(0.5)},
+		{&b, "true", true},
+	}
+	for _, test := range tests {
+		if err := assignOut(test.dest, test.v); err != nil {
+			t.Fatalf("%T %v: %s", test.dest, test.v, err)
+		}
+		if v := reflect.ValueOf(test.dest).Elem().Interface(); v != test.expected {
+			t.Fatalf("%T %v: value %v - expected %v", test.dest, test.v, v, test.expected)
+		}
+	}
+
+	// lossy conversions
+	for _, test := range []struct {
+		dest interface{}
+		v    driver.Value
+	}{
+		{&i, float64(1.5)},
+		{&i, float64(1e20)},
+		{&i, "1.5"},
+		{&f32, float64(math.MaxFloat64)},
+		{&f64, int64(math.MaxInt64)},
+		{&b, int64(1)},
+	} {
+		if err := assignOut(test.dest, test.v); err == nil {
+			t.Fatalf("%T %v: conversion error expected", test.dest, test.v)
+		}
+	}
+}
+
+func TestAssignOutIntegerRange(t *testing.T) {
+	var (
+		i8  int8
+		i32 int32
+		u8  uint8
+		u64 uint64
+	)
+
+	tests := []struct {
+		dest interface{}
+		v    driver.Value
+		ok   bool
+	}{
+		{&i32, int64(math.MaxInt32), true},
+		{&i32, int64(math.MinInt32), true},
+		{&i32, int64(math.MaxInt32 + 1), false},
+		{&i32, int64(math.MinInt32 - 1), false},
+		{&i8, int64(-128), true},
+		{&i8, int64(128), false},
+		{&u8, int64(255), true},
+		{&u8, int64(256), false},
+		{&u8, int64(-1), false},
+		{&u64, int64(math.MaxInt64), true},
+		{&u64, int64(-1), false},
+	}
+	for _, test := range tests {
+		err := assignOut(test.dest, test.v)
+		if test.ok && err != nil {
+			t.Fatalf("%T %d: %s", test.dest, test.v, err)
+		}
+		if !test.ok && err == nil {
+			t.Fatalf("%T %d: out of range error expected", test.dest, test.v)
+		}
+	}
+	if i32 != math.MinInt32 || i8 != -128 || u8 != 255 || u64 != math.MaxInt64 {
+		t.Fatalf("values %d %d %d %d", i32, i8, u8, u64)
+	}
+}
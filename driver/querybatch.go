@@ -0,0 +1,131 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/SAP/go-hdb/driver/sqltrace"
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+// QueryBatchError is the error returned by Conn.QueryBatch if queries of the batch failed.
+// The rows of the failed queries are nil, the rows of the successful queries are returned and need to be closed.
+type QueryBatchError struct {
+	errs []error // query errors by query index (nil: query succeeded)
+}
+
+func (e *QueryBatchError) Error() string {
+	for i, err := range e.errs {
+		if err != nil {
+			return fmt.Sprintf("query batch: %d of %d queries failed - query %d: %s", e.NumFailed(), len(e.errs), i, err)
+		}
+	}
+	return "query batch: no query failed"
+}
+
+// NumFailed returns the number of failed queries.
+func (e *QueryBatchError) NumFailed() int {
+	n := 0
+	for _, err := range e.errs {
+		if err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// QueryErr returns the error of the query with index idx in the batch or nil, if the query succeeded.
+func (e *QueryBatchError) QueryErr(idx int) error {
+	return e.errs[idx]
+}
+
+func (c *conn) QueryBatch(ctx context.Context, queries []string) (rows []driver.Rows, err error) {
+	if c.session.IsBad() {
+		return nil, driver.ErrBadConn
+	}
+
+	hintQueries := make([]string, len(queries))
+	queryLogs := make([]*queryLog, len(queries))
+	for i, query := range queries {
+		if pos := parameterMarkerPos(query); pos != -1 {
+			return nil, fmt.Errorf("query batch: query %d: query parameters are not supported (parameter marker at position %d)", i, pos)
+		}
+		if hintQueries[i], err = c.hintQuery(ctx, query); err != nil {
+			return nil, err
+		}
+		sqltrace.Traceln(hintQueries[i])
+		queryLogs[i] = newQueryLog(c.connector, hintQueries[i])
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, c.connector)
+	defer cancel()
+	c.session.SetClientInfo(ctxClientInfo(ctx))
+	c.session.SetRowDecoder(ctxRowDecoder(ctx))
+	c.session.SetHoldCursor(ctxHoldCursor(ctx))
+	c.session.SetScrollableCursor(ctxScrollableCursor(ctx))
+
+	done := make(chan struct{})
+	go func() {
+		var results []p.QueryDirectResult
+		if results, err = c.session.QueryDirectBatch(hintQueries); err == nil {
+			rows, err = c.queryBatchRows(ctx, hintQueries, queryLogs, results)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-done:
+		return rows, err
+	}
+}
+
+// queryBatchRows returns the rows of the query batch results.
+func (c *conn) queryBatchRows(ctx context.Context, queries []string, queryLogs []*queryLog, results []p.QueryDirectResult) ([]driver.Rows, error) {
+	rows := make([]driver.Rows, len(results))
+	var errs []error
+
+	for i, r := range results {
+		var err error
+		switch {
+		case r.Err != nil:
+			err = r.Err
+		case r.ID == 0: // non select query
+			r.FieldValues.Release()
+			rows[i] = noResult
+		default:
+			rows[i], err = newQueryResult(c.session, r.ID, r.ResultFieldSet, r.FieldValues, r.Attrs, ctxFetchSize(ctx), ctxScrollableCursor(ctx), c.connector.DecimalFormat(), c.connector.DecimalConverter(), newQueryRetry(ctx, c.connector, c.session, queries[i], nil))
+		}
+		queryLogs[i].logQuery(rows[i], err)
+
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(results))
+			}
+			errs[i] = err
+		}
+	}
+
+	if errs != nil {
+		return rows, &QueryBatchError{errs: errs}
+	}
+	return rows, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"testing"
+)
+
+func TestQueryBatch(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	queries := []string{
+		"select 1 from dummy",
+		"select * from dummy_unknown",
+		"select 'a' from dummy union all select 'b' from dummy",
+	}
+
+	rows, err := conn.(Conn).QueryBatch(context.Background(), queries)
+	for _, r := range rows {
+		if r != nil {
+			defer r.Close()
+		}
+	}
+
+	batchErr, ok := err.(*QueryBatchError)
+	if !ok {
+		t.Fatalf("error %v - expected query batch error", err)
+	}
+	if batchErr.NumFailed() != 1 || batchErr.QueryErr(1) == nil || rows[1] != nil {
+		t.Fatalf("invalid query batch error %s", batchErr)
+	}
+
+	numRows := []int{1, 0, 2}
+	dest := make([]driver.Value, 1)
+	for i, r := range rows {
+		if r == nil {
+			continue
+		}
+		n := 0
+		for {
+			if err := r.Next(dest); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			n++
+		}
+		if n != numRows[i] {
+			t.Fatalf("query %d: number of rows %d - expected %d", i, n, numRows[i])
+		}
+	}
+
+	// query parameters are not supported
+	if _, err := conn.(Conn).QueryBatch(context.Background(), []string{"select ? from dummy"}); err == nil {
+		t.Fatal("query parameter error expected")
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+)
+
+// A Queryer executes queries returning rows. Queryer is implemented by sql.DB, sql.Conn and sql.Tx.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// RowOrError is an element of the channel returned by QueryChan. It carries either a row
+// or the error terminating the channel.
+type RowOrError struct {
+	Row []interface{}
+	Err error
+}
+
+/*
+QueryChan executes a query and returns a channel which receives the resultset rows.
+The rows are fetched by a go routine sending each row as soon as the receiver is ready (backpressure).
+
+The values of a row are typed according to the column types:
+  - int64, float64, string, []byte, time.Time
+  - *Decimal for decimal columns
+  - []byte for BLOB and BINTEXT lobs and string for other lobs (lobs are read completely)
+  - nil for NULL values.
+
+The channel is closed after the last row was sent or after a terminal error (RowOrError.Err) was sent.
+On cancellation of the context the go routine closes the resultset and the channel. Receivers stopping to read
+from the channel before it is closed need to cancel the context to release the resultset.
+*/
+func QueryChan(ctx context.Context, q Queryer, query string, args ...interface{}) (<-chan RowOrError, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	ch := make(chan RowOrError)
+
+	go func() {
+		defer close(ch)
+		defer rows.Close()
+
+		send := func(e RowOrError) bool {
+			select {
+			case ch <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		values := make([]interface{}, len(columnTypes))
+		dest := make([]interface{}, len(columnTypes))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				send(RowOrError{Err: err})
+				return
+			}
+			row := make([]interface{}, len(values))
+			for i, v := range values {
+				if row[i], err = chanValue(columnTypes[i], v); err != nil {
+					send(RowOrError{Err: err})
+					return
+				}
+			}
+			if !send(RowOrError{Row: row}) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			send(RowOrError{Err: err})
+		}
+	}()
+
+	return ch, nil
+}
+
+// chanValue converts a scanned driver value of a column into the row value type of QueryChan.
+func chanValue(ct *sql.ColumnType, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch ct.ScanType() {
+
+	case scanTypeString:
+		if b, ok := v.([]byte); ok {
+			return string(b), nil
+		}
+
+	case scanTypeDecimal:
+		d := new(Decimal)
+		if err := d.Scan(v); err != nil {
+			return nil, err
+		}
+		return d, nil
+
+	case scanTypeLob:
+		ws, ok := v.(writerSetter)
+		if !ok {
+			break
+		}
+		b, err := lobBytes(ws, nil) // empty lob: zero-length value (NULL: nil)
+		if err != nil {
+			return nil, err
+		}
+		switch ct.DatabaseTypeName() {
+		case "BLOB", "BINTEXT": // raw bytes
+			return b, nil
+		}
+		return string(b), nil
+
+	}
+	return v, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+func TestQueryChan(t *testing.T) {
+	const numRow = 100
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("queryChan_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, s nvarchar(20), d decimal, c nclob)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	// SQL Error 596 - LOB streaming is not permitted in auto-commit mode
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < numRow; i++ {
+		if _, err := tx.Exec(fmt.Sprintf("insert into %s.%s values (?, ?, ?, ?)", TestSchema, table), i, fmt.Sprintf("s%d", i), i, fmt.Sprintf("c%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	query := fmt.Sprintf("select i, s, d, c from %s.%s order by i", TestSchema, table)
+
+	ch, err := QueryChan(context.Background(), db, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := 0
+	for e := range ch {
+		if e.Err != nil {
+			t.Fatal(e.Err)
+		}
+		if e.Row[0] != int64(i) || e.Row[1] != fmt.Sprintf("s%d", i) || e.Row[3] != fmt.Sprintf("c%d", i) {
+			t.Fatalf("row %d: invalid values %v", i, e.Row)
+		}
+		if _, ok := e.Row[2].(*Decimal); !ok {
+			t.Fatalf("row %d: invalid decimal type %T", i, e.Row[2])
+		}
+		i++
+	}
+	if i != numRow {
+		t.Fatalf("number of rows %d - expected %d", i, numRow)
+	}
+
+	// cancel after first row: channel gets closed
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err = QueryChan(ctx, db, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	cancel()
+	for range ch {
+	}
+}
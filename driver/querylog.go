@@ -0,0 +1,97 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// QueryLogEntry contains the metadata of a statement execution passed to a QueryLogger.
+type QueryLogEntry struct {
+	Query    string        // SQL statement.
+	Exec     bool          // Statement executed by Exec (true) or Query (false).
+	Duration time.Duration // Duration of the execution (Query: including fetching the rows until the rows are closed).
+	Rows     int64         // Number of rows affected (Exec) or read (Query).
+	Err      error         // Execution error.
+}
+
+/*
+A QueryLogger is called for statements executed by Exec and Query on connections opened by a Connector
+(see Connector.SetQueryLogger), e.g. to write a log of slow statements:
+
+	connector.SetQueryLogger(func(e driver.QueryLogEntry) {
+		log.Printf("query %q: duration %s rows %d error %v", e.Query, e.Duration, e.Rows, e.Err)
+	})
+	connector.SetQueryLogThreshold(time.Second)
+
+Queries are logged when the rows are closed. In contrast to sqltrace, which traces the statements before
+the execution, the logger is called in the go routine of the execution and should therefore not block.
+*/
+type QueryLogger func(entry QueryLogEntry)
+
+// queryLog logs a statement execution, if a query logger is set (nil: no logging).
+type queryLog struct {
+	logger    QueryLogger
+	threshold time.Duration
+	query     string
+	start     time.Time
+}
+
+func newQueryLog(connector *Connector, query string) *queryLog {
+	if connector == nil {
+		return nil
+	}
+	logger := connector.QueryLogger()
+	if logger == nil {
+		return nil
+	}
+	return &queryLog{logger: logger, threshold: connector.QueryLogThreshold(), query: query, start: time.Now()}
+}
+
+func (l *queryLog) log(exec bool, rows int64, err error) {
+	if l == nil {
+		return
+	}
+	d := time.Since(l.start)
+	if d < l.threshold {
+		return
+	}
+	l.logger(QueryLogEntry{Query: l.query, Exec: exec, Duration: d, Rows: rows, Err: err})
+}
+
+func (l *queryLog) logExec(r driver.Result, err error) {
+	if l == nil || err == driver.ErrSkip {
+		return
+	}
+	var rows int64
+	if r != nil {
+		rows, _ = r.RowsAffected()
+	}
+	l.log(true, rows, err)
+}
+
+func (l *queryLog) logQuery(rows driver.Rows, err error) {
+	if l == nil || err == driver.ErrSkip {
+		return
+	}
+	if r, ok := rows.(*queryResult); ok && err == nil { // log on close
+		r.queryLog = l
+		return
+	}
+	l.log(false, 0, err)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/SAP/go-hdb/driver/sqltrace"
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+// queryRetry re-executes a query on a new session in case of network errors while fetching resultset rows.
+// The new session is set up like the connections of the connector (see setupSession).
+type queryRetry struct {
+	ctx       context.Context // context of the query caller (without query timeout)
+	connector *Connector
+	query     string
+	args      []driver.NamedValue
+	limit     int        // maximum number of re-executions
+	session   *p.Session // session of last re-execution
+}
+
+// newQueryRetry returns nil if re-execution is disabled or the query is executed inside a transaction.
+// As rows are fetched after the query timeout context of the query execution was canceled, the caller
+// context is kept and each re-execution gets a query timeout of its own.
+func newQueryRetry(ctx context.Context, connector *Connector, session *p.Session, query string, args []driver.NamedValue) *queryRetry {
+	if connector == nil {
+		return nil
+	}
+	limit := connector.FetchRetryLimit()
+	if limit == 0 || session.InTx() {
+		return nil
+	}
+	return &queryRetry{ctx: ctxCaller(ctx), connector: connector, query: query, args: args, limit: limit}
+}
+
+func (q *queryRetry) close() {
+	if q.session != nil {
+		q.session.Close()
+		q.session = nil
+	}
+}
+
+// reexecute executes the query on a new session and positions r so that the rows already returned are skipped.
+func (q *queryRetry) reexecute(r *queryResult) error {
+	var err error
+
+	for q.limit > 0 {
+		q.limit--
+		sqltrace.Tracef("re-execute query after network error: %s", q.query)
+		if err = q.execute(r); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (q *queryRetry) execute(r *queryResult) error {
+	ctx, cancel := withQueryTimeout(q.ctx, q.connector)
+	defer cancel()
+
+	conn, err := openConn(ctx, q.connector)
+	if err != nil {
+		return err
+	}
+	session := conn.session
+
+	var id uint64
+	resultFieldSet := r.resultFieldSet
+	var fieldValues *p.FieldValues
+	var attrs p.PartAttributes
+
+	dec := r.fieldValues.RowDecoder() // decode re-executed query like the original one
+	if len(q.args) == 0 {
+		session.SetRowDecoder(dec)
+		id, resultFieldSet, fieldValues, attrs, err = session.QueryDirect(q.query)
+	} else {
+		var stmtID uint64
+		var prmFieldSet *p.ParameterFieldSet
+		if _, stmtID, prmFieldSet, _, err = session.Prepare(q.query); err == nil {
+			session.SetRowDecoder(dec)
+			id, resultFieldSet, fieldValues, attrs, err = session.Query(stmtID, prmFieldSet, r.resultFieldSet, q.args)
+			session.DropStatementID(stmtID)
+		}
+	}
+	if err == nil && (resultFieldSet == nil || resultFieldSet.NumField() != r.resultFieldSet.NumField()) { // result schema changed
+		err = fmt.Errorf("re-executed query does not return the columns of the original query")
+	}
+	if err != nil {
+		fieldValues.Release()
+		session.Close()
+		return err
+	}
+
+	q.close()
+	q.session = session
+	r.fieldValues.Release()
+
+	r.session, r.id, r.resultFieldSet, r.fieldValues, r.attrs = session, id, resultFieldSet, fieldValues, attrs
+	r.pos, r.skip = 0, r.numRow
+	r.stmtCtx = newStatementContext(session.StatementContext())
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+// TopologyHost represents a host (index server) of a scale-out database system.
+type TopologyHost struct {
+	HostName         string
+	Port             int
+	VolumeID         int  // Volume id of the index server.
+	IsMaster         bool // Master index server.
+	IsCurrentSession bool // Host of the current connection.
+}
+
+func newTopologyHosts(hosts []p.TopologyHost) []TopologyHost {
+	r := make([]TopologyHost, len(hosts))
+	for i, h := range hosts {
+		r[i] = TopologyHost{HostName: h.HostName, Port: h.Port, VolumeID: h.VolumeID, IsMaster: h.IsMaster, IsCurrentSession: h.IsCurrentSession}
+	}
+	return r
+}
+
+func (c *conn) Topology() []TopologyHost {
+	return newTopologyHosts(c.session.Topology())
+}
+
+func (c *conn) StatementRouting(query string) ([]TopologyHost, error) {
+	if c.session.IsBad() {
+		return nil, driver.ErrBadConn
+	}
+
+	hosts, err := c.session.StatementRouting(query)
+	if err != nil {
+		return nil, err
+	}
+	return newTopologyHosts(hosts), nil
+}
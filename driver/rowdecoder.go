@@ -0,0 +1,173 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+// A WireType is the type code of a resultset field in wire format. The string representation
+// of a wire type corresponds to the database type name of the column (see sql.ColumnType.DatabaseTypeName).
+type WireType byte
+
+// Wire types of resultset fields.
+const (
+	WireTinyint    WireType = 1
+	WireSmallint   WireType = 2
+	WireInteger    WireType = 3
+	WireBigint     WireType = 4
+	WireDecimal    WireType = 5
+	WireReal       WireType = 6
+	WireDouble     WireType = 7
+	WireChar       WireType = 8
+	WireVarchar    WireType = 9
+	WireNchar      WireType = 10
+	WireNvarchar   WireType = 11
+	WireBinary     WireType = 12
+	WireVarbinary  WireType = 13
+	WireDate       WireType = 14
+	WireTime       WireType = 15
+	WireTimestamp  WireType = 16
+	WireClob       WireType = 25
+	WireNclob      WireType = 26
+	WireBlob       WireType = 27
+	WireText       WireType = 51
+	WireBintext    WireType = 53
+	WireLongdate   WireType = 61
+	WireSeconddate WireType = 62
+	WireDaydate    WireType = 63
+	WireSecondtime WireType = 64
+)
+
+func (t WireType) String() string {
+	return p.TypeCode(t).TypeName()
+}
+
+/*
+A RowDecoder decodes resultset rows of a query with a known (static) schema, e.g. implemented by generated
+data access code. In contrast to the default decoding the decoder reads the fields of a row
+with the WireReader method of the field wire type directly, avoiding the type code dispatching per field.
+
+The wire types of the decoder are checked against the resultset metadata: a mismatch fails the query.
+Please note that connector options affecting the field values (e.g. SetNullAsZeroValue, SetTrimChar, SetCharEncoding)
+are not applied to rows read by a row decoder.
+*/
+type RowDecoder interface {
+	// WireTypes returns the wire types of the resultset fields.
+	WireTypes() []WireType
+	// DecodeRow reads the fields of one row in resultset field order and stores the values in dest.
+	DecodeRow(r *WireReader, dest []driver.Value) error
+}
+
+// WithRowDecoder returns a copy of ctx with the row decoder applied to the resultset of the query executed with
+// the returned context.
+func WithRowDecoder(ctx context.Context, dec RowDecoder) context.Context {
+	return context.WithValue(ctx, rowDecoderCtxKey, dec)
+}
+
+// ctxRowDecoder returns the protocol row decoder of ctx or nil if not set.
+func ctxRowDecoder(ctx context.Context) p.RowDecoder {
+	dec, ok := ctx.Value(rowDecoderCtxKey).(RowDecoder)
+	if !ok || dec == nil {
+		return nil
+	}
+	wireTypes := dec.WireTypes()
+	tcs := make([]p.TypeCode, len(wireTypes))
+	for i, t := range wireTypes {
+		tcs[i] = p.TypeCode(t)
+	}
+	return &rowDecoder{dec: dec, tcs: tcs}
+}
+
+// rowDecoder adapts a RowDecoder to the protocol row decoder.
+type rowDecoder struct {
+	dec RowDecoder
+	tcs []p.TypeCode
+	r   WireReader
+}
+
+func (d *rowDecoder) TypeCodes() []p.TypeCode { return d.tcs }
+
+func (d *rowDecoder) DecodeRow(rd *p.FieldReader, dest []driver.Value) error {
+	d.r.rd = rd
+	return d.dec.DecodeRow(&d.r, dest)
+}
+
+// A WireReader reads the fields of a resultset row in wire format. Each method reads the next field
+// of the row, which needs to be of the wire type the method is named after. The null result is true
+// for database NULL values. Returned byte slices are only valid until the next call of Rows.Next.
+type WireReader struct {
+	rd *p.FieldReader
+}
+
+// Tinyint reads a WireTinyint field.
+func (r *WireReader) Tinyint() (v uint8, null bool) { return r.rd.Tinyint() }
+
+// Smallint reads a WireSmallint field.
+func (r *WireReader) Smallint() (v int16, null bool) { return r.rd.Smallint() }
+
+// Integer reads a WireInteger field.
+func (r *WireReader) Integer() (v int32, null bool) { return r.rd.Integer() }
+
+// Bigint reads a WireBigint field.
+func (r *WireReader) Bigint() (v int64, null bool) { return r.rd.Bigint() }
+
+// Real reads a WireReal field.
+func (r *WireReader) Real() (v float32, null bool) { return r.rd.Real() }
+
+// Double reads a WireDouble field.
+func (r *WireReader) Double() (v float64, null bool) { return r.rd.Double() }
+
+// Decimal reads a WireDecimal field. The value can be scanned by a Decimal.
+func (r *WireReader) Decimal() (v []byte, null bool) { return r.rd.Decimal() }
+
+// Date reads a WireDate field.
+func (r *WireReader) Date() (v time.Time, null bool) { return r.rd.Date() }
+
+// Time reads a WireTime field.
+func (r *WireReader) Time() (v time.Time, null bool) { return r.rd.Time() }
+
+// Timestamp reads a WireTimestamp field.
+func (r *WireReader) Timestamp() (v time.Time, null bool) { return r.rd.Timestamp() }
+
+// Longdate reads a WireLongdate field.
+func (r *WireReader) Longdate() (v time.Time, null bool) { return r.rd.Longdate() }
+
+// Seconddate reads a WireSeconddate field.
+func (r *WireReader) Seconddate() (v time.Time, null bool) { return r.rd.Seconddate() }
+
+// Daydate reads a WireDaydate field.
+func (r *WireReader) Daydate() (v time.Time, null bool) { return r.rd.Daydate() }
+
+// Secondtime reads a WireSecondtime field.
+func (r *WireReader) Secondtime() (v time.Time, null bool) { return r.rd.Secondtime() }
+
+// Chars reads a WireChar or WireVarchar field.
+func (r *WireReader) Chars() (v []byte, null bool) { return r.rd.Chars() }
+
+// NChars reads a WireNchar or WireNvarchar field as UTF-8 encoded bytes.
+func (r *WireReader) NChars() (v []byte, null bool) { return r.rd.NChars() }
+
+// Binary reads a WireBinary or WireVarbinary field.
+func (r *WireReader) Binary() (v []byte, null bool) { return r.rd.Binary() }
+
+// Value reads a field of wire type t like the default decoding, e.g. for lob fields.
+func (r *WireReader) Value(t WireType) (driver.Value, error) { return r.rd.Value(p.TypeCode(t)) }
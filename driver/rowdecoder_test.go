@@ -0,0 +1,77 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+type testRowDecoder struct{}
+
+func (d testRowDecoder) WireTypes() []WireType { return []WireType{WireInteger, WireNvarchar} }
+
+func (d testRowDecoder) DecodeRow(r *WireReader, dest []driver.Value) error {
+	dest[0], dest[1] = nil, nil
+	if i, null := r.Integer(); !null {
+		dest[0] = int64(i)
+	}
+	if b, null := r.NChars(); !null {
+		dest[1] = b
+	}
+	return nil
+}
+
+func TestRowDecoder(t *testing.T) {
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("rowDecoder_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, s nvarchar(20))", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), 42, "€"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRowDecoder(context.Background(), testRowDecoder{})
+
+	var i int
+	var s string
+	if err := db.QueryRowContext(ctx, fmt.Sprintf("select i, s from %s.%s", TestSchema, table)).Scan(&i, &s); err != nil {
+		t.Fatal(err)
+	}
+	if i != 42 || s != "€" {
+		t.Fatalf("values %d %q - expected %d %q", i, s, 42, "€")
+	}
+
+	// wire type mismatch
+	if err := db.QueryRowContext(ctx, fmt.Sprintf("select s, i from %s.%s", TestSchema, table)).Scan(&s, &i); err == nil {
+		t.Fatal("wire type mismatch error expected")
+	}
+
+	if WireNvarchar.String() != "NVARCHAR" {
+		t.Fatalf("wire type name %s - expected %s", WireNvarchar, "NVARCHAR")
+	}
+}
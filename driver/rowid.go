@@ -0,0 +1,63 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+)
+
+// RowIDColumn is the name of the hdb pseudo column identifying a table row.
+//
+//	select "$rowid$", ... from ...
+//	update ... where "$rowid$" = ?
+const RowIDColumn = `"$rowid$"`
+
+/*
+A RowID is the driver representation of a database row id ($rowid$ pseudo column value).
+
+A RowID can be used to select or update a row again, e.g. for optimistic locking. As the driver reuses the
+buffers of resultset rows, scanning into a RowID copies the row id value.
+*/
+type RowID []byte
+
+// Scan implements the database/sql/Scanner interface.
+func (r *RowID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case []byte:
+		*r = append((*r)[:0], src...)
+		return nil
+	}
+	return fmt.Errorf("rowid: invalid scan type %T", src)
+}
+
+// Value implements the database/sql/Valuer interface.
+func (r RowID) Value() (driver.Value, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return []byte(r), nil
+}
+
+// String implements the Stringer interface.
+func (r RowID) String() string {
+	return hex.EncodeToString(r)
+}
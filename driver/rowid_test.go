@@ -0,0 +1,98 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+func TestRowIDScan(t *testing.T) {
+	src := []byte{0x01, 0x02, 0x03}
+
+	var r RowID
+	if err := r.Scan(src); err != nil {
+		t.Fatal(err)
+	}
+	src[0] = 0xff // buffer reused by driver
+	if !bytes.Equal(r, []byte{0x01, 0x02, 0x03}) {
+		t.Fatalf("rowid %s not stable", r)
+	}
+	if r.String() != "010203" {
+		t.Fatalf("rowid %s - expected %s", r, "010203")
+	}
+
+	if err := r.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if r != nil {
+		t.Fatalf("rowid %s - expected nil", r)
+	}
+	if err := r.Scan("010203"); err == nil {
+		t.Fatal("invalid scan type error expected")
+	}
+}
+
+func TestRowID(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("rowid_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (i integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?)", TestSchema, table), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select %s, i from %s.%s order by i", RowIDColumn, TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	rowIDs := []RowID{}
+	for rows.Next() {
+		var rowID RowID
+		var i int
+		if err := rows.Scan(&rowID, &i); err != nil {
+			t.Fatal(err)
+		}
+		rowIDs = append(rowIDs, rowID)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// re-fetch rows by rowid
+	for i, rowID := range rowIDs {
+		var j int
+		if err := db.QueryRow(fmt.Sprintf("select i from %s.%s where %s = ?", TestSchema, table, RowIDColumn), rowID).Scan(&j); err != nil {
+			t.Fatal(err)
+		}
+		if j != i {
+			t.Fatalf("rowid %s: value %d - expected %d", rowID, j, i)
+		}
+	}
+}
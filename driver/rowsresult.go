@@ -0,0 +1,38 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+)
+
+/*
+RowsResult may be implemented by driver.Rows. It provides the result of the statement returning the rows,
+so that the number of affected rows of a data manipulation statement returning a resultset is
+available before the rows are read.
+
+The number of affected rows is part of the database reply returning the resultset and does not
+change while reading the rows.
+*/
+type RowsResult interface {
+	driver.Rows
+	Result() driver.Result
+}
+
+func (r *queryResult) Result() driver.Result {
+	return driver.RowsAffected(r.rowsAffected)
+}
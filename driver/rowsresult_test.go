@@ -0,0 +1,102 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestRowsResult(t *testing.T) {
+
+	const numRow = 3
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("rowsResult_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < numRow; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?)", TestSchema, table), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// statement returning a row count (update) and a resultset (select)
+	query := fmt.Sprintf("do begin update %[1]s.%[2]s set i = i + 10; select i from %[1]s.%[2]s order by i; end", TestSchema, table)
+	stmt, err := conn.(driver.ConnPrepareContext).PrepareContext(context.Background(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.(driver.StmtQueryContext).QueryContext(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	resultRows, ok := rows.(RowsResult)
+	if !ok {
+		t.Fatal("RowsResult expected")
+	}
+
+	// result is available before reading the rows
+	rowsAffected, err := resultRows.Result().RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rowsAffected != numRow {
+		t.Fatalf("rows affected %d - expected %d", rowsAffected, numRow)
+	}
+
+	dest := make([]driver.Value, 1)
+	for i := 0; ; i++ {
+		err := rows.Next(dest)
+		if err == io.EOF {
+			if i != numRow {
+				t.Fatalf("number of rows %d - expected %d", i, numRow)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dest[0] != int64(i+10) {
+			t.Fatalf("row %d: value %v - expected %d", i, dest[0], i+10)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"strconv"
+)
+
+/*
+ScanMap returns the current row of rows as map of the column values keyed by the column display names.
+
+The values are typed according to the column types like the row values of QueryChan. If several columns
+have the same display name, the names of the subsequent columns are extended by a sequence number
+(e.g. ID, ID_2, ID_3) not conflicting with the names of other columns.
+
+	for rows.Next() {
+		m, err := driver.ScanMap(rows)
+		if err != nil {
+			...
+		}
+		b, err := json.Marshal(m)
+		...
+	}
+*/
+func ScanMap(rows *sql.Rows) (map[string]interface{}, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(columnTypes))
+	dest := make([]interface{}, len(columnTypes))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = ct.Name()
+	}
+
+	m := make(map[string]interface{}, len(columnTypes))
+	for i, key := range mapKeys(columns) {
+		if m[key], err = chanValue(columnTypes[i], values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// mapKeys returns unique keys for the column names, whereby duplicate names are extended by a sequence number.
+func mapKeys(columns []string) []string {
+	names := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		names[column] = true
+	}
+
+	keys := make([]string, len(columns))
+	used := make(map[string]bool, len(columns))
+	for i, column := range columns {
+		key := column
+		for n := 2; used[key] || (key != column && names[key]); n++ { // extended name must not be the name of another column
+			key = column + "_" + strconv.Itoa(n)
+		}
+		used[key] = true
+		keys[i] = key
+	}
+	return keys
+}
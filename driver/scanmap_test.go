@@ -0,0 +1,70 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestMapKeys(t *testing.T) {
+	var data = []struct {
+		columns, keys []string
+	}{
+		{[]string{"A", "B"}, []string{"A", "B"}},
+		{[]string{"ID", "ID", "ID"}, []string{"ID", "ID_2", "ID_3"}},
+		{[]string{"ID", "ID", "ID_2"}, []string{"ID", "ID_3", "ID_2"}},
+		{[]string{"ID_2", "ID", "ID"}, []string{"ID_2", "ID", "ID_3"}},
+	}
+
+	for _, d := range data {
+		if keys := mapKeys(d.columns); !reflect.DeepEqual(keys, d.keys) {
+			t.Fatalf("columns %v: keys %v - expected %v", d.columns, keys, d.keys)
+		}
+	}
+}
+
+func TestScanMap(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("select 1 as id, 'a' as name, cast(null as varchar(10)) as name, to_blob(x'01') as b, to_nclob('c') as c from dummy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		t.Fatal("row expected")
+	}
+	m, err := ScanMap(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{"ID": int64(1), "NAME": "a", "NAME_2": nil, "B": []byte{1}, "C": "c"}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("map %v - expected %v", m, expected)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+// ErrCursorNotScrollable is the error raised if rows are scrolled which were not opened with a scrollable cursor.
+var ErrCursorNotScrollable = errors.New("resultset cursor is not scrollable (see WithScrollableCursor)")
+
+/*
+RowsScrollable is implemented by driver.Rows. It extends the forward only iteration of database/sql/driver rows
+by the backward iteration of resultsets opened with a scrollable cursor (see WithScrollableCursor):
+
+	FetchLast positions the cursor on the last row of the resultset and reads the row into dest.
+	FetchAbsolute positions the cursor on row pos (1: first row, negative: counted from the last row, -1: last row)
+	and reads the row into dest.
+	Prev moves the cursor to the row before the current row and reads the row into dest.
+
+The methods return io.EOF if no row is available (empty resultset, position out of range or cursor on the first row)
+and ErrCursorNotScrollable if the resultset was not opened with a scrollable cursor.
+
+Rows which are already fetched are read from the client buffer, otherwise Prev fetches the previous row from
+the database server. As the rows are not cached on client side, a query re-execution on network errors
+(see Connector.SetFetchRetryLimit) is not supported for scrolled resultsets.
+*/
+type RowsScrollable interface {
+	driver.Rows
+	FetchLast(dest []driver.Value) error
+	FetchAbsolute(pos int, dest []driver.Value) error
+	Prev(dest []driver.Value) error
+}
+
+// checkScrollable returns an error if the cursor of the resultset cannot be scrolled.
+func (r *queryResult) checkScrollable() error {
+	if !r.scrollable {
+		return ErrCursorNotScrollable
+	}
+	if r.session.IsBad() {
+		return driver.ErrBadConn
+	}
+	if r.lastErr != nil {
+		return r.lastErr
+	}
+	if r.attrs.ResultsetClosed() {
+		return fmt.Errorf("scrollable cursor: resultset %d is closed", r.id)
+	}
+	return nil
+}
+
+func (r *queryResult) FetchLast(dest []driver.Value) error {
+	if err := r.checkScrollable(); err != nil {
+		return err
+	}
+	return r.scroll(dest, func() (p.PartAttributes, error) {
+		return r.session.FetchLast(r.id, r.resultFieldSet, r.fieldValues)
+	})
+}
+
+func (r *queryResult) FetchAbsolute(pos int, dest []driver.Value) error {
+	if err := r.checkScrollable(); err != nil {
+		return err
+	}
+	if pos == 0 {
+		return io.EOF
+	}
+	return r.scroll(dest, func() (p.PartAttributes, error) {
+		return r.session.FetchAbsolute(r.id, pos, 1, r.resultFieldSet, r.fieldValues)
+	})
+}
+
+func (r *queryResult) Prev(dest []driver.Value) error {
+	if err := r.checkScrollable(); err != nil {
+		return err
+	}
+
+	switch {
+	case r.pos == 0: // no current row
+		return io.EOF
+	case r.pos > 1: // previous row already fetched
+		r.pos--
+		return r.readRow(r.pos-1, dest)
+	}
+
+	// current row is the first row of the fetched chunk:
+	// the server cursor is positioned on the last row of the chunk
+	offset := -r.fieldValues.NumRow()
+	return r.scroll(dest, func() (p.PartAttributes, error) {
+		return r.session.FetchRelative(r.id, offset, 1, r.resultFieldSet, r.fieldValues)
+	})
+}
+
+// scroll fetches a row by a scrolling fetch and reads the row into dest.
+func (r *queryResult) scroll(dest []driver.Value, fetch func() (p.PartAttributes, error)) error {
+	// rows position is not known anymore: do not re-execute query on network errors
+	r.retry = nil
+
+	attrs, err := fetch()
+	if err != nil {
+		r.lastErr = err
+		return err
+	}
+	r.attrs = attrs
+
+	if attrs.NoRows() || r.fieldValues.NumRow() == 0 {
+		r.pos = 0
+		return io.EOF
+	}
+
+	r.pos = 1
+	return r.readRow(0, dest)
+}
+
+// readRow reads the fetched row with index idx into dest.
+func (r *queryResult) readRow(idx int, dest []driver.Value) error {
+	r.fieldValues.Row(idx, dest)
+
+	if r.timeLocation != nil {
+		timesInLocation(dest, r.timeLocation)
+	}
+	if err := r.decodeArrayDecimals(dest); err != nil {
+		return err
+	}
+	return r.formatDecimals(dest)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// serverTimezoneOffsetQuery returns the offset of the database server local time to UTC in seconds.
+const serverTimezoneOffsetQuery = "select seconds_between(current_utctimestamp, current_timestamp) from dummy"
+
+func (c *conn) ServerLocation() (*time.Location, error) {
+	if c.serverLocation != nil {
+		return c.serverLocation, nil
+	}
+
+	if c.session.IsBad() {
+		return nil, driver.ErrBadConn
+	}
+
+	v, ok, err := queryValue(c.session, serverTimezoneOffsetQuery)
+	if err != nil {
+		return nil, err
+	}
+	offset, isInt := v.(int64)
+	if !ok || !isInt {
+		return nil, fmt.Errorf("invalid server timezone offset %v", v)
+	}
+	c.serverLocation = offsetLocation(offset)
+	return c.serverLocation, nil
+}
+
+// offsetLocation returns the fixed location of the UTC offset given in seconds rounded to minutes
+// (the local and UTC server timestamps might not be taken at exactly the same time).
+func offsetLocation(offset int64) *time.Location {
+	minutes := (offset + 30) / 60
+	if offset < 0 {
+		minutes = (offset - 30) / 60
+	}
+	if minutes == 0 {
+		return time.UTC
+	}
+	sign, abs := '+', minutes
+	if minutes < 0 {
+		sign, abs = '-', -minutes
+	}
+	return time.FixedZone(fmt.Sprintf("UTC%c%02d:%02d", sign, abs/60, abs%60), int(minutes*60))
+}
+
+// timesInLocation interprets the (UTC) time values of a row as wall clock values of location loc.
+func timesInLocation(dest []driver.Value, loc *time.Location) {
+	for i, v := range dest {
+		if t, ok := v.(time.Time); ok {
+			dest[i] = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+		}
+	}
+}
+
+// isLiteral returns true, if v is a string value. Date and time literals are parsed as time-zone-naive values
+// and are not converted to the server location.
+func isLiteral(v driver.Value) bool {
+	switch v.(type) {
+	case string, []byte:
+		return true
+	}
+	return false
+}
+
+// timeArgInLocation converts a time argument to the wall clock value of location loc, which is sent as
+// time-zone-naive database value.
+func timeArgInLocation(nv *driver.NamedValue, loc *time.Location) {
+	if t, ok := nv.Value.(time.Time); ok {
+		t = t.In(loc)
+		nv.Value = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	}
+}
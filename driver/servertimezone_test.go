@@ -0,0 +1,137 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestOffsetLocation(t *testing.T) {
+	testData := []struct {
+		offset int64
+		name   string
+		secs   int
+	}{
+		{0, "UTC", 0},
+		{-29, "UTC", 0},
+		{3601, "UTC+01:00", 3600},
+		{3599, "UTC+01:00", 3600},
+		{-18000, "UTC-05:00", -18000},
+		{20700, "UTC+05:45", 20700},
+	}
+
+	for _, d := range testData {
+		name, secs := time.Date(2020, time.January, 1, 0, 0, 0, 0, offsetLocation(d.offset)).Zone()
+		if name != d.name || secs != d.secs {
+			t.Fatalf("offset %d: zone %s %d - expected %s %d", d.offset, name, secs, d.name, d.secs)
+		}
+	}
+}
+
+func TestTimeInLocation(t *testing.T) {
+	loc := offsetLocation(3600)
+
+	// query result: wall clock in server location
+	dest := []driver.Value{time.Date(2020, time.June, 30, 10, 0, 0, 100, time.UTC), int64(1), nil}
+	timesInLocation(dest, loc)
+	if expected := time.Date(2020, time.June, 30, 10, 0, 0, 100, loc); dest[0] != expected {
+		t.Fatalf("value %v - expected %v", dest[0], expected)
+	}
+
+	// parameter: server location wall clock sent as naive value
+	nv := &driver.NamedValue{Value: time.Date(2020, time.June, 30, 10, 0, 0, 0, time.UTC)}
+	timeArgInLocation(nv, loc)
+	if expected := time.Date(2020, time.June, 30, 11, 0, 0, 0, time.UTC); nv.Value != expected {
+		t.Fatalf("value %v - expected %v", nv.Value, expected)
+	}
+}
+
+func TestServerTimezoneConversion(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector.SetServerTimezoneConversion(true)
+
+	// server location of a driver connection (sql.Conn.Raw requires go 1.13)
+	ctx := context.Background()
+	driverConn, err := connector.Connect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer driverConn.Close()
+	loc, err := driverConn.(Conn).ServerLocation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// time-zone-naive local server timestamp is returned in server location
+	var now, utcNow time.Time
+	if err := conn.QueryRowContext(ctx, "select current_timestamp, current_utctimestamp from dummy").Scan(&now, &utcNow); err != nil {
+		t.Fatal(err)
+	}
+	if now.Location().String() != loc.String() {
+		t.Fatalf("location %s - expected %s", now.Location(), loc)
+	}
+	if d := now.Sub(utcNow); d < -time.Minute || d > time.Minute {
+		t.Fatalf("local server time %s differs from utc server time %s", now, utcNow)
+	}
+
+	// round trip
+	table := RandomIdentifier("serverTimezone_")
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("create table %s.%s (t timestamp)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	in := time.Date(2020, time.June, 30, 10, 20, 30, 0, time.UTC)
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("insert into %s.%s values (?)", TestSchema, table), in); err != nil {
+		t.Fatal(err)
+	}
+	var out time.Time
+	if err := conn.QueryRowContext(ctx, fmt.Sprintf("select t from %s.%s", TestSchema, table)).Scan(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Equal(in) {
+		t.Fatalf("value %s - expected %s", out, in)
+	}
+
+	// procedure output parameter
+	procedure := RandomIdentifier("serverTimezoneProc_")
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("create procedure %s.%s (out t timestamp) as begin select t into t from %s.%s; end", TestSchema, procedure, TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	var outPrm time.Time
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("call %s.%s(?)", TestSchema, procedure), sql.Out{Dest: &outPrm}); err != nil {
+		t.Fatal(err)
+	}
+	if !outPrm.Equal(in) || outPrm.Location().String() != loc.String() {
+		t.Fatalf("output parameter %s - expected %s", outPrm, in.In(loc))
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"time"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+/*
+RowsStatementContext may be implemented by driver.Rows. It provides the server execution information
+of the query returned by the database server (statement context).
+
+Please note that query plan information like a plan hash is not part of the statement context.
+*/
+type RowsStatementContext interface {
+	driver.Rows
+	StatementContext() StatementContext
+}
+
+/*
+ResultStatementContext may be implemented by driver.Result. It provides the server execution information
+of the executed statement returned by the database server (statement context).
+
+As sql.Result does not give access to the driver result, the server execution information of the last statement
+executed on a connection is provided by Conn.StatementContext as well:
+
+	conn, err := db.Conn(ctx)
+	...
+	if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+		...
+	}
+	var sc driver.StatementContext
+	conn.Raw(func(driverConn interface{}) error {
+		sc = driverConn.(driver.Conn).StatementContext()
+		return nil
+	})
+*/
+type ResultStatementContext interface {
+	driver.Result
+	StatementContext() StatementContext
+}
+
+/*
+StatementContext contains server execution information of a statement. Values not provided
+by the database server are zero.
+
+The database server returns the execution information with each reply, so no request option is needed.
+CPU time and memory usage are only provided, if resource tracking is enabled on the database server
+(global.ini: resource_tracking, enable_tracking and memory_tracking).
+*/
+type StatementContext struct {
+	ServerExecutionTime time.Duration // Server processing time of the statement.
+	ServerCPUTime       time.Duration // Server CPU time of the statement.
+	ServerMemoryUsage   int64         // Server memory usage of the statement in bytes.
+}
+
+func newStatementContext(c p.StatementContext) StatementContext {
+	return StatementContext{ServerExecutionTime: c.ServerExecutionTime, ServerCPUTime: c.ServerCPUTime, ServerMemoryUsage: c.ServerMemoryUsage}
+}
+
+func (r *queryResult) StatementContext() StatementContext {
+	return r.stmtCtx
+}
+
+var _ ResultStatementContext = (*execResult)(nil)
+
+// execResult is the driver.Result of an executed statement providing the statement context.
+type execResult struct {
+	driver.Result
+	stmtCtx StatementContext
+}
+
+func (r *execResult) StatementContext() StatementContext {
+	return r.stmtCtx
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestStatementContext(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	rows, err := conn.(driver.QueryerContext).QueryContext(context.Background(), "select count(*) from objects", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	stmtCtxRows, ok := rows.(RowsStatementContext)
+	if !ok {
+		t.Fatal("RowsStatementContext expected")
+	}
+
+	if sc := stmtCtxRows.StatementContext(); sc.ServerExecutionTime <= 0 {
+		t.Fatalf("server execution time %s - expected > 0", sc.ServerExecutionTime)
+	}
+}
+
+func TestResultStatementContext(t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stmt, err := conn.(driver.ConnPrepareContext).PrepareContext(context.Background(), "set transaction isolation level read committed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.(driver.StmtExecContext).ExecContext(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmtCtxResult, ok := result.(ResultStatementContext)
+	if !ok {
+		t.Fatal("ResultStatementContext expected")
+	}
+
+	if sc := stmtCtxResult.StatementContext(); sc != conn.(Conn).StatementContext() {
+		t.Fatalf("statement context %v - expected %v", sc, conn.(Conn).StatementContext())
+	}
+}
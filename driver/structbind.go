@@ -0,0 +1,149 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// isStructArg reports whether v is a struct (or pointer to struct) argument whose fields are bound
+// to the statement parameters. Structs which are values on their own (driver.Valuer, time.Time, Lob, ...) and sql.Out arguments are excluded.
+func isStructArg(v interface{}) bool {
+	switch v.(type) {
+	case nil, sql.Out, time.Time, *time.Time, Lob, *Lob, big.Int, *big.Int, big.Rat, *big.Rat:
+		return false
+	}
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !t.Implements(valuerType) && !reflect.PtrTo(t).Implements(valuerType)
+}
+
+/*
+bindStructArg expands a single struct argument into one argument per statement input parameter.
+
+A struct field is bound to a parameter by name, if all input parameters are named (e.g. procedure parameters)
+and a field exists for each of the names. The names are compared case insensitive to the struct field names
+as defined for ScanStruct (struct field tag 'hdb' or struct field name). Otherwise the struct fields are bound
+to the parameters in struct field order. Nil pointer fields are bound as NULL values.
+
+	type Employee struct {
+		ID      int     `hdb:"ID"`
+		Name    string  `hdb:"NAME"`
+		Manager *string `hdb:"MANAGER"` // NULL if nil
+	}
+
+	db.Exec("insert into employee values (?, ?, ?)", Employee{ID: 1, Name: "Smith"})
+
+A struct argument is only supported as the only argument, all other argument lists are returned unchanged.
+*/
+func (s *stmt) bindStructArg(args []driver.NamedValue) ([]driver.NamedValue, error) {
+	if len(args) == 0 || args[0].Name != "" || !isStructArg(args[0].Value) {
+		return args, nil
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("bind struct: struct argument %T must be the only argument - %d arguments given", args[0].Value, len(args))
+	}
+
+	v := reflect.ValueOf(args[0].Value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("bind struct: invalid nil pointer argument %T", args[0].Value)
+		}
+		v = v.Elem()
+	}
+
+	list := structFieldList(v.Type())
+	numField := s.prmFieldSet.NumInputField()
+
+	indexes := structParameterIndexes(inputParameterNames(s.prmFieldSet), structFields(v.Type()))
+	if indexes == nil {
+		if len(list) != numField {
+			return nil, fmt.Errorf("bind struct: invalid number of fields %d in %s - %d expected", len(list), v.Type(), numField)
+		}
+		indexes = make([][]int, numField)
+		for i, f := range list {
+			indexes[i] = f.index
+		}
+	}
+
+	bindArgs := make([]driver.NamedValue, numField)
+	for i, index := range indexes {
+		value := fieldValueByIndex(v, index)
+		if isStructArg(value) {
+			return nil, fmt.Errorf("bind struct: invalid field type %T in %s", value, v.Type())
+		}
+		bindArgs[i] = driver.NamedValue{Ordinal: i + 1, Value: value}
+		if err := s.CheckNamedValue(&bindArgs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return bindArgs, nil
+}
+
+// inputParameterNames returns the names of the input parameters in parameter order.
+// Output parameters (e.g. procedure OUT parameters preceding IN parameters) are skipped.
+func inputParameterNames(prmFieldSet *p.ParameterFieldSet) []string {
+	names := make([]string, prmFieldSet.NumInputField())
+	for i := range names {
+		names[i] = prmFieldSet.InputField(i).Name()
+	}
+	return names
+}
+
+// structParameterIndexes returns the struct field index sequences of the input parameter names mapped by name
+// or nil, if not all input parameters can be mapped by name.
+func structParameterIndexes(names []string, fields map[string][]int) [][]int {
+	indexes := make([][]int, len(names))
+	for i, name := range names {
+		if name == "" {
+			return nil
+		}
+		index, ok := fields[strings.ToUpper(name)]
+		if !ok {
+			return nil
+		}
+		indexes[i] = index
+	}
+	return indexes
+}
+
+// fieldValueByIndex returns the value of the struct field of v with index sequence index.
+// Fields of nil pointers to embedded structs are returned as nil values.
+func fieldValueByIndex(v reflect.Value, index []int) interface{} {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v.Interface()
+}
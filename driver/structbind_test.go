@@ -0,0 +1,171 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIsStructArg(t *testing.T) {
+	testData := []struct {
+		v  interface{}
+		ok bool
+	}{
+		{testScanStruct{}, true},
+		{&testScanStruct{}, true},
+		{1, false},
+		{nil, false},
+		{time.Now(), false},
+		{Decimal{}, false},
+		{&BigInt{}, false},
+		{big.NewInt(1), false},
+		{NullDecimal{}, false},
+		{sql.NullString{}, false},
+		{Geometry{}, false},
+		{Lob{}, false},
+		{sql.Out{Dest: new(int)}, false},
+	}
+
+	for i, d := range testData {
+		if ok := isStructArg(d.v); ok != d.ok {
+			t.Fatalf("%d %T: struct argument %t - expected %t", i, d.v, ok, d.ok)
+		}
+	}
+}
+
+func TestBindStructArgMultiple(t *testing.T) {
+	s := &stmt{}
+	args := []driver.NamedValue{{Ordinal: 1, Value: testBindStruct{}}, {Ordinal: 2, Value: 1}}
+	if _, err := s.bindStructArg(args); err == nil {
+		t.Fatal("struct argument not being the only argument: error expected")
+	}
+}
+
+type testBindStruct struct {
+	ID      int
+	Name    string  `hdb:"NAME"`
+	Manager *string `hdb:"MANAGER"`
+	Ignored int     `hdb:"-"`
+}
+
+func TestBindStruct(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("bindStruct_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (id integer, name nvarchar(20), manager nvarchar(20))", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := "manager"
+	in := []testBindStruct{{ID: 1, Name: "name1", Manager: &manager}, {ID: 2, Name: "name2"}}
+
+	for _, e := range in {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?, ?)", TestSchema, table), e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// wrong number of fields
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?)", TestSchema, table), in[0]); err == nil {
+		t.Fatal("invalid number of fields error expected")
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select * from %s.%s where id <= ? order by id", TestSchema, table), struct{ ID int }{ID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	i := 0
+	for rows.Next() {
+		var out testBindStruct
+		if err := ScanStruct(rows, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.ID != in[i].ID || out.Name != in[i].Name || (out.Manager == nil) != (in[i].Manager == nil) {
+			t.Fatalf("row %d: %v - expected %v", i, out, in[i])
+		}
+		i++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if i != len(in) {
+		t.Fatalf("rows %d - expected %d", i, len(in))
+	}
+}
+
+func TestStructParameterIndexes(t *testing.T) {
+	v := struct {
+		B string
+		A int
+	}{}
+	fields := structFields(reflect.TypeOf(v))
+
+	if indexes := structParameterIndexes([]string{"a", "B"}, fields); !reflect.DeepEqual(indexes, [][]int{{1}, {0}}) {
+		t.Fatalf("indexes %v - expected %v", indexes, [][]int{{1}, {0}})
+	}
+	for _, names := range [][]string{{"A", ""}, {"A", "C"}} {
+		if indexes := structParameterIndexes(names, fields); indexes != nil {
+			t.Fatalf("names %v: indexes %v - expected nil", names, indexes)
+		}
+	}
+}
+
+func TestBindStructProcedure(t *testing.T) {
+	// output parameter preceding the input parameters
+	const proc = `create procedure %[1]s.%[2]s (out c integer, in a integer, in b nvarchar(10))
+language SQLSCRIPT as
+begin
+    c := :a * 10 + length(:b);
+end
+`
+
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	procedure := RandomIdentifier("bindStructProc_")
+	if _, err := db.Exec(fmt.Sprintf(proc, TestSchema, procedure)); err != nil {
+		t.Fatal(err)
+	}
+
+	arg := struct {
+		B string
+		A int
+	}{B: "xy", A: 4}
+
+	var c int
+	if err := db.QueryRow(fmt.Sprintf("call %s.%s(?, ?, ?)", TestSchema, procedure), arg).Scan(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c != 42 {
+		t.Fatalf("value %d - expected %d", c, 42)
+	}
+}
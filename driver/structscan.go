@@ -0,0 +1,181 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// structTagKey is the struct field tag key used by ScanStruct to map result columns to struct fields.
+const structTagKey = "hdb"
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+/*
+ScanStruct copies the columns of the current row of rows into the fields of the struct pointed to by dest.
+
+A column is mapped to a struct field by the column display name, which is compared case insensitive to
+- the name given by the struct field tag 'hdb' or
+- the name of the struct field, if no tag is provided.
+
+Unexported struct fields and struct fields tagged by `hdb:"-"` are ignored. Fields of embedded structs are handled
+like fields of the outer struct, whereby fields of the outer struct take precedence.
+To scan a database NULL value, a struct field needs to be a pointer (e.g. *string) or a type implementing
+the sql.Scanner interface (e.g. sql.NullString or NullDecimal). An error is returned, if a column cannot be mapped
+to a struct field.
+
+	type Employee struct {
+		ID      int     `hdb:"ID"`
+		Name    string  `hdb:"NAME"`
+		Manager *string `hdb:"MANAGER"` // nullable column
+		Salary  Decimal
+	}
+
+	for rows.Next() {
+		var e Employee
+		if err := driver.ScanStruct(rows, &e); err != nil {
+			...
+		}
+	}
+*/
+func ScanStruct(rows *sql.Rows, dest interface{}) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("scan struct: invalid destination type %T - pointer to struct expected", dest)
+	}
+	v := rv.Elem()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	fields := structFields(v.Type())
+
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		index, ok := fields[strings.ToUpper(column)]
+		if !ok {
+			return fmt.Errorf("scan struct: missing destination field for column %s in %s", column, v.Type())
+		}
+		args[i] = fieldByIndex(v, index).Addr().Interface()
+	}
+	return rows.Scan(args...)
+}
+
+// structFields returns the field index sequences of the struct type t by upper case column name.
+func structFields(t reflect.Type) map[string][]int {
+	list := structFieldList(t)
+	fields := make(map[string][]int, len(list))
+	for _, f := range list {
+		fields[f.name] = f.index
+	}
+	return fields
+}
+
+// structField is a mapped struct field given by upper case name and field index sequence.
+type structField struct {
+	name  string
+	index []int
+}
+
+// structFieldList returns the mapped fields of the struct type t in struct field declaration order,
+// whereby the fields of an embedded struct are listed at the position of the embedded struct (like encoding/json).
+// If a name is mapped more than once, the field with the shallowest embedding depth takes precedence
+// and in case of equal depth the field declared first.
+func structFieldList(t reflect.Type) []structField {
+	var list []structField
+	collectStructFields(t, nil, map[reflect.Type]bool{t: true}, &list)
+
+	depth := make(map[string]int, len(list))
+	for _, f := range list {
+		if d, ok := depth[f.name]; !ok || len(f.index) < d {
+			depth[f.name] = len(f.index)
+		}
+	}
+
+	fields := list[:0]
+	for _, f := range list {
+		if d, ok := depth[f.name]; ok && d == len(f.index) {
+			fields = append(fields, f)
+			delete(depth, f.name)
+		}
+	}
+	return fields
+}
+
+// collectStructFields appends all mapped fields of the struct type t (including duplicate names)
+// to list in struct field declaration order. index is the index sequence of t and visited contains the
+// struct types of the embedding path, so that recursively embedded structs are not expanded again.
+func collectStructFields(t reflect.Type, index []int, visited map[reflect.Type]bool, list *[]structField) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get(structTagKey)
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			isPtr := ft.Kind() == reflect.Ptr
+			if isPtr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !reflect.PtrTo(ft).Implements(scannerType) {
+				if !(isPtr && f.PkgPath != "") && !visited[ft] { // nil pointer of unexported embedded struct cannot be set
+					visited[ft] = true
+					collectStructFields(ft, fieldIndex, visited, list)
+					delete(visited, ft)
+				}
+				continue
+			}
+		}
+
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+		*list = append(*list, structField{name: strings.ToUpper(name), index: fieldIndex})
+	}
+}
+
+// fieldByIndex returns the struct field of v with index sequence index.
+// In contrast to reflect.Value.FieldByIndex nil pointers to embedded structs are allocated.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testScanBase struct {
+	ID   int
+	Name string `hdb:"BASE_NAME"`
+}
+
+// exported: pointers to unexported embedded structs are not supported
+type ScanTestAudit struct {
+	Changed *string
+}
+
+type testScanStruct struct {
+	testScanBase
+	*ScanTestAudit
+	Name       *string `hdb:"NAME"`
+	Amount     NullDecimal
+	Ignored    int `hdb:"-"`
+	unexported int
+}
+
+func TestStructFields(t *testing.T) {
+	fields := structFields(reflect.TypeOf(testScanStruct{}))
+
+	expected := map[string][]int{
+		"ID":        {0, 0},
+		"BASE_NAME": {0, 1},
+		"CHANGED":   {1, 0},
+		"NAME":      {2},
+		"AMOUNT":    {3},
+	}
+
+	if !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("fields %v - expected %v", fields, expected)
+	}
+
+	s := new(testScanStruct)
+	if _, ok := fieldByIndex(reflect.ValueOf(s).Elem(), fields["CHANGED"]).Addr().Interface().(**string); !ok {
+		t.Fatal("invalid field type")
+	}
+	if s.ScanTestAudit == nil {
+		t.Fatal("embedded struct pointer not allocated")
+	}
+}
+
+func TestStructFieldList(t *testing.T) {
+	list := structFieldList(reflect.TypeOf(testScanStruct{}))
+
+	expected := []structField{
+		{name: "ID", index: []int{0, 0}},
+		{name: "BASE_NAME", index: []int{0, 1}},
+		{name: "CHANGED", index: []int{1, 0}},
+		{name: "NAME", index: []int{2}},
+		{name: "AMOUNT", index: []int{3}},
+	}
+
+	if !reflect.DeepEqual(list, expected) {
+		t.Fatalf("fields %v - expected %v", list, expected)
+	}
+
+	// outer field dominates embedded field of same name
+	type dominant struct {
+		testScanBase
+		ID string
+	}
+	list = structFieldList(reflect.TypeOf(dominant{}))
+
+	expected = []structField{
+		{name: "BASE_NAME", index: []int{0, 1}},
+		{name: "ID", index: []int{1}},
+	}
+
+	if !reflect.DeepEqual(list, expected) {
+		t.Fatalf("fields %v - expected %v", list, expected)
+	}
+}
+
+func TestScanStruct(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("scanStruct_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (id integer, base_name nvarchar(20), changed nvarchar(20), name nvarchar(20), amount decimal)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (1, 'base', null, 'name', 1.5)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (2, 'base', 'changed', null, null)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select id, base_name, changed, name, amount from %s.%s order by id", TestSchema, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var result []*testScanStruct
+	for rows.Next() {
+		s := new(testScanStruct)
+		if err := ScanStruct(rows, s); err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("number of rows %d - expected %d", len(result), 2)
+	}
+
+	s0, s1 := result[0], result[1]
+	switch {
+	case s0.ID != 1 || s0.testScanBase.Name != "base" || s0.Changed != nil || s0.Name == nil || *s0.Name != "name" || !s0.Amount.Valid:
+		t.Fatalf("invalid row %v", s0)
+	case s1.ID != 2 || s1.testScanBase.Name != "base" || s1.Changed == nil || *s1.Changed != "changed" || s1.Name != nil || s1.Amount.Valid:
+		t.Fatalf("invalid row %v", s1)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"time"
+)
+
+// systemTimeFormat is the timestamp literal format with the 100 nanosecond precision of database timestamps.
+const systemTimeFormat = "2006-01-02 15:04:05.0000000"
+
+// MaxSystemTime is the validity end (row end column value) of the current versions of the rows
+// of a system-versioned table.
+var MaxSystemTime = time.Date(9999, time.December, 31, 23, 59, 59, 999999900, time.UTC)
+
+/*
+SystemTimeAsOf returns the clause to query a system-versioned table at point in time t.
+The clause is built from the UTC value of t, so it can be included safely into a query string:
+
+	query := fmt.Sprintf("select * from employee %s where id = ?", driver.SystemTimeAsOf(t))
+
+The validity columns (row start and row end) of system-versioned tables are timestamp columns and
+are scanned like any other time.Time value. The row end column of the current row versions is MaxSystemTime.
+*/
+func SystemTimeAsOf(t time.Time) string {
+	return fmt.Sprintf("for system_time as of '%s'", t.UTC().Format(systemTimeFormat))
+}
+
+/*
+SystemTimeFromTo returns the clause to query all versions of the rows of a system-versioned table
+valid within the time range from (inclusive) to (exclusive).
+*/
+func SystemTimeFromTo(from, to time.Time) string {
+	return fmt.Sprintf("for system_time from '%s' to '%s'", from.UTC().Format(systemTimeFormat), to.UTC().Format(systemTimeFormat))
+}
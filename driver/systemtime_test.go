@@ -0,0 +1,96 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSystemTimeClause(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	from := time.Date(2020, time.June, 30, 11, 20, 30, 123456789, loc)
+	to := time.Date(2021, time.January, 1, 1, 0, 0, 0, loc)
+
+	if s, expected := SystemTimeAsOf(from), "for system_time as of '2020-06-30 10:20:30.1234567'"; s != expected {
+		t.Fatalf("clause %s - expected %s", s, expected)
+	}
+	if s, expected := SystemTimeFromTo(from, to), "for system_time from '2020-06-30 10:20:30.1234567' to '2021-01-01 00:00:00.0000000'"; s != expected {
+		t.Fatalf("clause %s - expected %s", s, expected)
+	}
+}
+
+func TestSystemVersionedTable(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("systemTime_")
+	historyTable := RandomIdentifier("systemTimeHistory_")
+
+	if _, err := db.Exec(fmt.Sprintf("create column table %s.%s (id integer, name nvarchar(20), validfrom timestamp not null, validto timestamp not null)", TestSchema, historyTable)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf(`create column table %[1]s.%[2]s (id integer primary key, name nvarchar(20),
+validfrom timestamp not null generated always as row start, validto timestamp not null generated always as row end,
+period for system_time (validfrom, validto)) with system versioning history table %[1]s.%[3]s`, TestSchema, table, historyTable)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s (id, name) values (?, ?)", TestSchema, table), 1, "initial"); err != nil {
+		t.Fatal(err)
+	}
+
+	var asOf time.Time
+	if err := db.QueryRow("select current_utctimestamp from dummy").Scan(&asOf); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("update %s.%s set name = ? where id = ?", TestSchema, table), "updated", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var name string
+	var validFrom, validTo time.Time
+
+	if err := db.QueryRow(fmt.Sprintf("select name, validfrom, validto from %s.%s where id = 1", TestSchema, table)).Scan(&name, &validFrom, &validTo); err != nil {
+		t.Fatal(err)
+	}
+	if name != "updated" {
+		t.Fatalf("name %s - expected %s", name, "updated")
+	}
+	if !validTo.Equal(MaxSystemTime) {
+		t.Fatalf("validto %s - expected %s", validTo, MaxSystemTime)
+	}
+	if !validFrom.Before(validTo) {
+		t.Fatalf("validfrom %s not before validto %s", validFrom, validTo)
+	}
+
+	if err := db.QueryRow(fmt.Sprintf("select name, validfrom, validto from %s.%s %s where id = 1", TestSchema, table, SystemTimeAsOf(asOf))).Scan(&name, &validFrom, &validTo); err != nil {
+		t.Fatal(err)
+	}
+	if name != "initial" {
+		t.Fatalf("name %s - expected %s", name, "initial")
+	}
+	if validTo.Equal(MaxSystemTime) || validFrom.After(asOf) {
+		t.Fatalf("invalid validity period %s - %s for %s", validFrom, validTo, asOf)
+	}
+}
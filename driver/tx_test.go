@@ -17,9 +17,12 @@ limitations under the License.
 package driver
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestTransactionCommit(t *testing.T) {
@@ -140,3 +143,139 @@ func TestTransactionRollback(t *testing.T) {
 		t.Fatal(fmt.Errorf("tx: invalid number of records %d - 0 expected", i))
 	}
 }
+
+func TestTransactionLockWaitTimeout(t *testing.T) {
+
+	const lockWaitTimeout = 100 * time.Millisecond
+
+	db1, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db1.Close()
+
+	db2, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db2.Close()
+
+	table := RandomIdentifier("testTxLockWaitTimeout_")
+	if _, err := db1.Exec(fmt.Sprintf("create table %s.%s (i tinyint)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db1.Exec(fmt.Sprintf("insert into %s.%s values(42)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	tx1, err := db1.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx1.Rollback()
+
+	//lock record in transaction 1
+	if _, err := tx1.Exec(fmt.Sprintf("update %s.%s set i = 1", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	tx2, err := db2.BeginTx(WithLockWaitTimeout(context.Background(), lockWaitTimeout), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//update locked record in transaction 2
+	start := time.Now()
+	if _, err := tx2.Exec(fmt.Sprintf("update %s.%s set i = 2", TestSchema, table)); err == nil {
+		t.Fatal("lock wait timeout error expected")
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Fatalf("lock wait timeout exceeded: %s", d)
+	}
+
+	//rollback restores session lock wait timeout
+	if err := tx2.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db2.BeginTx(WithLockWaitTimeout(context.Background(), -1), nil); err != ErrInvalidLockWaitTimeout {
+		t.Fatalf("error %v - expected %v", err, ErrInvalidLockWaitTimeout)
+	}
+}
+
+func TestTransactionID(t *testing.T) {
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	hdbConn, ok := c.(Conn)
+	if !ok {
+		t.Fatal("Conn expected")
+	}
+
+	if _, err := hdbConn.TransactionID(); err != ErrNoTransaction {
+		t.Fatalf("error %v - expected %v", err, ErrNoTransaction)
+	}
+
+	tx, err := c.(driver.ConnBeginTx).BeginTx(context.Background(), driver.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	id, err := hdbConn.TransactionID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= 0 {
+		t.Fatalf("invalid transaction id %d", id)
+	}
+}
+
+func TestTransactionRestoreLockWaitTimeout(t *testing.T) {
+
+	const sessionLockWaitTimeout = 5 * time.Second
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// lock wait timeout set in session
+	if err := connector.SetSessionStatements([]string{fmt.Sprintf(lockWaitTimeoutStmt, sessionLockWaitTimeout/time.Millisecond)}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	hdbConn := c.(*conn)
+
+	if timeout, err := hdbConn.sessionLockWaitTimeout(); err != nil || timeout != sessionLockWaitTimeout {
+		t.Fatalf("session lock wait timeout %s error %v - expected %s", timeout, err, sessionLockWaitTimeout)
+	}
+
+	tx, err := hdbConn.BeginTx(WithLockWaitTimeout(context.Background(), 100*time.Millisecond), driver.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeout, err := hdbConn.sessionLockWaitTimeout(); err != nil || timeout != 100*time.Millisecond {
+		t.Fatalf("transaction lock wait timeout %s error %v - expected %s", timeout, err, 100*time.Millisecond)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	// rollback restores the session value instead of the hdb default
+	if timeout, err := hdbConn.sessionLockWaitTimeout(); err != nil || timeout != sessionLockWaitTimeout {
+		t.Fatalf("session lock wait timeout %s error %v - expected %s", timeout, err, sessionLockWaitTimeout)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+/*
+NullOf returns a NULL parameter value of database type t. In contrast to a nil argument, which is sent as NULL value
+of the parameter type returned by the database server when preparing the statement, the typed NULL value is sent
+with the type code t, e.g. for parameters the database server cannot derive a type for:
+
+	db.Exec("call proc(?)", driver.NullOf(driver.WireNvarchar))
+
+The value is passed to the database server as is, so t needs to be a type the database server can convert
+to the parameter type.
+*/
+func NullOf(t WireType) driver.Value {
+	return p.TypedNull(t)
+}
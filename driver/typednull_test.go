@@ -0,0 +1,50 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+func TestNullOf(t *testing.T) {
+	db, err := sql.Open(DriverName, TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := RandomIdentifier("nullOf_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer, s nvarchar(20), d decimal)", TestSchema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s.%s values (?, ?, ?)", TestSchema, table), NullOf(WireInteger), NullOf(WireNvarchar), NullOf(WireDecimal)); err != nil {
+		t.Fatal(err)
+	}
+
+	var i sql.NullInt64
+	var s sql.NullString
+	var d NullDecimal
+	if err := db.QueryRow(fmt.Sprintf("select i, s, d from %s.%s", TestSchema, table)).Scan(&i, &s, &d); err != nil {
+		t.Fatal(err)
+	}
+	if i.Valid || s.Valid || d.Valid {
+		t.Fatalf("null values expected: %v %v %v", i, s, d)
+	}
+}
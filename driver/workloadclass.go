@@ -0,0 +1,98 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxWorkloadClassLength is the maximum length (characters) of hdb identifiers.
+const maxWorkloadClassLength = 127
+
+const workloadClassHint = "\nwith hint(workload_class(%s))"
+
+var (
+	// statements supporting hints (leading comments are skipped)
+	reHintStatement = regexp.MustCompile(`(?is)^(?:\s|--[^\n]*\n|/\*.*?\*/)*(select|with|insert|update|delete|upsert|replace|merge|call)\b`)
+	reHintClause    = regexp.MustCompile(`(?i)\bwith\s+hint\s*\(`)
+	// string literals, delimited identifiers and comments, which might contain hint like text
+	reHintIgnore = regexp.MustCompile(`(?s)'(?:[^']|'')*'|"(?:[^"]|"")*"|--[^\n]*|/\*.*?\*/`)
+)
+
+// checkWorkloadClass returns an error if class is not a valid workload class name.
+func checkWorkloadClass(class string) error {
+	if class == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(class) > maxWorkloadClassLength {
+		return fmt.Errorf("invalid workload class %q: name exceeds %d characters", class, maxWorkloadClassLength)
+	}
+	for _, r := range class {
+		if r == '"' || r == utf8.RuneError || unicode.IsControl(r) {
+			return fmt.Errorf("invalid workload class %q: invalid character %q", class, r)
+		}
+	}
+	return nil
+}
+
+/*
+WithWorkloadClass returns a copy of ctx with the workload class of statements prepared or directly executed
+with the returned context, overriding the connector workload class (see Connector.SetWorkloadClass).
+An empty class disables the workload class for the statements.
+
+The class name is case sensitive. An error is returned by the statement execution if class is not a valid
+workload class name.
+*/
+func WithWorkloadClass(ctx context.Context, class string) context.Context {
+	return context.WithValue(ctx, workloadClassCtxKey, class)
+}
+
+// ctxWorkloadClass returns the workload class of ctx and true, if set.
+func ctxWorkloadClass(ctx context.Context) (string, bool) {
+	class, ok := ctx.Value(workloadClassCtxKey).(string)
+	return class, ok
+}
+
+// workloadClassQuery returns query with a workload class hint. Queries not supporting hints or already
+// containing a hint clause are returned unchanged. A trailing statement terminator is removed, as the
+// hint clause needs to be part of the statement.
+func workloadClassQuery(query, class string) string {
+	if class == "" || !reHintStatement.MatchString(query) || reHintClause.MatchString(reHintIgnore.ReplaceAllString(query, " ")) {
+		return query
+	}
+	query = strings.TrimRightFunc(query, unicode.IsSpace)
+	query = strings.TrimRightFunc(strings.TrimSuffix(query, ";"), unicode.IsSpace)
+	return query + fmt.Sprintf(workloadClassHint, quoteIdentifier(class))
+}
+
+// hintQuery returns query with the workload class hint of ctx or the connector.
+func (c *conn) hintQuery(ctx context.Context, query string) (string, error) {
+	class, ok := ctxWorkloadClass(ctx)
+	if ok {
+		if err := checkWorkloadClass(class); err != nil {
+			return "", err
+		}
+	} else {
+		class = c.connector.WorkloadClass()
+	}
+	return workloadClassQuery(query, class), nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorkloadClassQuery(t *testing.T) {
+	var data = []struct {
+		query, class, hinted string
+	}{
+		{"select * from dummy", "", "select * from dummy"},
+		{"select * from dummy ", "batch", "select * from dummy\nwith hint(workload_class(\"batch\"))"},
+		{" Insert into t values (?)", "Batch", " Insert into t values (?)\nwith hint(workload_class(\"Batch\"))"},
+		{"select * from dummy -- comment", "batch", "select * from dummy -- comment\nwith hint(workload_class(\"batch\"))"},
+		{"select * from dummy with hint (no_cs_join)", "batch", "select * from dummy with hint (no_cs_join)"}, // hint clause
+		{"create table t (i integer)", "batch", "create table t (i integer)"},                                 // no hints supported
+		{"selection", "batch", "selection"},
+		{"select * from dummy;\n", "batch", "select * from dummy\nwith hint(workload_class(\"batch\"))"},                                    // statement terminator
+		{"select 'with hint(x)' from dummy", "batch", "select 'with hint(x)' from dummy\nwith hint(workload_class(\"batch\"))"},             // hint in literal
+		{"select 'it''s' from dummy with hint(no_cs_join)", "batch", "select 'it''s' from dummy with hint(no_cs_join)"},                     // escaped quote
+		{"/* with hint(x) */ select * from dummy", "batch", "/* with hint(x) */ select * from dummy\nwith hint(workload_class(\"batch\"))"}, // leading comment
+	}
+
+	for i, d := range data {
+		if hinted := workloadClassQuery(d.query, d.class); hinted != d.hinted {
+			t.Fatalf("%d: query %q - expected %q", i, hinted, d.hinted)
+		}
+	}
+}
+
+func TestCheckWorkloadClass(t *testing.T) {
+	for _, class := range []string{"", "batch", "Batch Jobs", "wlc_ä", strings.Repeat("x", maxWorkloadClassLength)} {
+		if err := checkWorkloadClass(class); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, class := range []string{`a"b`, "a\nb", "a\x00b", "\xff", strings.Repeat("x", maxWorkloadClassLength+1)} {
+		if err := checkWorkloadClass(class); err == nil {
+			t.Fatalf("workload class %q: error expected", class)
+		}
+	}
+}
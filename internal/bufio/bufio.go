@@ -31,6 +31,7 @@ import (
 type Reader struct {
 	rd  *bufio.Reader
 	err error
+	cnt int     // number of bytes read
 	b   [8]byte // scratch buffer (8 Bytes)
 	tr  transform.Transformer
 }
@@ -51,6 +52,26 @@ func NewReaderSize(r io.Reader, size int) *Reader {
 	}
 }
 
+// Reset discards any buffered data, resets the error and switches the reader to read from r.
+func (r *Reader) Reset(rd io.Reader) {
+	r.rd.Reset(rd)
+	r.err = nil
+	r.cnt = 0
+}
+
+// InputOffset returns the number of bytes read (including skipped bytes) since the creation or the last reset of the reader.
+func (r *Reader) InputOffset() int {
+	return r.cnt
+}
+
+// readFull reads len(p) bytes into p and counts the read bytes.
+func (r *Reader) readFull(p []byte) error {
+	var n int
+	n, r.err = io.ReadFull(r.rd, p)
+	r.cnt += n
+	return r.err
+}
+
 // GetError returns reader error
 func (r *Reader) GetError() error {
 	err := r.err
@@ -63,7 +84,9 @@ func (r *Reader) Skip(cnt int) {
 	if r.err != nil {
 		return
 	}
-	_, r.err = r.rd.Discard(cnt)
+	var n int
+	n, r.err = r.rd.Discard(cnt)
+	r.cnt += n
 }
 
 // ReadB reads and returns a byte.
@@ -72,7 +95,9 @@ func (r *Reader) ReadB() byte { // ReadB as sig differs from ReadByte (vet issue
 		return 0
 	}
 	var b byte
-	b, r.err = r.rd.ReadByte()
+	if b, r.err = r.rd.ReadByte(); r.err == nil {
+		r.cnt++
+	}
 	return b
 }
 
@@ -81,7 +106,7 @@ func (r *Reader) ReadFull(p []byte) {
 	if r.err != nil {
 		return
 	}
-	_, r.err = io.ReadFull(r.rd, p)
+	r.readFull(p)
 }
 
 // ReadBool reads and returns a boolean.
@@ -102,7 +127,7 @@ func (r *Reader) ReadInt16() int16 {
 	if r.err != nil {
 		return 0
 	}
-	if _, r.err = io.ReadFull(r.rd, r.b[:2]); r.err != nil {
+	if r.readFull(r.b[:2]) != nil {
 		return 0
 	}
 	return int16(binary.LittleEndian.Uint16(r.b[:2]))
@@ -113,7 +138,7 @@ func (r *Reader) ReadUint16() uint16 {
 	if r.err != nil {
 		return 0
 	}
-	if _, r.err = io.ReadFull(r.rd, r.b[:2]); r.err != nil {
+	if r.readFull(r.b[:2]) != nil {
 		return 0
 	}
 	return binary.LittleEndian.Uint16(r.b[:2])
@@ -124,7 +149,7 @@ func (r *Reader) ReadInt32() int32 {
 	if r.err != nil {
 		return 0
 	}
-	if _, r.err = io.ReadFull(r.rd, r.b[:4]); r.err != nil {
+	if r.readFull(r.b[:4]) != nil {
 		return 0
 	}
 	return int32(binary.LittleEndian.Uint32(r.b[:4]))
@@ -135,7 +160,7 @@ func (r *Reader) ReadUint32() uint32 {
 	if r.err != nil {
 		return 0
 	}
-	if _, r.err = io.ReadFull(r.rd, r.b[:4]); r.err != nil {
+	if r.readFull(r.b[:4]) != nil {
 		return 0
 	}
 	return binary.LittleEndian.Uint32(r.b[:4])
@@ -146,7 +171,7 @@ func (r *Reader) ReadInt64() int64 {
 	if r.err != nil {
 		return 0
 	}
-	if _, r.err = io.ReadFull(r.rd, r.b[:8]); r.err != nil {
+	if r.readFull(r.b[:8]) != nil {
 		return 0
 	}
 	return int64(binary.LittleEndian.Uint64(r.b[:8]))
@@ -157,7 +182,7 @@ func (r *Reader) ReadUint64() uint64 {
 	if r.err != nil {
 		return 0
 	}
-	if _, r.err = io.ReadFull(r.rd, r.b[:8]); r.err != nil {
+	if r.readFull(r.b[:8]) != nil {
 		return 0
 	}
 	return binary.LittleEndian.Uint64(r.b[:8])
@@ -168,7 +193,7 @@ func (r *Reader) ReadFloat32() float32 {
 	if r.err != nil {
 		return 0
 	}
-	if _, r.err = io.ReadFull(r.rd, r.b[:4]); r.err != nil {
+	if r.readFull(r.b[:4]) != nil {
 		return 0
 	}
 	bits := binary.LittleEndian.Uint32(r.b[:4])
@@ -180,7 +205,7 @@ func (r *Reader) ReadFloat64() float64 {
 	if r.err != nil {
 		return 0
 	}
-	if _, r.err = io.ReadFull(r.rd, r.b[:8]); r.err != nil {
+	if r.readFull(r.b[:8]) != nil {
 		return 0
 	}
 	bits := binary.LittleEndian.Uint64(r.b[:8])
@@ -192,8 +217,16 @@ func (r *Reader) ReadCesu8(size int) []byte {
 	if r.err != nil {
 		return nil
 	}
-	p := make([]byte, size)
-	if _, r.err = io.ReadFull(r.rd, p); r.err != nil {
+	return r.ReadCesu8Full(make([]byte, size))
+}
+
+// ReadCesu8Full reads len(p) CESU-8 encoded bytes into p and returns the UTF-8 converted content,
+// which is stored in p (inplace transformation).
+func (r *Reader) ReadCesu8Full(p []byte) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if r.readFull(p) != nil {
 		return nil
 	}
 	r.tr.Reset()
@@ -0,0 +1,72 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+// Capabilities are the features negotiated with the database server on connect.
+// Features not reported by the database server (e.g. older server versions) are disabled,
+// so that the driver falls back to the legacy protocol behavior.
+type Capabilities struct {
+	ArrayExecution         bool // Execution of statements with multiple parameter rows (bulk insert) in one request (enabled unless disabled by the server).
+	LargeBulkOperations    bool // Bulk operations exceeding the row number limit of a single request.
+	LargeNumberOfParameter bool // Statements with more than 32767 parameters.
+	SplitBatchCommands     bool // Splitting of batch commands by the database server.
+	DataFormatVersion      int  // Effective data format version of date and time values.
+}
+
+// boolean returns the boolean value of the connect option k or false, if the option is not set.
+func (o *connectOptions) boolean(k connectOption) bool {
+	if v, ok := o.get(k); ok {
+		if b, ok := v.(booleanType); ok {
+			return bool(b)
+		}
+	}
+	return false
+}
+
+// Capabilities returns the features negotiated with the database server on connect.
+//
+// The effective data format version might be lower than the requested version, in which case the database server
+// describes date and time fields by the type codes of the lower version (e.g. TIMESTAMP instead of LONGDATE).
+// As parameters and result fields are encoded according to the type codes provided by the server, no further
+// action is needed.
+func (s *Session) Capabilities() Capabilities {
+	c := Capabilities{
+		ArrayExecution:         s.maxParameterRows() == 0,
+		LargeBulkOperations:    s.connectOptions.boolean(coSupportsLargeBulkOperations),
+		LargeNumberOfParameter: s.connectOptions.boolean(coLargeNumberOfParameterSupport),
+		SplitBatchCommands:     s.connectOptions.boolean(coSplitBatchCommands),
+		DataFormatVersion:      int(dfvBaseline),
+	}
+	if v, ok := s.connectOptions.get(coDataFormatVersion2); ok {
+		if dfv, ok := v.(intType); ok {
+			c.DataFormatVersion = int(dfv)
+		}
+	}
+	return c
+}
+
+// maxParameterRows returns the maximum number of parameter rows of an execute request (0: no limit).
+// The complete array execution option is deprecated and not reported by current database servers, so that
+// rows are only executed one by one, if the database server disables array execution explicitly.
+func (s *Session) maxParameterRows() int {
+	if v, ok := s.connectOptions.get(coCompleteArrayExecution); ok {
+		if b, ok := v.(booleanType); ok && !bool(b) {
+			return 1
+		}
+	}
+	return 0
+}
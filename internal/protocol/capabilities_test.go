@@ -0,0 +1,53 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	// no options reported (complete array execution is deprecated): array execution
+	s := &Session{connectOptions: newConnectOptions()}
+	if c := s.Capabilities(); c != (Capabilities{ArrayExecution: true, DataFormatVersion: int(dfvBaseline)}) {
+		t.Fatalf("capabilities %v - expected legacy capabilities with array execution", c)
+	}
+	if s.maxParameterRows() != 0 {
+		t.Fatalf("maximum number of parameter rows %d - expected %d", s.maxParameterRows(), 0)
+	}
+
+	// array execution disabled explicitly: one row per request
+	s.connectOptions.set(coCompleteArrayExecution, booleanType(false))
+	if c := s.Capabilities(); c.ArrayExecution {
+		t.Fatalf("capabilities %v - expected no array execution", c)
+	}
+	if s.maxParameterRows() != 1 {
+		t.Fatalf("maximum number of parameter rows %d - expected %d", s.maxParameterRows(), 1)
+	}
+
+	s.connectOptions.set(coCompleteArrayExecution, booleanType(true))
+	s.connectOptions.set(coSplitBatchCommands, booleanType(true))
+	s.connectOptions.set(coDataFormatVersion2, dfvSPS06)
+
+	expected := Capabilities{ArrayExecution: true, SplitBatchCommands: true, DataFormatVersion: int(dfvSPS06)}
+	if c := s.Capabilities(); c != expected {
+		t.Fatalf("capabilities %v - expected %v", c, expected)
+	}
+	if s.maxParameterRows() != 0 {
+		t.Fatalf("maximum number of parameter rows %d - expected %d", s.maxParameterRows(), 0)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+	"github.com/SAP/go-hdb/internal/unicode/cesu8"
+)
+
+// clientInfo holds the client information (key value pairs) of a session. Modified values are sent
+// to the database server with the next statement execution request.
+type clientInfo struct {
+	defaults map[string]string // session values
+	stmt     map[string]string // values of the next statement only
+	server   map[string]string // values set on the database server
+}
+
+func newClientInfo(defaults map[string]string) *clientInfo {
+	return &clientInfo{defaults: defaults, server: make(map[string]string)}
+}
+
+// setStmt sets the client information values of the next statement.
+func (c *clientInfo) setStmt(values map[string]string) {
+	c.stmt = values
+}
+
+// changes returns the client information values to be sent to the database server and resets the
+// statement values. Values set by a former statement only are reset to the empty value.
+func (c *clientInfo) changes() clientInfoPart {
+	values := make(map[string]string, len(c.defaults)+len(c.stmt))
+	for k, v := range c.defaults {
+		values[k] = v
+	}
+	for k, v := range c.stmt {
+		values[k] = v
+	}
+	c.stmt = nil
+
+	part := clientInfoPart{}
+	for k, v := range values {
+		if c.server[k] != v {
+			part[k] = v
+		}
+	}
+	for k, v := range c.server {
+		if _, ok := values[k]; !ok && v != "" {
+			part[k] = ""
+		}
+	}
+	c.server = values
+	return part
+}
+
+// clientInfoPart is the client information request part.
+type clientInfoPart map[string]string
+
+func (p clientInfoPart) String() string {
+	return fmt.Sprintf("%v", map[string]string(p))
+}
+
+func (p clientInfoPart) kind() partKind {
+	return pkClientInfo
+}
+
+func (p clientInfoPart) size() (int, error) {
+	size := 0
+	for k, v := range p {
+		n, err := bytesSize(cesu8.StringSize(k))
+		if err != nil {
+			return 0, err
+		}
+		size += n
+		if n, err = bytesSize(cesu8.StringSize(v)); err != nil {
+			return 0, err
+		}
+		size += n
+	}
+	return size, nil
+}
+
+func (p clientInfoPart) numArg() int {
+	return len(p)
+}
+
+func (p clientInfoPart) write(wr *bufio.Writer) error {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		writeUtf8String(wr, k)
+		writeUtf8String(wr, p[k])
+	}
+
+	if trace {
+		outLogger.Printf("client info: %s", p)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+func TestClientInfoChanges(t *testing.T) {
+	c := newClientInfo(map[string]string{"APPLICATION": "app"})
+
+	var data = []struct {
+		stmt    map[string]string
+		changes clientInfoPart
+	}{
+		{nil, clientInfoPart{"APPLICATION": "app"}},
+		{nil, clientInfoPart{}},
+		{map[string]string{"TRACEID": "4711"}, clientInfoPart{"TRACEID": "4711"}},
+		{map[string]string{"TRACEID": "4712"}, clientInfoPart{"TRACEID": "4712"}},
+		{nil, clientInfoPart{"TRACEID": ""}}, // statement value is reset
+		{map[string]string{"APPLICATION": "other"}, clientInfoPart{"APPLICATION": "other"}},
+		{nil, clientInfoPart{"APPLICATION": "app"}}, // session value is restored
+	}
+
+	for i, d := range data {
+		c.setStmt(d.stmt)
+		if changes := c.changes(); !reflect.DeepEqual(changes, d.changes) {
+			t.Fatalf("%d: changes %v - expected %v", i, changes, d.changes)
+		}
+	}
+}
+
+func TestClientInfoPart(t *testing.T) {
+	part := clientInfoPart{"b": "2", "a": "€"}
+
+	size, err := part.size()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	if err := part.write(wr); err != nil {
+		t.Fatal(err)
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{1, 'a', 3, 0xe2, 0x82, 0xac, 1, 'b', 1, '2'} // sorted by key
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Fatalf("part %x - expected %x", buf.Bytes(), expected)
+	}
+	if size != len(expected) {
+		t.Fatalf("size %d - expected %d", size, len(expected))
+	}
+	if part.numArg() != 2 {
+		t.Fatalf("number of arguments %d - expected %d", part.numArg(), 2)
+	}
+}
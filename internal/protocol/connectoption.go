@@ -54,4 +54,6 @@ const (
 	coEndianess                    connectOption = 34
 	// 35, 36 reserved: do not use
 	coImplicitLobStreaming connectOption = 37
+	// 38-43 not used by the driver
+	coFullVersionString connectOption = 44
 )
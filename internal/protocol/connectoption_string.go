@@ -10,6 +10,7 @@ const (
 	_connectOption_name_2 = "coAbapVarcharModecoSelectForUpdateSupportedcoClientDistributionModecoEngineDataFormatVersioncoDistributionProtocolVersioncoSplitBatchCommandscoUseTransactionFlagsOnly"
 	_connectOption_name_3 = "coIgnoreUnknownPartscoTableOutputParametercoDataFormatVersion2coItabParametercoDescribeTableOutputParametercoColumnarResultsetcoScrollablResultSetcoClientInfoNullValueSupportedcoAssociatedConnectionIDcoNoTransactionalPreparecoFDAEnabledcoOSUsercoRowslotImageResultcoEndianess"
 	_connectOption_name_4 = "coImplicitLobStreaming"
+	_connectOption_name_5 = "coFullVersionString"
 )
 
 var (
@@ -35,6 +36,8 @@ func (i connectOption) String() string {
 		return _connectOption_name_3[_connectOption_index_3[i]:_connectOption_index_3[i+1]]
 	case i == 37:
 		return _connectOption_name_4
+	case i == 44:
+		return _connectOption_name_5
 	default:
 		return "connectOption(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
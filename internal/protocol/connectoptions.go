@@ -28,6 +28,8 @@ const (
 	dfvDoNotUse intType = 3
 	dfvSPS06    intType = 4 //see docu
 	dfvBINTEXT  intType = 6
+	dfvBoolean  intType = 7
+	dfvFixed    intType = 8
 )
 
 // client distribution mode
@@ -35,4 +35,6 @@ const (
 	DtString
 	DtBytes
 	DtLob
+	DtArray
+	DtBoolean
 )
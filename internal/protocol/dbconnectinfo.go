@@ -0,0 +1,103 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+// dbConnectInfo is the request and reply part of a database connect information request.
+// In a multitenant database system the reply provides the host of the requested tenant database,
+// so that connections to a different host (e.g. the system database) can be redirected.
+type dbConnectInfo struct {
+	po      plainOptions
+	_numArg int
+}
+
+func newDBConnectInfo(databaseName string) *dbConnectInfo {
+	return &dbConnectInfo{
+		po: plainOptions{int8(ciDatabaseName): stringType(databaseName)},
+	}
+}
+
+func (ci *dbConnectInfo) String() string {
+	m := make(map[dbConnectInfoType]interface{})
+	for k, v := range ci.po {
+		m[dbConnectInfoType(k)] = v
+	}
+	return fmt.Sprintf("%s", m)
+}
+
+func (ci *dbConnectInfo) kind() partKind {
+	return pkDBConnectInfo
+}
+
+func (ci *dbConnectInfo) size() (int, error) {
+	return ci.po.size(), nil
+}
+
+func (ci *dbConnectInfo) numArg() int {
+	return len(ci.po)
+}
+
+func (ci *dbConnectInfo) setNumArg(numArg int) {
+	ci._numArg = numArg
+}
+
+// isConnected returns true, if the connection is connected to the requested database.
+func (ci *dbConnectInfo) isConnected() bool {
+	v, ok := ci.po[int8(ciIsConnected)].(booleanType)
+	return ok && bool(v)
+}
+
+// redirectHost returns the address (host:port) of the requested database.
+func (ci *dbConnectInfo) redirectHost() (string, error) {
+	host, ok := ci.po[int8(ciHost)].(stringType)
+	if !ok || len(host) == 0 {
+		return "", fmt.Errorf("database connect info: missing host")
+	}
+	port, ok := ci.po[int8(ciPort)].(intType)
+	if !ok {
+		return "", fmt.Errorf("database connect info: missing port")
+	}
+	return net.JoinHostPort(string(host), strconv.Itoa(int(port))), nil
+}
+
+func (ci *dbConnectInfo) read(rd *bufio.Reader) error {
+	ci.po = plainOptions{} // replace request options
+	ci.po.read(rd, ci._numArg)
+
+	if trace {
+		outLogger.Printf("db connect info: %v", ci)
+	}
+
+	return rd.GetError()
+}
+
+func (ci *dbConnectInfo) write(wr *bufio.Writer) error {
+	ci.po.write(wr)
+
+	if trace {
+		outLogger.Printf("db connect info: %v", ci)
+	}
+
+	return nil
+}
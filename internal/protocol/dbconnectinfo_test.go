@@ -0,0 +1,69 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+func TestDBConnectInfo(t *testing.T) {
+	var data = []struct {
+		po           plainOptions
+		redirectHost string
+	}{
+		{plainOptions{int8(ciDatabaseName): stringType("DB1"), int8(ciIsConnected): booleanType(true)}, ""},
+		{plainOptions{int8(ciDatabaseName): stringType("DB1"), int8(ciHost): stringType("host1"), int8(ciPort): intType(30041), int8(ciIsConnected): booleanType(false)}, "host1:30041"},
+	}
+
+	for i, d := range data {
+		buf := new(bytes.Buffer)
+		wr := bufio.NewWriter(buf)
+		d.po.write(wr)
+		if err := wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		ci := newDBConnectInfo("DB1")
+		ci.setNumArg(len(d.po))
+		if err := ci.read(bufio.NewReader(buf)); err != nil {
+			t.Fatal(err)
+		}
+
+		if ci.isConnected() != (d.redirectHost == "") {
+			t.Fatalf("%d: connected %t - expected %t", i, ci.isConnected(), d.redirectHost == "")
+		}
+		if ci.isConnected() {
+			continue
+		}
+		redirectHost, err := ci.redirectHost()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if redirectHost != d.redirectHost {
+			t.Fatalf("%d: redirect host %s - expected %s", i, redirectHost, d.redirectHost)
+		}
+	}
+
+	// missing host
+	ci := newDBConnectInfo("DB1")
+	if _, err := ci.redirectHost(); err == nil {
+		t.Fatal("missing host error expected")
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package protocol
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/SAP/go-hdb/internal/bufio"
@@ -30,6 +31,14 @@ const (
 	fixLength = 2
 )
 
+// ErrServerOOM is matched by database server errors reporting that the server could not allocate enough memory
+// for a statement (see hdbErrors.Is).
+var ErrServerOOM = errors.New("server out of memory")
+
+// errCodeOOM is the error code of 'cannot allocate enough memory' errors, covering the global, statement
+// and composite memory limit failures (the failure type is part of the error text).
+const errCodeOOM = 4
+
 type sqlState [sqlStateSize]byte
 
 type hdbError struct {
@@ -63,6 +72,51 @@ func (e *hdbError) Error() string {
 	return fmt.Sprintf("SQL %s %d - %s", e.errorLevel, e.errorCode, e.errorText)
 }
 
+// ErrorDetail is a single database server error of a reply (see MultiError).
+type ErrorDetail struct {
+	Code     int    // Database error code.
+	Position int    // Start position of the erroneous sql statement.
+	Level    int    // Error level (0: warning, 1: error, 2: fatal error).
+	Text     string // Error description.
+	StmtNo   int    // Statement number of the error in multi statement contexts (-1: not available).
+}
+
+// Error implements the golang error interface.
+func (d ErrorDetail) Error() string {
+	e := &hdbError{errorCode: int32(d.Code), errorLevel: errorLevel(d.Level), stmtNo: d.StmtNo, errorText: []byte(d.Text)}
+	return e.Error()
+}
+
+// MultiError carries all errors returned by the database server in one reply (e.g. the errors of a bulk statement).
+type MultiError struct {
+	Errors []ErrorDetail
+}
+
+// Error implements the golang error interface.
+func (e *MultiError) Error() string {
+	switch len(e.Errors) {
+	case 0:
+		return "no database errors"
+	case 1:
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e.Errors[0].Error(), len(e.Errors)-1)
+}
+
+// CommitError is the error of an asynchronous commit. As the commit reply is read later, the error is returned
+// by the operation reading the reply (the next request of the session, ReadPendingCommitReply).
+type CommitError struct {
+	err error
+}
+
+// Error implements the golang error interface.
+func (e *CommitError) Error() string {
+	return fmt.Sprintf("asynchronous commit failed: %s", e.err)
+}
+
+// Unwrap returns the error of the commit reply.
+func (e *CommitError) Unwrap() error { return e.err }
+
 type hdbErrors struct {
 	errors []*hdbError
 	numArg int
@@ -136,12 +190,64 @@ func (e *hdbErrors) IsFatal() bool {
 	return e.errors[e.idx].errorLevel == errorLevelFatalError
 }
 
+// Is reports whether any of the errors matches target (see ErrServerOOM).
+func (e *hdbErrors) Is(target error) bool {
+	if target != ErrServerOOM {
+		return false
+	}
+	for _, _error := range e.errors[:e.numArg] {
+		if _error.errorCode == errCodeOOM {
+			return true
+		}
+	}
+	return false
+}
+
+// As sets target to a MultiError carrying all errors, if target is of type **MultiError.
+func (e *hdbErrors) As(target interface{}) bool {
+	t, ok := target.(**MultiError)
+	if !ok {
+		return false
+	}
+	m := &MultiError{Errors: make([]ErrorDetail, e.numArg)}
+	for i, _error := range e.errors[:e.numArg] {
+		m.Errors[i] = ErrorDetail{
+			Code:     int(_error.errorCode),
+			Position: int(_error.errorPosition),
+			Level:    int(_error.errorLevel),
+			Text:     string(_error.errorText),
+			StmtNo:   _error.stmtNo,
+		}
+	}
+	*t = m
+	return true
+}
+
 func (e *hdbErrors) setStmtNo(idx, no int) {
 	if idx >= 0 && idx < e.numArg {
 		e.errors[idx].stmtNo = no
 	}
 }
 
+// addStmtNoOffset adds ofs to the statement numbers of the errors (split mass insert).
+func (e *hdbErrors) addStmtNoOffset(ofs int) {
+	for _, _error := range e.errors {
+		if _error.stmtNo != -1 {
+			_error.stmtNo += ofs
+		}
+	}
+}
+
+// clone returns a copy of e, which is not overwritten by the errors of subsequent replies.
+func (e *hdbErrors) clone() *hdbErrors {
+	c := &hdbErrors{errors: make([]*hdbError, e.numArg), numArg: e.numArg, idx: e.idx}
+	for i, _error := range e.errors[:e.numArg] {
+		_errorCopy := *_error
+		c.errors[i] = &_errorCopy
+	}
+	return c
+}
+
 func (e *hdbErrors) isWarnings() bool {
 	for _, _error := range e.errors {
 		if _error.errorLevel != errorLevelWarning {
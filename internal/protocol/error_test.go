@@ -0,0 +1,77 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrServerOOM(t *testing.T) {
+	testData := []struct {
+		codes []int32
+		oom   bool
+	}{
+		{[]int32{errCodeOOM}, true},
+		{[]int32{259}, false},
+		{[]int32{259, errCodeOOM}, true},
+	}
+
+	for _, d := range testData {
+		e := &hdbErrors{numArg: len(d.codes)}
+		for _, code := range d.codes {
+			e.errors = append(e.errors, &hdbError{errorCode: code, stmtNo: -1})
+		}
+
+		if e.Is(ErrServerOOM) != d.oom {
+			t.Fatalf("codes %v: is server oom %t - expected %t", d.codes, !d.oom, d.oom)
+		}
+		if e.Is(errors.New(ErrServerOOM.Error())) {
+			t.Fatalf("codes %v: other error matched", d.codes)
+		}
+	}
+}
+
+func TestMultiError(t *testing.T) {
+	e := &hdbErrors{numArg: 2, errors: []*hdbError{
+		{errorCode: 301, errorLevel: errorLevelError, stmtNo: 1, errorText: []byte("unique constraint violated")},
+		{errorCode: 301, errorLevel: errorLevelError, stmtNo: 3, errorText: []byte("unique constraint violated")},
+		{errorCode: 259, errorLevel: errorLevelError, stmtNo: -1, errorText: []byte("error of previous reply")}, // not part of numArg
+	}}
+
+	var m *MultiError
+	if !e.As(&m) {
+		t.Fatal("multi error expected")
+	}
+	if len(m.Errors) != 2 {
+		t.Fatalf("number of errors %d - expected %d", len(m.Errors), 2)
+	}
+	for i, stmtNo := range []int{1, 3} {
+		d := m.Errors[i]
+		if d.Code != 301 || d.Level != int(errorLevelError) || d.StmtNo != stmtNo || d.Text != "unique constraint violated" {
+			t.Fatalf("error %d: %+v - expected code 301 statement no %d", i, d, stmtNo)
+		}
+		if d.Error() != e.errors[i].Error() {
+			t.Fatalf("error %d: text %s - expected %s", i, d.Error(), e.errors[i].Error())
+		}
+	}
+
+	const expected = "SQL Error 301 - unique constraint violated (statement no: 1) (and 1 more errors)"
+	if m.Error() != expected {
+		t.Fatalf("error %s - expected %s", m.Error(), expected)
+	}
+}
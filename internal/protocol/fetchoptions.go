@@ -0,0 +1,64 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+// fetch option keys
+const (
+	foResultsetPos int8 = 1 // cursor position (absolute or relative scroll offset)
+)
+
+// fetchOptions is the request part of scrollable cursor fetch requests (fetch absolute, fetch relative)
+// providing the cursor position.
+type fetchOptions struct {
+	po plainOptions
+}
+
+func newFetchOptions(pos int) *fetchOptions {
+	return &fetchOptions{po: plainOptions{foResultsetPos: intType(pos)}}
+}
+
+func (o *fetchOptions) String() string {
+	return fmt.Sprintf("resultset position %v", o.po[foResultsetPos])
+}
+
+func (o *fetchOptions) kind() partKind {
+	return pkFetchOptions
+}
+
+func (o *fetchOptions) size() (int, error) {
+	return o.po.size(), nil
+}
+
+func (o *fetchOptions) numArg() int {
+	return len(o.po)
+}
+
+func (o *fetchOptions) write(wr *bufio.Writer) error {
+	o.po.write(wr)
+
+	if trace {
+		outLogger.Printf("fetch options: %v", o)
+	}
+
+	return nil
+}
@@ -17,12 +17,22 @@ limitations under the License.
 package protocol
 
 import (
+	"math"
+
 	"github.com/SAP/go-hdb/internal/bufio"
 )
 
 //fetch size
 type fetchsize int32
 
+// newFetchsize returns the fetch size part of size, clamped to the maximum value supported by the protocol.
+func newFetchsize(size int) fetchsize {
+	if size > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return fetchsize(size)
+}
+
 func (s fetchsize) kind() partKind {
 	return pkFetchSize
 }
@@ -17,13 +17,19 @@ limitations under the License.
 package protocol
 
 import (
+	"bytes"
 	"database/sql/driver"
 	"fmt"
 	"math"
+	"math/big"
 	"sort"
+	"sync"
 	"time"
 
+	"golang.org/x/text/encoding"
+
 	"github.com/SAP/go-hdb/internal/bufio"
+	"github.com/SAP/go-hdb/internal/unicode"
 	"github.com/SAP/go-hdb/internal/unicode/cesu8"
 )
 
@@ -34,6 +40,13 @@ const (
 	doubleNullValue uint64 = ^uint64(0)
 )
 
+// boolean values (NULL: UNKNOWN state of three-valued logic)
+const (
+	booleanFalseValue byte = 0
+	booleanNullValue  byte = 1
+	booleanTrueValue  byte = 2
+)
+
 const noFieldName uint32 = 0xFFFFFFFF
 
 type uint32Slice []uint32
@@ -80,19 +93,105 @@ type FieldValues struct {
 	rows   int
 	cols   int
 	values []driver.Value
+	buf    []byte     // buffer for variable length field values (reused by subsequent reads)
+	dec    RowDecoder // optional row decoder replacing the default field decoding
+	pooled bool       // obtained from field values pool (see Release)
+	// fields of a row split across fetch replies, which are read before the row is completed by the next fetch (nil: no split row)
+	splitRow []driver.Value
 }
 
 func newFieldValues() *FieldValues {
 	return &FieldValues{}
 }
 
+// fieldValuesPool pools the field values of query results, so that the values slice and the buffer
+// of variable length field values are reused by subsequent queries.
+var fieldValuesPool = sync.Pool{New: func() interface{} { return newFieldValues() }}
+
+// limits of the values slice and buffer sizes kept by pooled field values
+const (
+	maxPooledFieldValues       = 1 << 16
+	maxPooledFieldValuesBuffer = 1 << 20
+)
+
+// getFieldValues returns field values from the field values pool.
+func getFieldValues() *FieldValues {
+	f := fieldValuesPool.Get().(*FieldValues)
+	f.pooled = true
+	return f
+}
+
+// Release returns field values obtained by a query to the pool of field values.
+// Release must only be called after the last use of the field values, as the field values and
+// the values returned by Row (e.g. byte slices referring to the field values buffer) are reused by
+// subsequent queries. Release is a no-op for nil field values or field values not obtained from the pool.
+func (f *FieldValues) Release() {
+	if f == nil || !f.pooled {
+		return
+	}
+	for i := range f.values { // do not keep references (e.g. lobs)
+		f.values[i] = nil
+	}
+	f.rows, f.cols, f.dec, f.pooled, f.splitRow = 0, 0, nil, false, nil
+	f.values, f.buf = f.values[:0], f.buf[:0]
+	if cap(f.values) > maxPooledFieldValues {
+		f.values = nil
+	}
+	if cap(f.buf) > maxPooledFieldValuesBuffer {
+		f.buf = nil
+	}
+	fieldValuesPool.Put(f)
+}
+
 func (f *FieldValues) String() string {
 	return fmt.Sprintf("rows %d columns %d", f.rows, f.cols)
 }
 
+// resize resizes the field values for reading a new set of rows.
+// Values read before are invalidated, as the underlying slice and buffer are reused.
 func (f *FieldValues) resize(rows, cols int) {
 	f.rows, f.cols = rows, cols
-	f.values = make([]driver.Value, rows*cols)
+	size := rows * cols
+	if cap(f.values) < size {
+		f.values = make([]driver.Value, size)
+	} else {
+		f.values = f.values[:size]
+	}
+	f.buf = f.buf[:0]
+}
+
+// grow appends rows to the field values.
+func (f *FieldValues) grow(rows, cols int) {
+	f.rows += rows
+	f.cols = cols
+	f.values = append(f.values, make([]driver.Value, rows*cols)...)
+}
+
+const minFieldValuesBufferSize = 4096
+
+// alloc returns a byte slice of size from the field values buffer.
+// If the buffer capacity is exceeded a new buffer is allocated, so that
+// slices returned before stay valid.
+func (f *FieldValues) alloc(size int) []byte {
+	l := len(f.buf)
+	if l+size > cap(f.buf) {
+		c := 2 * cap(f.buf)
+		if c < minFieldValuesBufferSize {
+			c = minFieldValuesBufferSize
+		}
+		if c < size {
+			c = size
+		}
+		f.buf = make([]byte, 0, c)
+		l = 0
+	}
+	f.buf = f.buf[:l+size]
+	return f.buf[l : l+size : l+size]
+}
+
+// RowDecoder returns the row decoder of the field values or nil, if the default field decoding is used.
+func (f *FieldValues) RowDecoder() RowDecoder {
+	return f.dec
 }
 
 // NumRow returns the number of rows available in FieldValues.
@@ -106,6 +205,7 @@ func (f *FieldValues) Row(idx int, dest []driver.Value) {
 }
 
 const (
+	booleanFieldSize       = 1
 	tinyintFieldSize       = 1
 	smallintFieldSize      = 2
 	intFieldSize           = 4
@@ -120,17 +220,22 @@ const (
 	daydateFieldSize       = 4
 	secondtimeFieldSize    = 4
 	decimalFieldSize       = 16
+	fixed8FieldSize        = 8
+	fixed12FieldSize       = 12
+	fixed16FieldSize       = 16
 	lobInputDescriptorSize = 9
 )
 
 func fieldSize(tc TypeCode, arg driver.NamedValue) (int, error) {
 	v := arg.Value
 
-	if v == nil { //HDB bug: secondtime null value --> see writeField
+	if v == nil && tc != tcSecondtime { //HDB bug: secondtime null value --> see writeField
 		return 0, nil
 	}
 
 	switch tc {
+	case tcBoolean:
+		return booleanFieldSize, nil
 	case tcTinyint:
 		return tinyintFieldSize, nil
 	case tcSmallint:
@@ -159,6 +264,12 @@ func fieldSize(tc TypeCode, arg driver.NamedValue) (int, error) {
 		return secondtimeFieldSize, nil
 	case tcDecimal:
 		return decimalFieldSize, nil
+	case tcFixed8:
+		return fixed8FieldSize, nil
+	case tcFixed12:
+		return fixed12FieldSize, nil
+	case tcFixed16:
+		return fixed16FieldSize, nil
 	case tcChar, tcVarchar, tcString:
 		switch v := v.(type) {
 		case []byte:
@@ -177,23 +288,33 @@ func fieldSize(tc TypeCode, arg driver.NamedValue) (int, error) {
 		default:
 			outLogger.Fatalf("data type %s mismatch %T", tc, v)
 		}
-	case tcBinary, tcVarbinary:
+	case tcBinary, tcVarbinary, tcBstring, tcVarbinary3:
 		v, ok := v.([]byte)
 		if !ok {
 			outLogger.Fatalf("data type %s mismatch %T", tc, v)
 		}
 		return bytesSize(len(v))
-	case tcBlob, tcClob, tcNclob:
+	case tcBlob, tcClob, tcNclob, tcText, tcBintext:
 		return lobInputDescriptorSize, nil
 	}
 	outLogger.Fatalf("data type %s not implemented", tc)
 	return 0, nil
 }
 
-func readField(session *Session, rd *bufio.Reader, tc TypeCode) (interface{}, error) {
+// readField reads a field value. Variable length values are stored in the field values buffer.
+func (f *FieldValues) readField(session *Session, rd *bufio.Reader, tc TypeCode) (interface{}, error) {
 
 	switch tc {
 
+	case tcBoolean:
+		switch rd.ReadB() {
+		case booleanNullValue: // unknown
+			return nil, nil
+		case booleanFalseValue:
+			return false, nil
+		}
+		return true, nil
+
 	case tcTinyint, tcSmallint, tcInteger, tcBigint:
 
 		if !rd.ReadBool() { //null value
@@ -277,45 +398,180 @@ func readField(session *Session, rd *bufio.Reader, tc TypeCode) (interface{}, er
 		return time, nil
 
 	case tcDecimal:
-		b, null := readDecimal(rd)
+		b, null := readDecimal(rd, f.alloc(decimalFieldSize))
 		if null {
 			return nil, nil
 		}
 		return b, nil
 
+	case tcFixed8:
+		return readFixed(rd, fixed8FieldSize), nil
+	case tcFixed12:
+		return readFixed(rd, fixed12FieldSize), nil
+	case tcFixed16:
+		return readFixed(rd, fixed16FieldSize), nil
+
 	case tcChar, tcVarchar:
-		value, null := readBytes(rd)
+		value, null := readCharBytes(rd, f.alloc)
 		if null {
 			return nil, nil
 		}
 		return value, nil
 
 	case tcNchar, tcNvarchar:
-		value, null := readUtf8(rd)
+		value, null := readUtf8(rd, f.alloc)
 		if null {
 			return nil, nil
 		}
 		return value, nil
 
-	case tcBinary, tcVarbinary:
-		value, null := readBytes(rd)
+	case tcBinary, tcVarbinary, tcRowid, tcBstring, tcVarbinary3:
+		value, null := readBytes(rd, f.alloc)
 		if null {
 			return nil, nil
 		}
 		return value, nil
 
-	case tcBlob, tcClob, tcNclob:
+	case tcBlob, tcClob, tcNclob, tcText, tcBintext:
 		null, writer, err := readLob(session, rd, tc)
 		if null {
 			return nil, nil
 		}
 		return writer, err
+
+	case tcArray:
+		return f.readArray(session, rd)
 	}
 
 	outLogger.Fatalf("read field: type code %s not implemented", tc)
 	return nil, nil
 }
 
+// A FieldError is the error of reading the value of a resultset or output parameter field.
+type FieldError struct {
+	idx  int
+	name string
+	err  error
+}
+
+// newFieldError returns a FieldError wrapping err. Connection errors are not field specific and returned unchanged.
+func newFieldError(idx int, name string, err error) error {
+	if err == driver.ErrBadConn {
+		return err
+	}
+	return &FieldError{idx: idx, name: name, err: err}
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %d (%s): %s", e.idx, e.name, e.err)
+}
+
+// FieldIndex returns the index of the field.
+func (e *FieldError) FieldIndex() int { return e.idx }
+
+// FieldName returns the name of the field.
+func (e *FieldError) FieldName() string { return e.name }
+
+// Unwrap returns the error of reading the field value.
+func (e *FieldError) Unwrap() error { return e.err }
+
+// zeroFieldValue returns the zero value of a field with type code tc.
+// Lob fields do not have a zero value (nil).
+func zeroFieldValue(tc TypeCode) interface{} {
+	switch tc.DataType() {
+	case DtBoolean:
+		return false
+	case DtTinyint, DtSmallint, DtInteger, DtBigint:
+		return int64(0)
+	case DtReal, DtDouble:
+		return float64(0)
+	case DtTime:
+		return time.Time{}
+	case DtDecimal:
+		return make([]byte, decimalFieldSize)
+	case DtString, DtBytes:
+		return []byte{}
+	case DtArray:
+		return []interface{}{}
+	}
+	return nil
+}
+
+// trimCharFieldValue trims the trailing space padding of fixed length character (CHAR, NCHAR) field values.
+func trimCharFieldValue(tc TypeCode, v interface{}) interface{} {
+	if tc != tcChar && tc != tcNchar {
+		return v
+	}
+	if b, ok := v.([]byte); ok {
+		return bytes.TrimRight(b, " ")
+	}
+	return v
+}
+
+// decodeCharFieldValue converts non-unicode character (CHAR, VARCHAR) field values from the character encoding enc to UTF-8.
+func decodeCharFieldValue(enc encoding.Encoding, tc TypeCode, v interface{}) (interface{}, error) {
+	if !tc.isNonUnicodeChar() {
+		return v, nil
+	}
+	if b, ok := v.([]byte); ok {
+		return enc.NewDecoder().Bytes(b)
+	}
+	return v, nil
+}
+
+// encodeCharValue converts a string value of a non-unicode character (CHAR, VARCHAR) parameter from UTF-8 to the character encoding enc.
+func encodeCharValue(enc encoding.Encoding, tc TypeCode, v driver.Value) (driver.Value, error) {
+	if !tc.isNonUnicodeChar() {
+		return v, nil
+	}
+	switch v := v.(type) {
+	case string:
+		return enc.NewEncoder().Bytes([]byte(v))
+	case []byte:
+		return enc.NewEncoder().Bytes(v)
+	}
+	return v, nil
+}
+
+// An ArrayDecimal is the binary representation of a decimal array element.
+// As the element type is not part of the field metadata, decimal elements are typed to be decoded by the driver.
+type ArrayDecimal []byte
+
+// readArray reads an array field value consisting of
+// - the number of array elements (int32 - negative: null value),
+// - the element type code and
+// - the elements encoded like fields of the element type (including element null values).
+// Variable length element values are copied, as the field values buffer is reused by subsequent fetches.
+func (f *FieldValues) readArray(session *Session, rd *bufio.Reader) (interface{}, error) {
+	n := int(rd.ReadInt32())
+	if n < 0 { // null value
+		return nil, rd.GetError()
+	}
+
+	tc := TypeCode(rd.ReadB())
+	if tc.DataType() == DtUnknown || tc.IsFixed() { // fixed elements: scale is not part of the field metadata
+		return nil, fmt.Errorf("array element type code %s not supported", tc)
+	}
+
+	values := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		var err error
+		if values[i], err = f.readField(session, rd, tc); err != nil {
+			return nil, err
+		}
+		if b, ok := values[i].([]byte); ok {
+			c := make([]byte, len(b))
+			copy(c, b)
+			if tc == tcDecimal {
+				values[i] = ArrayDecimal(c)
+			} else {
+				values[i] = c
+			}
+		}
+	}
+	return values, rd.GetError()
+}
+
 func writeField(wr *bufio.Writer, tc TypeCode, arg driver.NamedValue) error {
 	v := arg.Value
 	//HDB bug: secondtime null value cannot be set by setting high byte
@@ -323,8 +579,7 @@ func writeField(wr *bufio.Writer, tc TypeCode, arg driver.NamedValue) error {
 	//         SQL HdbError 1033 - error while parsing protocol: no such data type: type_code=192, index=2
 
 	// null value
-	//if v == nil && tc != tcSecondtime
-	if v == nil {
+	if v == nil && tc != tcSecondtime {
 		wr.WriteB(byte(tc) | 0x80) //set high bit
 		return nil
 	}
@@ -337,6 +592,17 @@ func writeField(wr *bufio.Writer, tc TypeCode, arg driver.NamedValue) error {
 	default:
 		outLogger.Fatalf("write field: type code %s not implemented", tc)
 
+	case tcBoolean:
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("invalid argument type %T", v)
+		}
+		if b {
+			wr.WriteB(booleanTrueValue)
+		} else {
+			wr.WriteB(booleanFalseValue)
+		}
+
 	case tcTinyint, tcSmallint, tcInteger, tcBigint:
 		var i64 int64
 
@@ -446,6 +712,13 @@ func writeField(wr *bufio.Writer, tc TypeCode, arg driver.NamedValue) error {
 		}
 		wr.Write(b)
 
+	case tcFixed8:
+		return writeFixed(wr, v, fixed8FieldSize)
+	case tcFixed12:
+		return writeFixed(wr, v, fixed12FieldSize)
+	case tcFixed16:
+		return writeFixed(wr, v, fixed16FieldSize)
+
 	case tcChar, tcVarchar, tcString:
 		switch v := v.(type) {
 		case []byte:
@@ -466,14 +739,14 @@ func writeField(wr *bufio.Writer, tc TypeCode, arg driver.NamedValue) error {
 			return fmt.Errorf("invalid argument type %T", v)
 		}
 
-	case tcBinary, tcVarbinary:
+	case tcBinary, tcVarbinary, tcBstring, tcVarbinary3:
 		v, ok := v.([]byte)
 		if !ok {
 			return fmt.Errorf("invalid argument type %T", v)
 		}
 		writeBytes(wr, v)
 
-	case tcBlob, tcClob, tcNclob:
+	case tcBlob, tcClob, tcNclob, tcText, tcBintext:
 		writeLob(wr)
 	}
 
@@ -550,6 +823,7 @@ func readSeconddate(rd *bufio.Reader) (time.Time, bool) {
 	return convertSeconddateToTime(seconddate), false
 }
 
+// writeSeconddate writes t truncated to seconds.
 func writeSeconddate(wr *bufio.Writer, t time.Time) {
 	wr.WriteInt64(convertTimeToSeconddate(t))
 }
@@ -624,8 +898,7 @@ func convertSecondtimeToTime(secondtime int) time.Time {
 	return time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(int64(secondtime-1) * 1000000000))
 }
 
-func readDecimal(rd *bufio.Reader) ([]byte, bool) {
-	b := make([]byte, 16)
+func readDecimal(rd *bufio.Reader, b []byte) ([]byte, bool) {
 	rd.ReadFull(b)
 	if (b[15] & 0x70) == 0x70 { //null value (bit 4,5,6 set)
 		return nil, true
@@ -633,6 +906,70 @@ func readDecimal(rd *bufio.Reader) ([]byte, bool) {
 	return b, false
 }
 
+// A Fixed is the unscaled integer value of a fixed size decimal field (FIXED8, FIXED12, FIXED16).
+// The scale is part of the field metadata: value = Fixed * 10^-scale.
+type Fixed big.Int
+
+// readFixed reads a fixed size decimal value of size bytes (little endian two's complement)
+// preceded by a null indicator byte (0: null value).
+func readFixed(rd *bufio.Reader, size int) interface{} {
+	if rd.ReadB() == 0 { // null value
+		return nil
+	}
+	var buf [fixed16FieldSize]byte
+	b := buf[:size]
+	rd.ReadFull(b)
+	for i, j := 0, size-1; i < j; i, j = i+1, j-1 { // big endian
+		b[i], b[j] = b[j], b[i]
+	}
+	neg := b[0]&0x80 != 0
+	if neg {
+		for i := range b {
+			b[i] = ^b[i]
+		}
+	}
+	m := new(big.Int).SetBytes(b)
+	if neg { // -(^x + 1)
+		m.Neg(m.Add(m, natOne))
+	}
+	return (*Fixed)(m)
+}
+
+var natOne = big.NewInt(1)
+
+// writeFixed writes the *Fixed value v as fixed size decimal value of size bytes (little endian two's complement).
+func writeFixed(wr *bufio.Writer, v driver.Value, size int) error {
+	x, ok := v.(*Fixed)
+	if !ok {
+		return fmt.Errorf("invalid argument type %T", v)
+	}
+	m := (*big.Int)(x)
+	neg := m.Sign() < 0
+	u := new(big.Int).Set(m)
+	if neg { // ^(-x - 1)
+		u.Neg(u).Sub(u, natOne)
+	}
+	if u.BitLen() >= size*8 { // sign bit
+		return fmt.Errorf("fixed value %s exceeds field size %d", m, size)
+	}
+	b := u.Bytes() // big endian
+	for i := len(b) - 1; i >= 0; i-- {
+		if neg {
+			wr.WriteB(^b[i])
+		} else {
+			wr.WriteB(b[i])
+		}
+	}
+	pad := byte(0)
+	if neg {
+		pad = 0xff
+	}
+	for i := len(b); i < size; i++ {
+		wr.WriteB(pad)
+	}
+	return nil
+}
+
 // string / binary length indicators
 const (
 	bytesLenIndNullValue byte = 255
@@ -696,22 +1033,34 @@ func writeBytesSize(wr *bufio.Writer, size int) error {
 	return nil
 }
 
-func readBytes(rd *bufio.Reader) ([]byte, bool) {
+// readBytes reads bytes into a slice provided by alloc.
+func readBytes(rd *bufio.Reader, alloc func(size int) []byte) ([]byte, bool) {
 	size, null := readBytesSize(rd)
 	if null {
 		return nil, true
 	}
-	b := make([]byte, size)
+	b := alloc(size)
 	rd.ReadFull(b)
 	return b, false
 }
 
-func readUtf8(rd *bufio.Reader) ([]byte, bool) {
+// readCharBytes reads bytes of a non unicode character field into a slice provided by alloc.
+// As hdb might return supplementary characters as CESU-8 surrogate pairs, valid CESU-8 is converted to UTF-8.
+func readCharBytes(rd *bufio.Reader, alloc func(size int) []byte) ([]byte, bool) {
+	b, null := readBytes(rd, alloc)
+	if null {
+		return nil, true
+	}
+	return unicode.Cesu8ToUtf8(b), false
+}
+
+// readUtf8 reads and converts cesu8 encoded bytes into a slice provided by alloc.
+func readUtf8(rd *bufio.Reader, alloc func(size int) []byte) ([]byte, bool) {
 	size, null := readBytesSize(rd)
 	if null {
 		return nil, true
 	}
-	b := rd.ReadCesu8(size)
+	b := rd.ReadCesu8Full(alloc(size))
 	return b, false
 }
 
@@ -751,7 +1100,6 @@ func readLob(s *Session, rd *bufio.Reader, tc TypeCode) (bool, lobChunkWriter, e
 	if null {
 		return true, nil, nil
 	}
-	eof := (lobOptions(opt) & loLastdata) != 0
 	rd.Skip(2)
 
 	charLen := rd.ReadInt64()
@@ -759,6 +1107,9 @@ func readLob(s *Session, rd *bufio.Reader, tc TypeCode) (bool, lobChunkWriter, e
 	id := rd.ReadUint64()
 	chunkLen := rd.ReadInt32()
 
+	// empty lob (not null): no data to be read
+	eof := (lobOptions(opt)&loLastdata) != 0 || byteLen == 0
+
 	lobChunkWriter := newLobChunkWriter(tc.isCharBased(), s, locatorID(id), charLen, byteLen)
 	if err := lobChunkWriter.write(rd, int(chunkLen), eof); err != nil {
 		return null, lobChunkWriter, err
@@ -17,6 +17,7 @@ limitations under the License.
 package protocol
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"io"
 	"math"
@@ -101,29 +102,64 @@ func (r *writeLobReply) read(rd *bufio.Reader) error {
 	return rd.GetError()
 }
 
+// writeLobDescr links an input lob parameter value to its database lob locator.
+type writeLobDescr struct {
+	id  locatorID
+	row int // row of the parameter value (bulk insert)
+	cr  lobChunkReader
+}
+
+// newWriteLobDescrs returns the write lob descriptors of all not null input lob parameter values in row order.
+func newWriteLobDescrs(inputFields []*ParameterField, args []driver.NamedValue) []*writeLobDescr {
+	cnt := len(inputFields)
+	if cnt == 0 {
+		return nil
+	}
+
+	var descrs []*writeLobDescr
+	for i, arg := range args {
+		if !inputFields[i%cnt].TypeCode().isLob() {
+			continue
+		}
+		if cr, ok := arg.Value.(lobChunkReader); ok {
+			descrs = append(descrs, &writeLobDescr{row: i / cnt, cr: cr})
+		}
+	}
+	return descrs
+}
+
 //write lob request
 type writeLobRequest struct {
-	lobPrmFields []*ParameterField
+	descrs []*writeLobDescr
 }
 
 func (r *writeLobRequest) kind() partKind {
 	return pkWriteLobRequest
 }
 
+// fill reads the next chunk of all lobs not written completely.
+func (r *writeLobRequest) fill() error {
+	for _, descr := range r.descrs {
+		if descr.cr.done() {
+			continue
+		}
+		if err := descr.cr.fill(); err != nil {
+			return fmt.Errorf("lob read error (row %d): %s", descr.row, err)
+		}
+	}
+	return nil
+}
+
 func (r *writeLobRequest) size() (int, error) {
 
 	// TODO: check size limit
 
 	size := 0
-	for _, prmField := range r.lobPrmFields {
-		cr := prmField.chunkReader
+	for _, descr := range r.descrs {
+		cr := descr.cr
 		if cr.done() {
 			continue
 		}
-
-		if err := cr.fill(); err != nil {
-			return 0, err
-		}
 		size += writeLobRequestHeaderSize
 		size += cr.size()
 	}
@@ -132,9 +168,8 @@ func (r *writeLobRequest) size() (int, error) {
 
 func (r *writeLobRequest) numArg() int {
 	n := 0
-	for _, prmField := range r.lobPrmFields {
-		cr := prmField.chunkReader
-		if !cr.done() {
+	for _, descr := range r.descrs {
+		if !descr.cr.done() {
 			n++
 		}
 	}
@@ -142,11 +177,11 @@ func (r *writeLobRequest) numArg() int {
 }
 
 func (r *writeLobRequest) write(wr *bufio.Writer) error {
-	for _, prmField := range r.lobPrmFields {
-		cr := prmField.chunkReader
+	for _, descr := range r.descrs {
+		cr := descr.cr
 		if !cr.done() {
 
-			wr.WriteUint64(uint64(prmField.lobLocatorID))
+			wr.WriteUint64(uint64(descr.id))
 
 			opt := int8(0x02) // data included
 			if cr.eof() {
@@ -411,6 +446,72 @@ func (l *binaryLobChunkWriter) flush() error {
 	return nil
 }
 
+// ReadAt implements the io.ReaderAt interface: it reads len(p) bytes of the lob starting at byte offset off
+// by read lob requests of the requested range, so that the lob content does not need to be read completely.
+// Data of the first lob chunk already transferred with the field value is returned without database request.
+func (l *binaryLobChunkWriter) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("lob read: negative offset %d", off)
+	}
+	if off >= l.byteLen {
+		return 0, io.EOF
+	}
+
+	b := p
+	if rest := l.byteLen - off; int64(len(b)) > rest {
+		b = b[:rest]
+	}
+
+	if l.readOfs == 0 && off+int64(len(b)) <= int64(len(l.b)) { // range within first chunk
+		copy(b, l.b[off:])
+	} else {
+		w := &lobRangeWriter{_id: l._id, ofs: off, b: b}
+		if err := l.s.readLobStream(w); err != nil {
+			return w.n, err
+		}
+		b = b[:w.n]
+	}
+
+	if len(b) < len(p) {
+		return len(b), io.EOF
+	}
+	return len(b), nil
+}
+
+// lobRangeWriter reads the byte range of a binary lob starting at offset ofs into b.
+type lobRangeWriter struct {
+	_id  locatorID
+	ofs  int64
+	b    []byte
+	n    int
+	_eof bool
+}
+
+func (l *lobRangeWriter) id() locatorID { return l._id }
+func (l *lobRangeWriter) eof() bool     { return l._eof || l.n == len(l.b) }
+
+func (l *lobRangeWriter) SetWriter(wr io.Writer) error {
+	return fmt.Errorf("lob read: writer not supported for range reads")
+}
+
+func (l *lobRangeWriter) write(rd *bufio.Reader, size int, eof bool) error {
+	l._eof = eof || size == 0 // no further data
+	if size > len(l.b)-l.n {
+		return fmt.Errorf("lob read: chunk size %d exceeds requested size %d", size, len(l.b)-l.n)
+	}
+	rd.ReadFull(l.b[l.n : l.n+size])
+	l.n += size
+	return nil
+}
+
+func (l *lobRangeWriter) readOfsLen() (int64, int32) {
+	readLen := len(l.b) - l.n
+	if readLen > int(lobChunkSize) {
+		readLen = int(lobChunkSize)
+	}
+	return l.ofs + int64(l.n), int32(readLen)
+}
+
 type charLobChunkWriter struct {
 	s *Session
 
@@ -470,14 +571,26 @@ func (l *charLobChunkWriter) flush() error {
 	if _, err := l.wr.Write(l.b[:nDst]); err != nil {
 		return err
 	}
-	l.ofs = len(l.b) - nSrc
-	if l.ofs != 0 && l.ofs != cesu8.CESUMax/2 { // assert remaining bytes
-		return unicode.ErrInvalidCesu8
+	if l.ofs != 0 {
+		l.readOfs-- // high surrogate of previous chunk is counted with the decoded rune
 	}
 	l.readOfs += int64(l.runeCount(l.b[:nDst]))
-	if l.ofs != 0 {
-		l.readOfs++                   // add half encoding
-		copy(l.b, l.b[nSrc:len(l.b)]) // move half encoding to buffer begin
+
+	// chunk boundaries must not split multibyte encodings:
+	// - a high surrogate is kept and completed by the low surrogate of the next chunk
+	// - an incomplete character encoding is dropped, as the next chunk starts with that character (readOfs)
+	rest := l.b[nSrc:]
+	switch {
+	case len(rest) == 0:
+		l.ofs = 0
+	case l._eof:
+		return unicode.ErrInvalidCesu8
+	case cesu8.HighSurrogate(rest):
+		l.ofs = cesu8.CESUMax / 2
+		l.readOfs++             // hdb counts the high surrogate as char
+		copy(l.b, rest[:l.ofs]) // move half encoding to buffer begin
+	default:
+		l.ofs = 0
 	}
 	return nil
 }
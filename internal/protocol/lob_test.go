@@ -0,0 +1,392 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+	"github.com/SAP/go-hdb/internal/unicode"
+	"github.com/SAP/go-hdb/internal/unicode/cesu8"
+)
+
+type errorReader struct{}
+
+func (r errorReader) Read(p []byte) (int, error) { return 0, errors.New("read failed") }
+
+func TestWriteLobDescrs(t *testing.T) {
+	inputFields := []*ParameterField{
+		&ParameterField{tc: tcInteger, mode: pmIn},
+		&ParameterField{tc: tcBlob, mode: pmIn},
+		&ParameterField{tc: tcNclob, mode: pmIn},
+	}
+
+	// bulk insert: 3 rows, null lob in row 1
+	args := []driver.NamedValue{
+		{Value: int64(0)}, {Value: inputFields[1].LobValue(bytes.NewReader([]byte("b0")))}, {Value: inputFields[2].LobValue(strings.NewReader("c0"))},
+		{Value: int64(1)}, {Value: nil}, {Value: inputFields[2].LobValue(strings.NewReader("c1"))},
+		{Value: int64(2)}, {Value: inputFields[1].LobValue(errorReader{})}, {Value: inputFields[2].LobValue(strings.NewReader("c2"))},
+	}
+
+	descrs := newWriteLobDescrs(inputFields, args)
+
+	rows := []int{0, 0, 1, 2, 2}
+	if len(descrs) != len(rows) {
+		t.Fatalf("number of descriptors %d - expected %d", len(descrs), len(rows))
+	}
+	for i, descr := range descrs {
+		if descr.row != rows[i] {
+			t.Fatalf("descriptor %d: row %d - expected %d", i, descr.row, rows[i])
+		}
+	}
+
+	r := &writeLobRequest{descrs: descrs[:3]}
+	if err := r.fill(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.size(); err != nil {
+		t.Fatal(err)
+	}
+	if r.numArg() != 3 {
+		t.Fatalf("number of arguments %d - expected %d", r.numArg(), 3)
+	}
+
+	r.descrs = descrs[3:]
+	err := r.fill()
+	if err == nil {
+		t.Fatal("lob read error expected")
+	}
+	if !strings.Contains(err.Error(), "row 2") {
+		t.Fatalf("error %q does not report row %d", err, 2)
+	}
+}
+
+func TestReadNullAndEmptyLob(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	writeLobField := func(opt lobOptions) {
+		wr.WriteInt8(int8(tcBlob))
+		wr.WriteInt8(int8(opt))
+		wr.WriteZeroes(2)
+		wr.WriteInt64(0) // char length
+		wr.WriteInt64(0) // byte length
+		wr.WriteUint64(1)
+		wr.WriteInt32(0) // chunk length
+	}
+
+	wr.WriteInt8(int8(tcBlob))
+	wr.WriteInt8(int8(loNullindicator))
+	writeLobField(loDataincluded | loLastdata)
+	writeLobField(loDataincluded) // empty lob without last data flag
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Session{readLobRequest: new(readLobRequest), readLobReply: new(readLobReply)}
+	rd := bufio.NewReader(buf)
+
+	null, _, err := readLob(s, rd, tcBlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !null {
+		t.Fatal("null lob expected")
+	}
+
+	for i := 0; i < 2; i++ {
+		null, w, err := readLob(s, rd, tcBlob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if null {
+			t.Fatalf("lob %d: empty lob expected", i)
+		}
+		b := new(bytes.Buffer)
+		if err := w.SetWriter(b); err != nil { // must not read from database
+			t.Fatal(err)
+		}
+		if b.Len() != 0 {
+			t.Fatalf("lob %d: size %d - expected %d", i, b.Len(), 0)
+		}
+	}
+}
+
+func TestCharLobChunkBoundaries(t *testing.T) {
+	euro := []byte("€")
+	high := make([]byte, cesu8.CESUMax)
+	cesu8.EncodeRune(high, '𝕳')
+
+	join := func(b ...[]byte) []byte { return bytes.Join(b, nil) }
+
+	var data = []struct {
+		chunks  [][]byte
+		readOfs []int64
+		s       string
+	}{
+		{[][]byte{[]byte("ab"), []byte("c")}, []int64{2, 3}, "abc"},
+		// incomplete character: next chunk starts with the character again
+		{[][]byte{join([]byte("a"), euro[:2]), join(euro, []byte("b"))}, []int64{1, 3}, "a€b"},
+		// high surrogate
+		{[][]byte{join([]byte("a"), high[:3]), join(high[3:], []byte("b"))}, []int64{2, 4}, "a𝕳b"},
+		// high surrogate and incomplete low surrogate
+		{[][]byte{join([]byte("a"), high[:4]), join(high[3:], []byte("b"))}, []int64{2, 4}, "a𝕳b"},
+	}
+
+	for i, d := range data {
+		out := new(bytes.Buffer)
+		l := &charLobChunkWriter{wr: out}
+
+		for j, chunk := range d.chunks {
+			if err := l.write(bufio.NewReader(bytes.NewReader(chunk)), len(chunk), j == len(d.chunks)-1); err != nil {
+				t.Fatalf("%d chunk %d: %s", i, j, err)
+			}
+			if l.readOfs != d.readOfs[j] {
+				t.Fatalf("%d chunk %d: read offset %d - expected %d", i, j, l.readOfs, d.readOfs[j])
+			}
+		}
+		if out.String() != d.s {
+			t.Fatalf("%d: value %q - expected %q", i, out.String(), d.s)
+		}
+	}
+
+	// incomplete character at end of lob
+	chunk := join([]byte("a"), euro[:2])
+	l := &charLobChunkWriter{wr: new(bytes.Buffer)}
+	if err := l.write(bufio.NewReader(bytes.NewReader(chunk)), len(chunk), true); err != unicode.ErrInvalidCesu8 {
+		t.Fatalf("error %v - expected %v", err, unicode.ErrInvalidCesu8)
+	}
+}
+
+func TestWriteLobStreamCancel(t *testing.T) {
+	in := new(bytes.Buffer) // database server replies
+	wr := bufio.NewWriter(in)
+
+	// rollback replies (abort and commit)
+	for i := 0; i < 2; i++ {
+		mh := &messageHeader{varPartLength: segmentHeaderSize, varPartSize: segmentHeaderSize, noOfSegm: 1}
+		mh.write(wr)
+		sh := &segmentHeader{segmentLength: segmentHeaderSize, segmentNo: 1, segmentKind: skReply, functionCode: fcRollback}
+		sh.write(wr)
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer) // client requests
+
+	s := &Session{
+		conn:            &sessionConn{inTx: true},
+		rd:              bufio.NewReader(in),
+		wr:              bufio.NewWriter(out),
+		mh:              new(messageHeader),
+		sh:              new(segmentHeader),
+		ph:              new(partHeader),
+		resultset:       new(resultset),
+		rowsAffected:    new(rowsAffected),
+		stmtCtx:         newStatementContext(),
+		lastError:       new(hdbErrors),
+		writeLobRequest: new(writeLobRequest),
+		writeLobReply:   &writeLobReply{ids: []locatorID{1}, numArg: 1},
+	}
+
+	prmFieldSet := newParameterFieldSet(1)
+	prmFieldSet.fields[0] = &ParameterField{tc: tcBlob, mode: pmIn}
+	prmFieldSet._inputFields = append(prmFieldSet._inputFields, prmFieldSet.fields[0])
+	args := []driver.NamedValue{{Value: prmFieldSet.fields[0].LobValue(bytes.NewReader([]byte("lob")))}}
+
+	done := make(chan struct{})
+	close(done)
+
+	if err := s.writeLobStream(prmFieldSet, nil, args, 0, done); err != errWriteLobCanceled {
+		t.Fatalf("error %v - expected %v", err, errWriteLobCanceled)
+	}
+	if !s.conn.inTx {
+		t.Fatal("transaction mode expected")
+	}
+
+	// commit of the rolled back transaction fails
+	if err := s.Commit(); err == nil {
+		t.Fatal("commit error expected")
+	}
+	if s.conn.inTx {
+		t.Fatal("transaction mode not expected")
+	}
+
+	// no lob chunk but rollback request written, commit is replaced by rollback
+	rd := bufio.NewReader(out)
+	for i := 0; i < 2; i++ {
+		if err := s.mh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.sh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if s.sh.messageType != mtRollback {
+			t.Fatalf("%d: message type %s - expected %s", i, s.sh.messageType, mtRollback)
+		}
+		rd.Skip(int(s.mh.varPartLength) - segmentHeaderSize)
+	}
+}
+
+func TestWriteLobStreamReadError(t *testing.T) {
+	in := new(bytes.Buffer) // database server replies
+	wr := bufio.NewWriter(in)
+
+	// write lob reply (first chunk) and rollback reply
+	for _, fc := range []functionCode{fcWriteLob, fcRollback} {
+		mh := &messageHeader{varPartLength: segmentHeaderSize, varPartSize: segmentHeaderSize, noOfSegm: 1}
+		mh.write(wr)
+		sh := &segmentHeader{segmentLength: segmentHeaderSize, segmentNo: 1, segmentKind: skReply, functionCode: fc}
+		sh.write(wr)
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer) // client requests
+
+	s := &Session{
+		conn:            &sessionConn{},
+		rd:              bufio.NewReader(in),
+		wr:              bufio.NewWriter(out),
+		mh:              new(messageHeader),
+		sh:              new(segmentHeader),
+		ph:              new(partHeader),
+		resultset:       new(resultset),
+		rowsAffected:    new(rowsAffected),
+		stmtCtx:         newStatementContext(),
+		lastError:       new(hdbErrors),
+		writeLobRequest: new(writeLobRequest),
+		writeLobReply:   &writeLobReply{ids: []locatorID{1}, numArg: 1},
+	}
+
+	prmFieldSet := newParameterFieldSet(1)
+	prmFieldSet.fields[0] = &ParameterField{tc: tcBlob, mode: pmIn}
+	prmFieldSet._inputFields = append(prmFieldSet._inputFields, prmFieldSet.fields[0])
+	// stream of unknown size failing after the first chunk
+	args := []driver.NamedValue{{Value: prmFieldSet.fields[0].LobValue(io.MultiReader(strings.NewReader("lob"), errorReader{}))}}
+
+	err := s.writeLobStream(prmFieldSet, nil, args, 0, nil)
+	if err == nil || !strings.Contains(err.Error(), "read failed") {
+		t.Fatalf("error %v - expected lob read error", err)
+	}
+
+	// first chunk and rollback request written
+	rd := bufio.NewReader(out)
+	for _, mt := range []messageType{mtReadLob, mtRollback} {
+		if err := s.mh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.sh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if s.sh.messageType != mt {
+			t.Fatalf("message type %s - expected %s", s.sh.messageType, mt)
+		}
+		rd.Skip(int(s.mh.varPartLength) - segmentHeaderSize)
+	}
+}
+
+func TestBinaryLobReadAt(t *testing.T) {
+	const size = 10000
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i)
+	}
+
+	in := new(bytes.Buffer) // database server replies
+	wr := bufio.NewWriter(in)
+
+	// read lob reply of the last 1000 bytes
+	tail := content[9000:]
+	writeTestReply(wr, fcReadLob, []testReplyPart{{pkReadLobReply, 1, 16 + len(tail), 0, func(wr *bufio.Writer) {
+		wr.WriteUint64(1) // locator id
+		wr.WriteInt8(int8(loDataincluded | loLastdata))
+		wr.WriteInt32(int32(len(tail)))
+		wr.WriteZeroes(3)
+		wr.Write(tail)
+	}}})
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer) // client requests
+
+	s := &Session{
+		conn:           &sessionConn{},
+		rd:             bufio.NewReader(in),
+		wr:             bufio.NewWriter(out),
+		mh:             new(messageHeader),
+		sh:             new(segmentHeader),
+		ph:             new(partHeader),
+		resultset:      new(resultset),
+		rowsAffected:   new(rowsAffected),
+		stmtCtx:        newStatementContext(),
+		lastError:      new(hdbErrors),
+		readLobRequest: new(readLobRequest),
+		readLobReply:   new(readLobReply),
+	}
+
+	l := &binaryLobChunkWriter{s: s, _id: 1, charLen: size, byteLen: size, b: content[:100]} // first chunk read with field
+
+	// range within first chunk: no database request
+	p := make([]byte, 50)
+	if n, err := l.ReadAt(p, 20); err != nil || n != len(p) || !bytes.Equal(p, content[20:70]) {
+		t.Fatalf("read %d bytes error %v - expected %d bytes", n, err, len(p))
+	}
+	if out.Len() != 0 {
+		t.Fatal("no database request expected")
+	}
+
+	// range exceeding lob size
+	p = make([]byte, 5000)
+	n, err := l.ReadAt(p, 9000)
+	if err != io.EOF || n != len(tail) || !bytes.Equal(p[:n], tail) {
+		t.Fatalf("read %d bytes error %v - expected %d bytes and %v", n, err, len(tail), io.EOF)
+	}
+
+	// read lob request of the range (1-based offset)
+	rd := bufio.NewReader(out)
+	if err := s.mh.read(rd); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.sh.read(rd); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ph.read(rd); err != nil {
+		t.Fatal(err)
+	}
+	if s.ph.partKind != pkReadLobRequest {
+		t.Fatalf("part kind %s - expected %s", s.ph.partKind, pkReadLobRequest)
+	}
+	if id, ofs, length := rd.ReadUint64(), rd.ReadInt64(), rd.ReadInt32(); id != 1 || ofs != 9001 || length != int32(len(tail)) {
+		t.Fatalf("locator %d offset %d length %d - expected %d %d %d", id, ofs, length, 1, 9001, len(tail))
+	}
+
+	if _, err := l.ReadAt(p, size); err != io.EOF {
+		t.Fatalf("error %v - expected %v", err, io.EOF)
+	}
+	if _, err := l.ReadAt(p, -1); err == nil {
+		t.Fatal("negative offset error expected")
+	}
+}
@@ -11,6 +11,7 @@ const (
 	_messageType_name_3 = "mtWriteLobmtReadLobmtFindLob"
 	_messageType_name_4 = "mtAuthenticatemtConnectmtCommitmtRollbackmtCloseResultsetmtDropStatementIDmtFetchNextmtFetchAbsolutemtFetchRelativemtFetchFirstmtFetchLast"
 	_messageType_name_5 = "mtDisconnectmtExecuteITabmtFetchNextITabmtInsertNextITab"
+	_messageType_name_6 = "mtDBConnectInfo"
 )
 
 var (
@@ -38,6 +39,8 @@ func (i messageType) String() string {
 	case 77 <= i && i <= 80:
 		i -= 77
 		return _messageType_name_5[_messageType_index_5[i]:_messageType_index_5[i+1]]
+	case i == 82:
+		return _messageType_name_6
 	default:
 		return "messageType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
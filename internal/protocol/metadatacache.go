@@ -0,0 +1,96 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+type metadataCacheEntry struct {
+	prmData        []byte // raw parameter metadata part
+	resultData     []byte // raw result metadata part
+	prmFieldSet    *ParameterFieldSet
+	resultFieldSet *ResultFieldSet
+}
+
+/*
+MetadataCache caches the parameter and result metadata of prepared statements by query
+and may be shared by sessions (connections).
+
+The metadata returned by a prepare request are compared with the cached metadata of the query:
+if they are equal the cached (parsed) field sets are reused, otherwise (e.g. after a schema change)
+the cache entry is replaced.
+*/
+type MetadataCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*metadataCacheEntry
+}
+
+// NewMetadataCache returns a metadata cache storing the metadata of up to size queries.
+func NewMetadataCache(size int) *MetadataCache {
+	return &MetadataCache{size: size, entries: make(map[string]*metadataCacheEntry)}
+}
+
+// Len returns the number of cached queries.
+func (c *MetadataCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// fieldSets returns the field sets of the raw prepare metadata parts (nil: part not included in reply).
+func (c *MetadataCache) fieldSets(query string, prmNumArg int, prmData []byte, resultNumArg int, resultData []byte) (*ParameterFieldSet, *ResultFieldSet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[query]; ok && bytes.Equal(e.prmData, prmData) && bytes.Equal(e.resultData, resultData) &&
+		(e.prmData == nil) == (prmData == nil) && (e.resultData == nil) == (resultData == nil) {
+		return e.prmFieldSet, e.resultFieldSet, nil
+	}
+
+	e := &metadataCacheEntry{prmData: prmData, resultData: resultData}
+
+	if prmData != nil {
+		e.prmFieldSet = newParameterFieldSet(prmNumArg)
+		rd := bufio.NewReader(bytes.NewReader(prmData))
+		e.prmFieldSet.read(rd)
+		if err := rd.GetError(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if resultData != nil {
+		e.resultFieldSet = newResultFieldSet(resultNumArg)
+		rd := bufio.NewReader(bytes.NewReader(resultData))
+		e.resultFieldSet.read(rd)
+		if err := rd.GetError(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if _, ok := c.entries[query]; !ok && len(c.entries) >= c.size {
+		for k := range c.entries { // cache full: evict an arbitrary entry
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[query] = e
+	return e.prmFieldSet, e.resultFieldSet, nil
+}
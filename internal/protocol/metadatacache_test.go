@@ -0,0 +1,93 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+// resultMetadataData returns the raw result metadata of integer columns with the given display names.
+func resultMetadataData(t *testing.T, names ...string) []byte {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	offset := uint32(0)
+	for _, name := range names {
+		wr.WriteInt8(0) // column options
+		wr.WriteInt8(int8(tcInteger))
+		wr.WriteInt16(0) // fraction
+		wr.WriteInt16(10)
+		wr.WriteZeroes(2)
+		for i := 0; i < columnDisplayName; i++ {
+			wr.WriteUint32(noFieldName)
+		}
+		wr.WriteUint32(offset)
+		offset += uint32(1 + len(name))
+	}
+	for _, name := range names {
+		wr.WriteB(byte(len(name)))
+		wr.WriteString(name)
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestMetadataCache(t *testing.T) {
+	const query = "select * from t"
+
+	c := NewMetadataCache(1)
+
+	data := resultMetadataData(t, "A")
+	_, f1, err := c.fieldSets(query, 0, nil, 1, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f1.NumField() != 1 || f1.Field(0).Name() != "A" {
+		t.Fatalf("field set %s - expected field A", f1)
+	}
+
+	// equal metadata: cached field set
+	_, f2, err := c.fieldSets(query, 0, nil, 1, append([]byte(nil), data...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f2 != f1 {
+		t.Fatal("cached field set expected")
+	}
+
+	// changed metadata: entry replaced
+	_, f3, err := c.fieldSets(query, 0, nil, 2, resultMetadataData(t, "A", "B"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f3 == f1 || f3.NumField() != 2 || f3.Field(1).Name() != "B" {
+		t.Fatalf("field set %s - expected fields A, B", f3)
+	}
+
+	// cache full
+	if _, _, err := c.fieldSets("select 1 from dummy", 0, nil, 1, data); err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 1 {
+		t.Fatalf("cache size %d - expected %d", c.Len(), 1)
+	}
+}
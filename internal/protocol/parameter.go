@@ -20,6 +20,9 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
 
 	"github.com/SAP/go-hdb/internal/bufio"
 )
@@ -132,6 +135,11 @@ func (f *ParameterFieldSet) outputFields() []*ParameterField {
 	return f._outputFields
 }
 
+// NumField returns the number of parameter fields in a database statement.
+func (f *ParameterFieldSet) NumField() int {
+	return len(f.fields)
+}
+
 // NumInputField returns the number of input fields in a database statement.
 func (f *ParameterFieldSet) NumInputField() int {
 	return len(f._inputFields)
@@ -147,6 +155,11 @@ func (f *ParameterFieldSet) Field(idx int) *ParameterField {
 	return f.fields[idx]
 }
 
+// InputField returns the input field at index idx.
+func (f *ParameterFieldSet) InputField(idx int) *ParameterField {
+	return f._inputFields[idx]
+}
+
 // OutputField returns the output field at index idx.
 func (f *ParameterFieldSet) OutputField(idx int) *ParameterField {
 	return f._outputFields[idx]
@@ -161,8 +174,6 @@ type ParameterField struct {
 	fraction         int16
 	length           int16
 	offset           uint32
-	chunkReader      lobChunkReader
-	lobLocatorID     locatorID
 }
 
 func newParameterField(fieldNames fieldNames) *ParameterField {
@@ -207,7 +218,13 @@ func (f *ParameterField) TypePrecisionScale() (int64, int64, bool) {
 // Nullable returns true if the field may be null, false otherwise.
 // see https://golang.org/pkg/database/sql/driver/#RowsColumnTypeNullable
 func (f *ParameterField) Nullable() bool {
-	return f.parameterOptions == poOptional
+	return f.parameterOptions&poOptional != 0
+}
+
+// HasDefault returns true if the parameter has a default value (e.g. procedure parameters with default),
+// false otherwise.
+func (f *ParameterField) HasDefault() bool {
+	return f.parameterOptions&poDefault != 0
 }
 
 // In returns true if the parameter field is an input field.
@@ -225,10 +242,10 @@ func (f *ParameterField) Name() string {
 	return f.fieldNames.name(f.offset)
 }
 
-// SetLobReader sets the io.Reader if a Lob parameter field.
-func (f *ParameterField) SetLobReader(rd io.Reader) error {
-	f.chunkReader = newLobChunkReader(f.TypeCode().isCharBased(), rd)
-	return nil
+// LobValue returns the parameter value of a Lob parameter field reading the lob content from rd.
+// As every value gets its own reader, lob parameters can be used in bulk inserts.
+func (f *ParameterField) LobValue(rd io.Reader) driver.Value {
+	return newLobChunkReader(f.TypeCode().isCharBased(), rd)
 }
 
 //
@@ -249,6 +266,7 @@ func (f *ParameterField) read(rd *bufio.Reader) {
 type parameterMetadata struct {
 	prmFieldSet *ParameterFieldSet
 	numArg      int
+	b           []byte // if not nil: read raw metadata part (see MetadataCache)
 }
 
 func (m *parameterMetadata) String() string {
@@ -261,10 +279,20 @@ func (m *parameterMetadata) kind() partKind {
 
 func (m *parameterMetadata) setNumArg(numArg int) {
 	m.numArg = numArg
+	m.prmFieldSet = nil // set by reply callback (see readReply)
 }
 
 func (m *parameterMetadata) read(rd *bufio.Reader) error {
 
+	if m.prmFieldSet == nil { // metadata not requested: do not overwrite field set of a previous reply
+		m.prmFieldSet = newParameterFieldSet(m.numArg)
+	}
+
+	if m.b != nil {
+		rd.ReadFull(m.b)
+		return rd.GetError()
+	}
+
 	m.prmFieldSet.read(rd)
 
 	if trace {
@@ -274,14 +302,113 @@ func (m *parameterMetadata) read(rd *bufio.Reader) error {
 	return rd.GetError()
 }
 
+// inputOptions are the session options applied to input parameter values.
+type inputOptions struct {
+	emptyStringAsNull bool              // bind empty strings of character type parameters as null values
+	charEncoding      encoding.Encoding // character encoding of non-unicode character parameters (nil: no conversion)
+	validateUTF8      bool              // check unicode character parameters for valid UTF-8 before sending
+}
+
 // input parameters
 type inputParameters struct {
 	inputFields []*ParameterField
 	args        []driver.NamedValue
+	opts        inputOptions
+}
+
+func newInputParameters(inputFields []*ParameterField, args []driver.NamedValue, opts inputOptions) *inputParameters {
+	return &inputParameters{inputFields: inputFields, args: args, opts: opts}
+}
+
+// A TypedNull is a NULL parameter value of a specific type: the null value is written with the type code
+// of the typed null instead of the type code of the parameter.
+type TypedNull TypeCode
+
+// typedArg returns the type code and argument i, whereby a typed null is returned as null value of its type code.
+func (p *inputParameters) typedArg(tc TypeCode, i int) (TypeCode, driver.NamedValue, error) {
+	if null, ok := p.args[i].Value.(TypedNull); ok {
+		arg := p.args[i]
+		arg.Value = nil
+		return TypeCode(null), arg, nil
+	}
+	arg, err := p.arg(tc, i)
+	return tc, arg, err
+}
+
+// argName returns the 1-based parameter index and name of argument i, including the 1-based row for multiple rows (bulk).
+func (p *inputParameters) argName(i int) string {
+	cnt := len(p.inputFields)
+	s := fmt.Sprintf("parameter %d", i%cnt+1)
+	if name := p.inputFields[i%cnt].Name(); name != "" {
+		s += fmt.Sprintf(" (%s)", name)
+	}
+	if len(p.args) > cnt {
+		s += fmt.Sprintf(" row %d", i/cnt+1)
+	}
+	return s
+}
+
+// arg returns argument i, replacing an empty string value of a character type parameter by null if enabled,
+// encoding the value of a non-unicode character parameter by the character encoding if set and checking
+// the value of a unicode character parameter for valid UTF-8 if enabled.
+func (p *inputParameters) arg(tc TypeCode, i int) (driver.NamedValue, error) {
+	arg := p.args[i]
+	if !tc.isString() {
+		return arg, nil
+	}
+	if p.opts.validateUTF8 && !tc.isNonUnicodeChar() {
+		if ofs := invalidUTF8Offset(arg.Value); ofs != -1 {
+			return arg, fmt.Errorf("%s: invalid UTF-8 encoding at byte offset %d", p.argName(i), ofs)
+		}
+	}
+	if p.opts.emptyStringAsNull {
+		switch v := arg.Value.(type) {
+		case string:
+			if v == "" {
+				arg.Value = nil
+			}
+		case []byte:
+			if len(v) == 0 {
+				arg.Value = nil
+			}
+		}
+	}
+	if p.opts.charEncoding != nil && arg.Value != nil {
+		v, err := encodeCharValue(p.opts.charEncoding, tc, arg.Value)
+		if err != nil {
+			return arg, fmt.Errorf("%s: %s", p.argName(i), err)
+		}
+		arg.Value = v
+	}
+	return arg, nil
 }
 
-func newInputParameters(inputFields []*ParameterField, args []driver.NamedValue) *inputParameters {
-	return &inputParameters{inputFields: inputFields, args: args}
+// invalidUTF8Offset returns the byte offset of the first invalid UTF-8 sequence of a string or byte slice value
+// or -1, if the value is valid UTF-8 or not a string value.
+func invalidUTF8Offset(v driver.Value) int {
+	var b []byte
+	switch v := v.(type) {
+	case string:
+		if utf8.ValidString(v) {
+			return -1
+		}
+		b = []byte(v)
+	case []byte:
+		if utf8.Valid(v) {
+			return -1
+		}
+		b = v
+	default:
+		return -1
+	}
+	for ofs := 0; ofs < len(b); {
+		r, size := utf8.DecodeRune(b[ofs:])
+		if r == utf8.RuneError && size == 1 {
+			return ofs
+		}
+		ofs += size
+	}
+	return -1
 }
 
 func (p *inputParameters) String() string {
@@ -293,20 +420,30 @@ func (p *inputParameters) kind() partKind {
 }
 
 func (p *inputParameters) size() (int, error) {
+	return p.rangeSize(0, len(p.args))
+}
+
+// rangeSize returns the size of the arguments from..to-1, so that argument errors refer to the
+// absolute parameter row.
+func (p *inputParameters) rangeSize(from, to int) (int, error) {
 
-	size := len(p.args)
+	size := to - from
 	cnt := len(p.inputFields)
 
-	for i, arg := range p.args {
+	for i := from; i < to; i++ {
+
+		// mass insert
+		field := p.inputFields[i%cnt]
 
+		tc, arg, err := p.typedArg(field.TypeCode(), i)
+		if err != nil {
+			return 0, err
+		}
 		if arg.Value == nil { // null value
 			continue
 		}
 
-		// mass insert
-		field := p.inputFields[i%cnt]
-
-		fieldSize, err := fieldSize(field.TypeCode(), arg)
+		fieldSize, err := fieldSize(tc, arg)
 		if err != nil {
 			return 0, err
 		}
@@ -317,6 +454,42 @@ func (p *inputParameters) size() (int, error) {
 	return size, nil
 }
 
+// split splits the input parameter rows (mass insert) into chunks, so that the size of each chunk
+// does not exceed maxSize and the number of rows of each chunk does not exceed maxRows (0: no limit).
+// The order of the rows is preserved.
+func (p *inputParameters) split(maxSize, maxRows int) ([]*inputParameters, error) {
+
+	cnt := len(p.inputFields)
+
+	if cnt == 0 { // no parameters
+		return []*inputParameters{p}, nil
+	}
+
+	var chunks []*inputParameters
+
+	start, size := 0, 0
+
+	for i := 0; i < len(p.args); i += cnt {
+
+		rowSize, err := p.rangeSize(i, i+cnt)
+		if err != nil {
+			return nil, err
+		}
+		if rowSize > maxSize {
+			return nil, fmt.Errorf("size %d of parameter row %d exceeds maximum packet size %d", rowSize, i/cnt+1, maxSize)
+		}
+
+		if size+rowSize > maxSize || (maxRows > 0 && (i-start)/cnt == maxRows) {
+			chunks = append(chunks, newInputParameters(p.inputFields, p.args[start:i], p.opts))
+			start, size = i, 0
+		}
+		size += rowSize
+	}
+	chunks = append(chunks, newInputParameters(p.inputFields, p.args[start:], p.opts))
+
+	return chunks, nil
+}
+
 func (p *inputParameters) numArg() int {
 	cnt := len(p.inputFields)
 
@@ -331,12 +504,16 @@ func (p *inputParameters) write(wr *bufio.Writer) error {
 
 	cnt := len(p.inputFields)
 
-	for i, arg := range p.args {
+	for i := range p.args {
 
 		//mass insert
 		field := p.inputFields[i%cnt]
 
-		if err := writeField(wr, field.TypeCode(), arg); err != nil {
+		tc, arg, err := p.typedArg(field.TypeCode(), i)
+		if err != nil {
+			return err
+		}
+		if err := writeField(wr, tc, arg); err != nil {
 			return err
 		}
 	}
@@ -375,10 +552,14 @@ func (p *outputParameters) read(rd *bufio.Reader) error {
 
 	for i := 0; i < p.numArg; i++ {
 		for j, field := range p.outputFields {
-			var err error
-			if p.fieldValues.values[i*cols+j], err = readField(p.s, rd, field.TypeCode()); err != nil {
-				return err
+			v, err := p.fieldValues.readField(p.s, rd, field.TypeCode())
+			if err == nil {
+				err = rd.GetError() // decoding error of field (e.g. invalid CESU-8)
+			}
+			if err != nil {
+				return newFieldError(j, field.Name(), err)
 			}
+			p.fieldValues.values[i*cols+j] = v
 		}
 	}
 
@@ -0,0 +1,298 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+func TestSplitInputParameters(t *testing.T) {
+	inputFields := []*ParameterField{
+		&ParameterField{tc: tcInteger, mode: pmIn},
+		&ParameterField{tc: tcInteger, mode: pmIn},
+	}
+
+	const numRow = 10
+	const rowSize = 2 * (1 + intFieldSize)
+
+	args := make([]driver.NamedValue, 0, numRow*len(inputFields))
+	for i := 0; i < numRow; i++ {
+		args = append(args, driver.NamedValue{Value: int64(i)}, driver.NamedValue{Value: int64(i)})
+	}
+
+	chunks, err := newInputParameters(inputFields, args, inputOptions{}).split(3*rowSize+1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	numRows := []int{3, 3, 3, 1}
+	if len(chunks) != len(numRows) {
+		t.Fatalf("number of chunks %d - expected %d", len(chunks), len(numRows))
+	}
+
+	row := 0
+	for i, chunk := range chunks {
+		if chunk.numArg() != numRows[i] {
+			t.Fatalf("chunk %d: number of rows %d - expected %d", i, chunk.numArg(), numRows[i])
+		}
+		for j := 0; j < len(chunk.args); j += len(inputFields) { // row order
+			if chunk.args[j].Value != int64(row) {
+				t.Fatalf("chunk %d: value %v - expected %d", i, chunk.args[j].Value, row)
+			}
+			row++
+		}
+	}
+
+	_, err = newInputParameters(inputFields, args, inputOptions{}).split(rowSize-1, 0)
+	if err == nil {
+		t.Fatal("row size error expected")
+	}
+	if expected := fmt.Sprintf("size %d of parameter row 1 exceeds maximum packet size %d", rowSize, rowSize-1); err.Error() != expected {
+		t.Fatalf("error %q - expected %q", err, expected)
+	}
+
+	// no array execution: one row per chunk
+	chunks, err = newInputParameters(inputFields, args, inputOptions{}).split(3*rowSize+1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != numRow {
+		t.Fatalf("number of chunks %d - expected %d", len(chunks), numRow)
+	}
+	for i, chunk := range chunks {
+		if chunk.numArg() != 1 || chunk.args[0].Value != int64(i) {
+			t.Fatalf("chunk %d: number of rows %d value %v - expected %d %d", i, chunk.numArg(), chunk.args[0].Value, 1, i)
+		}
+	}
+}
+
+func TestInputParametersEmptyStringAsNull(t *testing.T) {
+	inputFields := []*ParameterField{
+		&ParameterField{tc: tcVarchar, mode: pmIn},
+		&ParameterField{tc: tcNvarchar, mode: pmIn},
+		&ParameterField{tc: tcVarbinary, mode: pmIn},
+	}
+	args := []driver.NamedValue{{Value: ""}, {Value: []byte{}}, {Value: []byte{}}}
+
+	for _, emptyStringAsNull := range []bool{false, true} {
+		p := newInputParameters(inputFields, args, inputOptions{emptyStringAsNull: emptyStringAsNull})
+
+		size, err := p.size()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedSize := len(args) // null values: type code only
+		if !emptyStringAsNull {
+			expectedSize += 2 // empty string values: type code and length indicator
+		}
+		expectedSize++ // empty binary value is not affected
+		if size != expectedSize {
+			t.Fatalf("empty string as null %t: size %d - expected %d", emptyStringAsNull, size, expectedSize)
+		}
+
+		for i, field := range inputFields {
+			arg, err := p.arg(field.TypeCode(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			null := arg.Value == nil
+			if null != (emptyStringAsNull && field.TypeCode() != tcVarbinary) {
+				t.Fatalf("empty string as null %t: argument %d null %t", emptyStringAsNull, i, null)
+			}
+		}
+	}
+}
+
+func TestInputParametersValidateUTF8(t *testing.T) {
+	inputFields := []*ParameterField{
+		&ParameterField{tc: tcVarchar, mode: pmIn},
+		&ParameterField{tc: tcNvarchar, mode: pmIn},
+		&ParameterField{tc: tcNvarchar, mode: pmIn},
+	}
+	args := []driver.NamedValue{{Value: "\xff"}, {Value: "valid äöü"}, {Value: []byte("ab\xc3")}}
+
+	p := newInputParameters(inputFields, args, inputOptions{})
+	if _, err := p.size(); err != nil { // not validated
+		t.Fatal(err)
+	}
+
+	p = newInputParameters(inputFields, args, inputOptions{validateUTF8: true})
+	for i, field := range inputFields[:2] { // non-unicode parameter is not validated
+		if _, err := p.arg(field.TypeCode(), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := p.arg(inputFields[2].TypeCode(), 2)
+	if err == nil {
+		t.Fatal("invalid UTF-8 error expected")
+	}
+	const expected = "parameter 3: invalid UTF-8 encoding at byte offset 2"
+	if err.Error() != expected {
+		t.Fatalf("error %q - expected %q", err, expected)
+	}
+	if _, err := p.size(); err == nil {
+		t.Fatal("invalid UTF-8 error expected")
+	}
+
+	// bulk: second row
+	bulkArgs := []driver.NamedValue{{Value: "a"}, {Value: "b"}, {Value: "c"}, {Value: "a"}, {Value: "b"}, {Value: []byte("ab\xc3")}}
+	p = newInputParameters(inputFields, bulkArgs, inputOptions{validateUTF8: true})
+	const expectedBulk = "parameter 3 row 2: invalid UTF-8 encoding at byte offset 2"
+	if _, err := p.arg(inputFields[2].TypeCode(), 5); err == nil || err.Error() != expectedBulk {
+		t.Fatalf("error %v - expected %q", err, expectedBulk)
+	}
+
+	// bulk split into single row chunks: the row is reported relative to all rows
+	if _, err := p.split(1<<16, 1); err == nil || err.Error() != expectedBulk {
+		t.Fatalf("error %v - expected %q", err, expectedBulk)
+	}
+}
+
+func TestInputParametersCharEncoding(t *testing.T) {
+	inputFields := []*ParameterField{
+		&ParameterField{tc: tcVarchar, mode: pmIn},
+		&ParameterField{tc: tcNvarchar, mode: pmIn},
+		&ParameterField{tc: tcChar, mode: pmIn},
+	}
+	args := []driver.NamedValue{{Value: "aä"}, {Value: "aä"}, {Value: "€"}}
+
+	p := newInputParameters(inputFields, args, inputOptions{charEncoding: charmap.Windows1252})
+
+	expected := []driver.Value{[]byte{'a', 0xe4}, "aä", []byte{0x80}}
+	for i, field := range inputFields {
+		arg, err := p.arg(field.TypeCode(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(arg.Value, expected[i]) {
+			t.Fatalf("argument %d: value %v - expected %v", i, arg.Value, expected[i])
+		}
+	}
+
+	// character not supported by encoding
+	p.args = []driver.NamedValue{{Value: "𝕳"}, {Value: ""}, {Value: ""}}
+	_, err := p.size()
+	if err == nil {
+		t.Fatal("encoding error expected")
+	}
+	if expected := "parameter 1: "; !strings.HasPrefix(err.Error(), expected) { // 1-based parameter index
+		t.Fatalf("error %q - expected prefix %q", err, expected)
+	}
+}
+
+func TestParameterOptions(t *testing.T) {
+	var data = []struct {
+		options    parameterOptions
+		nullable   bool
+		hasDefault bool
+	}{
+		{poMandatory, false, false},
+		{poOptional, true, false},
+		{poMandatory | poDefault, false, true},
+		{poOptional | poDefault, true, true},
+	}
+
+	for _, d := range data {
+		f := &ParameterField{parameterOptions: d.options}
+		if f.Nullable() != d.nullable || f.HasDefault() != d.hasDefault {
+			t.Fatalf("options %s: nullable %t default %t - expected %t %t", d.options, f.Nullable(), f.HasDefault(), d.nullable, d.hasDefault)
+		}
+	}
+}
+
+func TestInputParametersTypedNull(t *testing.T) {
+	inputFields := []*ParameterField{
+		&ParameterField{tc: tcNvarchar, mode: pmIn},
+		&ParameterField{tc: tcNvarchar, mode: pmIn},
+	}
+	args := []driver.NamedValue{{Value: TypedNull(tcInteger)}, {Value: nil}}
+
+	p := newInputParameters(inputFields, args, inputOptions{charEncoding: charmap.Windows1252})
+
+	size, err := p.size()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	if err := p.write(wr); err != nil {
+		t.Fatal(err)
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.Len() != size {
+		t.Fatalf("size %d - expected %d", buf.Len(), size)
+	}
+	// null values: type code with high bit set
+	if expected := []byte{byte(tcInteger) | 0x80, byte(tcNvarchar) | 0x80}; !bytes.Equal(buf.Bytes(), expected) {
+		t.Fatalf("parameters %x - expected %x", buf.Bytes(), expected)
+	}
+}
+
+func TestParameterFieldSetInputField(t *testing.T) {
+	// procedure signature (out o integer, in a integer, inout b integer)
+	fields := []struct {
+		mode parameterMode
+		name string
+	}{{pmOut, "O"}, {pmIn, "A"}, {pmInout, "B"}}
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	offset := uint32(0)
+	for _, f := range fields {
+		wr.WriteInt8(0) // parameter options
+		wr.WriteInt8(int8(tcInteger))
+		wr.WriteInt8(int8(f.mode))
+		wr.WriteZeroes(1)
+		wr.WriteUint32(offset)
+		wr.WriteInt16(10) // length
+		wr.WriteInt16(0)  // fraction
+		wr.WriteZeroes(4)
+		offset += uint32(1 + len(f.name))
+	}
+	for _, f := range fields {
+		wr.WriteInt8(int8(len(f.name)))
+		wr.WriteString(f.name)
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	prmFieldSet := newParameterFieldSet(len(fields))
+	prmFieldSet.read(bufio.NewReader(buf))
+
+	if prmFieldSet.NumInputField() != 2 {
+		t.Fatalf("number of input fields %d - expected %d", prmFieldSet.NumInputField(), 2)
+	}
+	for i, name := range []string{"A", "B"} {
+		if f := prmFieldSet.InputField(i); f.Name() != name || !f.In() {
+			t.Fatalf("input field %d: name %s - expected %s", i, f.Name(), name)
+		}
+	}
+}
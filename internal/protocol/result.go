@@ -17,8 +17,11 @@ limitations under the License.
 package protocol
 
 import (
+	"database/sql/driver"
 	"fmt"
 
+	"golang.org/x/text/encoding"
+
 	"github.com/SAP/go-hdb/internal/bufio"
 )
 
@@ -203,7 +206,7 @@ func (f *ResultField) TypePrecisionScale() (int64, int64, bool) {
 // Nullable returns true if the field may be null, false otherwise.
 // see https://golang.org/pkg/database/sql/driver/#RowsColumnTypeNullable
 func (f *ResultField) Nullable() bool {
-	return f.columnOptions == coOptional
+	return f.columnOptions&coOptional != 0 // further option bits may be set
 }
 
 // Name returns the result field name.
@@ -211,6 +214,21 @@ func (f *ResultField) Name() string {
 	return f.fieldNames.name(f.offsets[columnDisplayName])
 }
 
+// TableName returns the database table (or view) name of the result field.
+func (f *ResultField) TableName() string {
+	return f.fieldNames.name(f.offsets[tableName])
+}
+
+// SchemaName returns the database schema name of the result field.
+func (f *ResultField) SchemaName() string {
+	return f.fieldNames.name(f.offsets[schemaName])
+}
+
+// ColumnName returns the database column name of the result field.
+func (f *ResultField) ColumnName() string {
+	return f.fieldNames.name(f.offsets[columnName])
+}
+
 func (f *ResultField) read(rd *bufio.Reader) {
 	f.columnOptions = columnOptions(rd.ReadInt8())
 	f.tc = TypeCode(rd.ReadInt8())
@@ -228,6 +246,7 @@ func (f *ResultField) read(rd *bufio.Reader) {
 type resultMetadata struct {
 	resultFieldSet *ResultFieldSet
 	numArg         int
+	b              []byte // if not nil: read raw metadata part (see MetadataCache)
 }
 
 func (r *resultMetadata) String() string {
@@ -240,10 +259,20 @@ func (r *resultMetadata) kind() partKind {
 
 func (r *resultMetadata) setNumArg(numArg int) {
 	r.numArg = numArg
+	r.resultFieldSet = nil // set by reply callback (see readReply)
 }
 
 func (r *resultMetadata) read(rd *bufio.Reader) error {
 
+	if r.resultFieldSet == nil { // metadata not requested: do not overwrite field set of a previous reply
+		r.resultFieldSet = newResultFieldSet(r.numArg)
+	}
+
+	if r.b != nil {
+		rd.ReadFull(r.b)
+		return rd.GetError()
+	}
+
 	r.resultFieldSet.read(rd)
 
 	if trace {
@@ -259,6 +288,29 @@ type resultset struct {
 	s              *Session
 	resultFieldSet *ResultFieldSet
 	fieldValues    *FieldValues
+	// field values read by the last resultset part of the current reply:
+	// further parts of the same resultset (split across reply segments) are appended
+	lastFieldValues *FieldValues
+	// part attributes of the last resultset part of the current reply
+	attrs partAttributes
+	// buffer length of the current resultset part (0: unknown)
+	size int
+	// the last row read continues in the next resultset part (at field pendingField)
+	splitRow     bool
+	pendingField int
+	// read null values as zero values of the field type
+	nullAsZeroValue bool
+	// trim fixed length character values
+	trimChar bool
+	// character encoding of non-unicode character values (nil: no conversion)
+	charEncoding encoding.Encoding
+}
+
+func (r *resultset) reset() {
+	r.lastFieldValues = nil
+	r.attrs = paLastPacket | paRowNotFound // reply without resultset part: no (further) rows
+	r.size = 0
+	r.splitRow, r.pendingField = false, 0
 }
 
 func (r *resultset) String() string {
@@ -273,18 +325,107 @@ func (r *resultset) setNumArg(numArg int) {
 	r.numArg = numArg
 }
 
+// keepSplitRow is called after reading a reply: a row split across resultset parts, which is not completed
+// by the reply, continues in the reply to the next fetch. Its fields read so far are kept by the field values
+// and the incomplete row is removed, so that only complete rows are provided.
+func (r *resultset) keepSplitRow() error {
+	if !r.splitRow {
+		return nil
+	}
+	if r.attrs.LastPacket() {
+		return fmt.Errorf("incomplete resultset row: field %d of %d not read", r.pendingField, len(r.resultFieldSet.fields))
+	}
+	f := r.fieldValues
+	ofs := (f.rows - 1) * f.cols
+	f.splitRow = make([]driver.Value, r.pendingField)
+	for i, v := range f.values[ofs : ofs+r.pendingField] {
+		if b, ok := v.([]byte); ok { // copy: the field values buffer is reused by the next fetch
+			v = append([]byte(nil), b...)
+		}
+		f.splitRow[i] = v
+	}
+	f.rows--
+	f.values = f.values[:ofs]
+	r.splitRow, r.pendingField = false, 0
+	return nil
+}
+
 func (r *resultset) read(rd *bufio.Reader) error {
 
 	cols := len(r.resultFieldSet.fields)
-	r.fieldValues.resize(r.numArg, cols)
 
-	for i := 0; i < r.numArg; i++ {
-		for j, field := range r.resultFieldSet.fields {
-			var err error
-			if r.fieldValues.values[i*cols+j], err = readField(r.s, rd, field.TypeCode()); err != nil {
+	ofs, first := 0, 0 // first row and field to be read
+	switch {
+	case r.fieldValues != r.lastFieldValues: // new resultset or fetch reply
+		r.splitRow, r.pendingField = false, 0
+		if splitRow := r.fieldValues.splitRow; splitRow != nil { // fetch reply completing a row split across fetch replies
+			if r.numArg < 1 {
+				return fmt.Errorf("invalid number of rows %d of resultset continuation part", r.numArg)
+			}
+			first = len(splitRow)
+			r.fieldValues.splitRow = nil
+			r.fieldValues.resize(r.numArg, cols)
+			copy(r.fieldValues.values, splitRow)
+			break
+		}
+		r.fieldValues.resize(r.numArg, cols)
+	case r.splitRow: // continuation part completing a row split across parts (the row is counted by both parts)
+		if r.numArg < 1 {
+			return fmt.Errorf("invalid number of rows %d of resultset continuation part", r.numArg)
+		}
+		ofs, first = r.fieldValues.rows-1, r.pendingField
+		r.splitRow, r.pendingField = false, 0
+		r.fieldValues.grow(r.numArg-1, cols)
+	default: // continuation part
+		ofs = r.fieldValues.rows
+		r.fieldValues.grow(r.numArg, cols)
+	}
+	r.lastFieldValues = r.fieldValues
+
+	if dec := r.fieldValues.dec; dec != nil {
+		if err := checkRowDecoder(dec, r.resultFieldSet); err != nil {
+			return err
+		}
+		fr := &FieldReader{s: r.s, rd: rd, fv: r.fieldValues}
+		for i := ofs; i < ofs+r.numArg; i++ {
+			if err := dec.DecodeRow(fr, r.fieldValues.values[i*cols:(i+1)*cols]); err != nil {
 				return err
 			}
 		}
+		return rd.GetError()
+	}
+
+	end := rd.InputOffset() + r.size // end of part data
+
+	lastRow := ofs + r.numArg - 1
+	for i := ofs; i <= lastRow; i++ {
+		for j := first; j < cols; j++ {
+			if i == lastRow && r.size != 0 && rd.InputOffset() >= end { // row continues in next resultset part (j == 0: no field in this part)
+				r.splitRow, r.pendingField = true, j
+				break
+			}
+			field := r.resultFieldSet.fields[j]
+			v, err := r.fieldValues.readField(r.s, rd, field.TypeCode())
+			if err == nil {
+				err = rd.GetError() // decoding error of field (e.g. invalid CESU-8)
+			}
+			if err != nil {
+				return newFieldError(j, field.Name(), err)
+			}
+			if v == nil && r.nullAsZeroValue {
+				v = zeroFieldValue(field.TypeCode())
+			}
+			if r.charEncoding != nil {
+				if v, err = decodeCharFieldValue(r.charEncoding, field.TypeCode(), v); err != nil {
+					return newFieldError(j, field.Name(), err)
+				}
+			}
+			if r.trimChar {
+				v = trimCharFieldValue(field.TypeCode(), v)
+			}
+			r.fieldValues.values[i*cols+j] = v
+		}
+		first = 0
 	}
 
 	if trace {
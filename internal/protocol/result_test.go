@@ -0,0 +1,715 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+	"github.com/SAP/go-hdb/internal/unicode/cesu8"
+)
+
+// writeTestResultsetReply writes a reply message containing one integer resultset part per segment.
+func writeTestResultsetReply(t *testing.T, segments [][]int32) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	segmentLength := func(rows []int32) int {
+		size := len(rows) * (1 + intFieldSize)
+		return segmentHeaderSize + partHeaderSize + size + padBytes(size)
+	}
+
+	varPartLength := 0
+	for _, rows := range segments {
+		varPartLength += segmentLength(rows)
+	}
+
+	mh := &messageHeader{varPartLength: uint32(varPartLength), varPartSize: uint32(varPartLength), noOfSegm: int16(len(segments))}
+	mh.write(wr)
+
+	for i, rows := range segments {
+		sh := &segmentHeader{segmentLength: int32(segmentLength(rows)), noOfParts: 1, segmentNo: int16(i + 1), segmentKind: skReply}
+		sh.write(wr)
+
+		size := len(rows) * (1 + intFieldSize)
+		ph := &partHeader{partKind: pkResultset, argumentCount: int16(len(rows)), bufferLength: int32(size), bufferSize: int32(size)}
+		ph.write(wr)
+
+		for _, v := range rows {
+			wr.WriteBool(true) // not null
+			wr.WriteInt32(v)
+		}
+		wr.WriteZeroes(padBytes(size))
+	}
+
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+func TestReadMultiSegmentResultset(t *testing.T) {
+	segments := [][]int32{{1, 2, 3}, {4, 5}, {6}}
+
+	s := &Session{
+		rd:           bufio.NewReader(writeTestResultsetReply(t, segments)),
+		mh:           new(messageHeader),
+		sh:           new(segmentHeader),
+		ph:           new(partHeader),
+		resultset:    new(resultset),
+		rowsAffected: new(rowsAffected),
+		stmtCtx:      newStatementContext(),
+		lastError:    new(hdbErrors),
+	}
+
+	resultFieldSet := newResultFieldSet(1)
+	resultFieldSet.fields[0] = &ResultField{fieldNames: newFieldNames(), tc: tcInteger}
+	fieldValues := new(FieldValues)
+
+	if err := s.readReply(func(p replyPart) {
+		if x, ok := p.(*resultset); ok {
+			x.s = s
+			x.resultFieldSet = resultFieldSet
+			x.fieldValues = fieldValues
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if fieldValues.NumRow() != 6 {
+		t.Fatalf("number of rows %d - expected %d", fieldValues.NumRow(), 6)
+	}
+	dest := make([]driver.Value, 1)
+	for i := 0; i < fieldValues.NumRow(); i++ {
+		fieldValues.Row(i, dest)
+		if dest[0] != int64(i+1) {
+			t.Fatalf("row %d: value %v - expected %d", i, dest[0], i+1)
+		}
+	}
+}
+
+func TestReadEmptyResultsetAttributes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	// one segment: empty resultset part followed by a transaction flags part
+	segmentLength := segmentHeaderSize + 2*partHeaderSize
+	mh := &messageHeader{varPartLength: uint32(segmentLength), varPartSize: uint32(segmentLength), noOfSegm: 1}
+	mh.write(wr)
+	sh := &segmentHeader{segmentLength: int32(segmentLength), noOfParts: 2, segmentNo: 1, segmentKind: skReply}
+	sh.write(wr)
+	ph := &partHeader{partKind: pkResultset, partAttributes: paLastPacket | paRowNotFound | paResultsetClosed}
+	ph.write(wr)
+	ph = &partHeader{partKind: pkTransactionFlags}
+	ph.write(wr)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Session{
+		rd:           bufio.NewReader(buf),
+		mh:           new(messageHeader),
+		sh:           new(segmentHeader),
+		ph:           new(partHeader),
+		resultset:    new(resultset),
+		rowsAffected: new(rowsAffected),
+		stmtCtx:      newStatementContext(),
+		txFlags:      newTransactionFlags(),
+		lastError:    new(hdbErrors),
+	}
+
+	resultFieldSet := newResultFieldSet(1)
+	resultFieldSet.fields[0] = &ResultField{fieldNames: newFieldNames(), tc: tcInteger}
+
+	if err := s.readReply(func(p replyPart) {
+		if x, ok := p.(*resultset); ok {
+			x.s = s
+			x.resultFieldSet = resultFieldSet
+			x.fieldValues = newFieldValues()
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// attributes of the resultset part - not of the last part
+	if !s.resultset.attrs.NoRows() || !s.resultset.attrs.ResultsetClosed() {
+		t.Fatalf("resultset attributes %s - expected %s", s.resultset.attrs, paLastPacket|paRowNotFound|paResultsetClosed)
+	}
+}
+
+// writeTestResultsetRows writes the resultset part content of rows with an integer, a varchar and a nvarchar field.
+func writeTestResultsetRows(b *testing.B, rows int) []byte {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	for i := 0; i < rows; i++ {
+		wr.WriteBool(true) // not null
+		wr.WriteInt32(int32(i))
+		writeBytes(wr, []byte("varchar value"))
+		writeUtf8Bytes(wr, []byte("nvarchar value"))
+	}
+
+	if err := wr.Flush(); err != nil {
+		b.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkReadResultset(b *testing.B) {
+	const rows = 1000
+
+	data := writeTestResultsetRows(b, rows)
+
+	resultFieldSet := newResultFieldSet(3)
+	for i, tc := range []TypeCode{tcInteger, tcVarchar, tcNvarchar} {
+		resultFieldSet.fields[i] = &ResultField{fieldNames: newFieldNames(), tc: tc}
+	}
+	r := &resultset{resultFieldSet: resultFieldSet, fieldValues: newFieldValues(), numArg: rows}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.lastFieldValues = nil // new fetch
+		if err := r.read(bufio.NewReader(bytes.NewReader(data))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadResultsetFieldValues reads a small resultset per query with field values allocated per query (new)
+// and with field values obtained from and released to the field values pool (pooled).
+func BenchmarkReadResultsetFieldValues(b *testing.B) {
+	const rows = 10
+
+	data := writeTestResultsetRows(b, rows)
+
+	resultFieldSet := newResultFieldSet(3)
+	for i, tc := range []TypeCode{tcInteger, tcVarchar, tcNvarchar} {
+		resultFieldSet.fields[i] = &ResultField{fieldNames: newFieldNames(), tc: tc}
+	}
+
+	bench := func(b *testing.B, get func() *FieldValues) {
+		rd := bufio.NewReader(nil)
+		br := bytes.NewReader(data)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			br.Reset(data)
+			rd.Reset(br)
+			r := &resultset{resultFieldSet: resultFieldSet, fieldValues: get(), numArg: rows}
+			if err := r.read(rd); err != nil {
+				b.Fatal(err)
+			}
+			r.fieldValues.Release()
+		}
+	}
+
+	b.Run("new", func(b *testing.B) { bench(b, newFieldValues) })
+	b.Run("pooled", func(b *testing.B) { bench(b, getFieldValues) })
+}
+
+func TestFieldValuesRelease(t *testing.T) {
+	f := newFieldValues()
+	f.resize(1, 1)
+	f.values[0] = "value"
+	f.Release() // not pooled: no-op
+	if f.values[0] == nil {
+		t.Fatal("value of not pooled field values released")
+	}
+
+	var nilValues *FieldValues
+	nilValues.Release()
+
+	f = getFieldValues()
+	f.resize(2, 2)
+	for i := range f.values {
+		f.values[i] = int64(i)
+	}
+	values := f.values
+	f.Release()
+
+	for i, v := range values[:cap(values)] {
+		if v != nil {
+			t.Fatalf("value %d: %v - expected nil", i, v)
+		}
+	}
+	if f.pooled || f.NumRow() != 0 || f.dec != nil {
+		t.Fatal("released field values not reset")
+	}
+	f.Release() // released field values are not pooled anymore: no-op
+}
+
+func TestReadResultsetNullAsZeroValue(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	wr.WriteBool(false)             // integer null value
+	wr.WriteB(bytesLenIndNullValue) // nvarchar null value
+	wr.WriteUint64(doubleNullValue) // double null value
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []TypeCode{tcInteger, tcNvarchar, tcDouble}
+	resultFieldSet := newResultFieldSet(len(tcs))
+	for i, tc := range tcs {
+		resultFieldSet.fields[i] = &ResultField{fieldNames: newFieldNames(), tc: tc}
+	}
+	r := &resultset{numArg: 1, resultFieldSet: resultFieldSet, fieldValues: newFieldValues(), nullAsZeroValue: true}
+	if err := r.read(bufio.NewReader(buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := make([]driver.Value, len(tcs))
+	r.fieldValues.Row(0, dest)
+	if dest[0] != int64(0) || !bytes.Equal(dest[1].([]byte), []byte{}) || dest[2] != float64(0) {
+		t.Fatalf("values %v - expected zero values", dest)
+	}
+}
+
+func TestReadResultsetTrimChar(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	for i := 0; i < 2; i++ {
+		writeBytes(wr, []byte("ab  "))   // char
+		writeUtf8Bytes(wr, []byte("€ ")) // nchar
+		writeBytes(wr, []byte("ab  "))   // varchar
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []TypeCode{tcChar, tcNchar, tcVarchar}
+	resultFieldSet := newResultFieldSet(len(tcs))
+	for i, tc := range tcs {
+		resultFieldSet.fields[i] = &ResultField{fieldNames: newFieldNames(), tc: tc}
+	}
+
+	rd := bufio.NewReader(buf)
+	for _, trimChar := range []bool{false, true} {
+		r := &resultset{numArg: 1, resultFieldSet: resultFieldSet, fieldValues: newFieldValues(), trimChar: trimChar}
+		if err := r.read(rd); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"ab  ", "€ ", "ab  "}
+		if trimChar {
+			expected = []string{"ab", "€", "ab  "}
+		}
+
+		dest := make([]driver.Value, len(tcs))
+		r.fieldValues.Row(0, dest)
+		for i, v := range dest {
+			if string(v.([]byte)) != expected[i] {
+				t.Fatalf("trim %t: value %d %q - expected %q", trimChar, i, v, expected[i])
+			}
+		}
+	}
+}
+
+func TestReadResultsetCharEncoding(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	for i := 0; i < 2; i++ {
+		writeBytes(wr, []byte{'a', 0xe4}) // varchar (windows-1252 'aä')
+		writeUtf8Bytes(wr, []byte("aä"))  // nvarchar
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []TypeCode{tcVarchar, tcNvarchar}
+	resultFieldSet := newResultFieldSet(len(tcs))
+	for i, tc := range tcs {
+		resultFieldSet.fields[i] = &ResultField{fieldNames: newFieldNames(), tc: tc}
+	}
+
+	rd := bufio.NewReader(buf)
+	for _, enc := range []encoding.Encoding{nil, charmap.Windows1252} {
+		r := &resultset{numArg: 1, resultFieldSet: resultFieldSet, fieldValues: newFieldValues(), charEncoding: enc}
+		if err := r.read(rd); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"a\xe4", "aä"}
+		if enc != nil {
+			expected = []string{"aä", "aä"}
+		}
+
+		dest := make([]driver.Value, len(tcs))
+		r.fieldValues.Row(0, dest)
+		for i, v := range dest {
+			if string(v.([]byte)) != expected[i] {
+				t.Fatalf("encoding %v: value %d %q - expected %q", enc, i, v, expected[i])
+			}
+		}
+	}
+}
+
+func TestReadArray(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	// integer array (1, null, 3)
+	wr.WriteInt32(3)
+	wr.WriteB(byte(tcInteger))
+	for _, v := range []int32{1, 0, 3} {
+		wr.WriteBool(v != 0) // null indicator
+		if v != 0 {
+			wr.WriteInt32(v)
+		}
+	}
+	// nvarchar array ('a')
+	wr.WriteInt32(1)
+	wr.WriteB(byte(tcNvarchar))
+	writeUtf8Bytes(wr, []byte("a"))
+	// decimal array (decimal, null)
+	dec := []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x40, 0x30}
+	wr.WriteInt32(2)
+	wr.WriteB(byte(tcDecimal))
+	wr.Write(dec)
+	wr.Write([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x70})
+	// null array
+	wr.WriteInt32(-1)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd := bufio.NewReader(buf)
+	f := newFieldValues()
+
+	expected := []interface{}{
+		[]interface{}{int64(1), nil, int64(3)},
+		[]interface{}{[]byte("a")},
+		[]interface{}{ArrayDecimal(dec), nil},
+		nil,
+	}
+	values := make([]interface{}, len(expected))
+	for i := range expected {
+		var err error
+		if values[i], err = f.readField(nil, rd, tcArray); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// reuse field values buffer: element values must not be affected
+	f.resize(0, 0)
+	b := f.alloc(minFieldValuesBufferSize)
+	for i := range b {
+		b[i] = 0xff
+	}
+
+	for i, e := range expected {
+		if !reflect.DeepEqual(values[i], e) {
+			t.Fatalf("array %d: value %v - expected %v", i, values[i], e)
+		}
+	}
+}
+
+func TestResultFieldNullable(t *testing.T) {
+	for _, d := range []struct {
+		options  columnOptions
+		nullable bool
+	}{
+		{coMandatory, false},
+		{coOptional, true},
+		{coOptional | 0x10, true}, // further option bits
+		{coMandatory | 0x10, false},
+	} {
+		f := &ResultField{columnOptions: d.options}
+		if f.Nullable() != d.nullable {
+			t.Fatalf("column options %x: nullable %t - expected %t", int8(d.options), f.Nullable(), d.nullable)
+		}
+	}
+}
+
+func TestReadRowid(t *testing.T) {
+	if tcRowid.DataType() != DtBytes {
+		t.Fatalf("data type %s - expected %s", tcRowid.DataType(), DtBytes)
+	}
+	if tcRowid.TypeName() != "ROWID" {
+		t.Fatalf("type name %s - expected %s", tcRowid.TypeName(), "ROWID")
+	}
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	// short and medium length indicator
+	expected := [][]byte{bytes.Repeat([]byte{0x01}, 8), bytes.Repeat([]byte{0x02}, 300)}
+	for _, b := range expected {
+		writeBytes(wr, b)
+	}
+	wr.WriteB(bytesLenIndNullValue)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd := bufio.NewReader(buf)
+	f := newFieldValues()
+
+	for i, e := range expected {
+		v, err := f.readField(nil, rd, tcRowid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(v.([]byte), e) {
+			t.Fatalf("rowid %d: value %x - expected %x", i, v, e)
+		}
+	}
+	if v, _ := f.readField(nil, rd, tcRowid); v != nil {
+		t.Fatalf("rowid: value %x - expected nil", v)
+	}
+}
+
+func TestReadAbapBinary(t *testing.T) {
+	for tc, typeName := range map[TypeCode]string{tcBstring: "BSTRING", tcVarbinary3: "VARBINARY3"} {
+		if tc.DataType() != DtBytes {
+			t.Fatalf("%s: data type %s - expected %s", tc, tc.DataType(), DtBytes)
+		}
+		if tc.TypeName() != typeName {
+			t.Fatalf("type name %s - expected %s", tc.TypeName(), typeName)
+		}
+
+		in := bytes.Repeat([]byte{0xab}, 300)
+
+		// parameter value: type code and bytes
+		prmBuf := new(bytes.Buffer)
+		prmWr := bufio.NewWriter(prmBuf)
+		if err := writeField(prmWr, tc, driver.NamedValue{Value: in}); err != nil {
+			t.Fatal(err)
+		}
+		if err := prmWr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		size, err := fieldSize(tc, driver.NamedValue{Value: in})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size != prmBuf.Len()-1 {
+			t.Fatalf("%s: field size %d - expected %d", tc, size, prmBuf.Len()-1)
+		}
+
+		// resultset values
+		buf := new(bytes.Buffer)
+		wr := bufio.NewWriter(buf)
+		buf.Write(prmBuf.Bytes()[1:])
+		wr.WriteB(bytesLenIndNullValue)
+		if err := wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		rd := bufio.NewReader(buf)
+		f := newFieldValues()
+
+		v, err := f.readField(nil, rd, tc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(v.([]byte), in) {
+			t.Fatalf("%s: value %x - expected %x", tc, v, in)
+		}
+		if v, _ := f.readField(nil, rd, tc); v != nil {
+			t.Fatalf("%s: value %x - expected nil", tc, v)
+		}
+	}
+}
+
+func TestBooleanField(t *testing.T) {
+	if tcBoolean.DataType() != DtBoolean {
+		t.Fatalf("data type %s - expected %s", tcBoolean.DataType(), DtBoolean)
+	}
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	wr.WriteB(booleanTrueValue)
+	wr.WriteB(booleanFalseValue)
+	wr.WriteB(booleanNullValue)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd := bufio.NewReader(buf)
+	f := newFieldValues()
+
+	for _, e := range []interface{}{true, false, nil} { // unknown: NULL
+		v, err := f.readField(nil, rd, tcBoolean)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != e {
+			t.Fatalf("value %v - expected %v", v, e)
+		}
+	}
+
+	// parameter values
+	for _, d := range []struct {
+		v interface{}
+		b []byte
+	}{
+		{true, []byte{byte(tcBoolean), booleanTrueValue}},
+		{false, []byte{byte(tcBoolean), booleanFalseValue}},
+		{nil, []byte{byte(tcBoolean) | 0x80}},
+	} {
+		buf.Reset()
+		if err := writeField(wr, tcBoolean, driver.NamedValue{Value: d.v}); err != nil {
+			t.Fatal(err)
+		}
+		if err := wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), d.b) {
+			t.Fatalf("value %v: bytes %x - expected %x", d.v, buf.Bytes(), d.b)
+		}
+	}
+}
+
+func TestFixedField(t *testing.T) {
+	max38, _ := new(big.Int).SetString("99999999999999999999999999999999999999", 10) // 38 digits
+
+	for _, d := range []struct {
+		tc TypeCode
+		v  string
+		b  []byte
+	}{
+		{tcFixed8, "1", []byte{1, 0, 0, 0, 0, 0, 0, 0}},
+		{tcFixed8, "-1", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{tcFixed8, "-256", []byte{0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{tcFixed8, "9223372036854775807", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}},
+		{tcFixed8, "-9223372036854775808", []byte{0, 0, 0, 0, 0, 0, 0, 0x80}},
+		{tcFixed12, "-2", []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{tcFixed16, max38.String(), []byte{0xff, 0xff, 0xff, 0xff, 0x3f, 0x22, 0x8a, 0x09, 0x7a, 0xc4, 0x86, 0x5a, 0xa8, 0x4c, 0x3b, 0x4b}},
+		{tcFixed16, new(big.Int).Neg(max38).String(), []byte{0x01, 0, 0, 0, 0xc0, 0xdd, 0x75, 0xf6, 0x85, 0x3b, 0x79, 0xa5, 0x57, 0xb3, 0xc4, 0xb4}},
+	} {
+		x, _ := new(big.Int).SetString(d.v, 10)
+
+		buf := new(bytes.Buffer)
+		wr := bufio.NewWriter(buf)
+		if err := writeField(wr, d.tc, driver.NamedValue{Value: (*Fixed)(x)}); err != nil {
+			t.Fatal(err)
+		}
+		if err := wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if b := buf.Bytes(); b[0] != byte(d.tc) || !bytes.Equal(b[1:], d.b) {
+			t.Fatalf("%s %s: bytes %x - expected %x", d.tc, d.v, b[1:], d.b)
+		}
+
+		// result field: null indicator and value
+		buf.Reset()
+		wr.WriteB(1)
+		wr.Write(d.b)
+		wr.WriteB(0)
+		if err := wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		rd := bufio.NewReader(buf)
+		f := newFieldValues()
+		v, err := f.readField(nil, rd, d.tc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r, ok := v.(*Fixed); !ok || (*big.Int)(r).Cmp(x) != 0 {
+			t.Fatalf("%s %s: value %v - expected %s", d.tc, d.v, v, x)
+		}
+		if v, _ := f.readField(nil, rd, d.tc); v != nil {
+			t.Fatalf("%s: value %v - expected nil", d.tc, v)
+		}
+	}
+
+	x := new(big.Int).Lsh(big.NewInt(1), 63) // exceeds FIXED8
+	wr := bufio.NewWriter(new(bytes.Buffer))
+	if err := writeField(wr, tcFixed8, driver.NamedValue{Value: (*Fixed)(x)}); err == nil {
+		t.Fatalf("value %s: field size error expected", x)
+	}
+
+	if dt := tcFixed16.DataType(); dt != DtDecimal {
+		t.Fatalf("data type %s - expected %s", dt, DtDecimal)
+	}
+	if name := tcFixed12.TypeName(); name != "DECIMAL" {
+		t.Fatalf("type name %s - expected %s", name, "DECIMAL")
+	}
+}
+
+func TestReadAstralString(t *testing.T) {
+	const s = "𝕳𝖆𝖓𝖆"
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	writeUtf8String(wr, s) // varchar returned as CESU-8
+	writeUtf8String(wr, s) // nvarchar
+	wr.WriteB(byte(cesu8.StringSize(s)))
+	wr.WriteStringCesu8(s) // short string (field names)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd := bufio.NewReader(buf)
+	f := newFieldValues()
+
+	for _, tc := range []TypeCode{tcVarchar, tcNvarchar} {
+		v, err := f.readField(nil, rd, tc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(v.([]byte)) != s {
+			t.Fatalf("%s: value %q - expected %q", tc, v, s)
+		}
+	}
+	if b, _ := readShortUtf8(rd); string(b) != s {
+		t.Fatalf("short string %q - expected %q", b, s)
+	}
+}
+
+func TestReadResultsetFieldError(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	wr.WriteBool(true)
+	wr.WriteInt32(1)
+	writeBytes(wr, []byte{0xff}) // invalid CESU-8
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	names := newFieldNames()
+	names.setName(1, "ID")
+	names.setName(2, "NAME")
+	resultFieldSet := newResultFieldSet(2)
+	resultFieldSet.fields[0] = &ResultField{fieldNames: names, tc: tcInteger}
+	resultFieldSet.fields[1] = &ResultField{fieldNames: names, tc: tcNvarchar}
+	resultFieldSet.fields[0].offsets[columnDisplayName] = 1
+	resultFieldSet.fields[1].offsets[columnDisplayName] = 2
+
+	r := &resultset{numArg: 1, resultFieldSet: resultFieldSet, fieldValues: newFieldValues()}
+	err := r.read(bufio.NewReader(buf))
+	fieldErr, ok := err.(*FieldError)
+	if !ok {
+		t.Fatalf("error %v - expected field error", err)
+	}
+	if fieldErr.FieldIndex() != 1 || fieldErr.FieldName() != "NAME" {
+		t.Fatalf("field %d %s - expected %d %s", fieldErr.FieldIndex(), fieldErr.FieldName(), 1, "NAME")
+	}
+	if fieldErr.Unwrap() == nil {
+		t.Fatal("wrapped error expected")
+	}
+}
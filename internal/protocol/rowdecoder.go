@@ -0,0 +1,184 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+// A RowDecoder decodes resultset rows of a known (static) schema without type code dispatching.
+type RowDecoder interface {
+	// TypeCodes returns the expected type codes of the resultset fields.
+	TypeCodes() []TypeCode
+	// DecodeRow reads the field values of one row from rd into dest.
+	DecodeRow(rd *FieldReader, dest []driver.Value) error
+}
+
+// checkRowDecoder checks if the type codes of the row decoder match the resultset fields.
+func checkRowDecoder(d RowDecoder, f *ResultFieldSet) error {
+	tcs := d.TypeCodes()
+	if len(tcs) != len(f.fields) {
+		return fmt.Errorf("row decoder: number of fields %d - expected %d", len(tcs), len(f.fields))
+	}
+	for i, field := range f.fields {
+		if tcs[i] != field.TypeCode() {
+			return fmt.Errorf("row decoder: field %d type code %s - expected %s", i, tcs[i], field.TypeCode())
+		}
+	}
+	return nil
+}
+
+// A FieldReader reads resultset field values in wire format. Each method reads the next field,
+// which must be of the type code the method is named after. The bool result is true for null values.
+// Variable length values are only valid until the next fetch of resultset rows.
+type FieldReader struct {
+	s  *Session
+	rd *bufio.Reader
+	fv *FieldValues
+}
+
+// Tinyint reads a TINYINT field value.
+func (r *FieldReader) Tinyint() (uint8, bool) {
+	if !r.rd.ReadBool() {
+		return 0, true
+	}
+	return r.rd.ReadB(), false
+}
+
+// Smallint reads a SMALLINT field value.
+func (r *FieldReader) Smallint() (int16, bool) {
+	if !r.rd.ReadBool() {
+		return 0, true
+	}
+	return r.rd.ReadInt16(), false
+}
+
+// Integer reads an INTEGER field value.
+func (r *FieldReader) Integer() (int32, bool) {
+	if !r.rd.ReadBool() {
+		return 0, true
+	}
+	return r.rd.ReadInt32(), false
+}
+
+// Bigint reads a BIGINT field value.
+func (r *FieldReader) Bigint() (int64, bool) {
+	if !r.rd.ReadBool() {
+		return 0, true
+	}
+	return r.rd.ReadInt64(), false
+}
+
+// Real reads a REAL field value.
+func (r *FieldReader) Real() (float32, bool) {
+	v := r.rd.ReadUint32()
+	if v == realNullValue {
+		return 0, true
+	}
+	return math.Float32frombits(v), false
+}
+
+// Double reads a DOUBLE field value.
+func (r *FieldReader) Double() (float64, bool) {
+	v := r.rd.ReadUint64()
+	if v == doubleNullValue {
+		return 0, true
+	}
+	return math.Float64frombits(v), false
+}
+
+// Decimal reads a DECIMAL field value in 128 bit decimal floating point format.
+func (r *FieldReader) Decimal() ([]byte, bool) {
+	b, null := readDecimal(r.rd, r.fv.alloc(decimalFieldSize))
+	if null {
+		return nil, true
+	}
+	return b, false
+}
+
+// Date reads a DATE field value.
+func (r *FieldReader) Date() (time.Time, bool) {
+	year, month, day, null := readDate(r.rd)
+	if null {
+		return time.Time{}, true
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), false
+}
+
+// Time reads a TIME field value.
+func (r *FieldReader) Time() (time.Time, bool) {
+	hour, minute, nanosecs, null := readTime(r.rd)
+	if null {
+		return time.Time{}, true
+	}
+	return time.Date(1, 1, 1, hour, minute, 0, nanosecs, time.UTC), false
+}
+
+// Timestamp reads a TIMESTAMP field value.
+func (r *FieldReader) Timestamp() (time.Time, bool) {
+	year, month, day, dateNull := readDate(r.rd)
+	hour, minute, nanosecs, timeNull := readTime(r.rd)
+	if dateNull || timeNull {
+		return time.Time{}, true
+	}
+	return time.Date(year, month, day, hour, minute, 0, nanosecs, time.UTC), false
+}
+
+// Longdate reads a LONGDATE field value.
+func (r *FieldReader) Longdate() (time.Time, bool) {
+	return readLongdate(r.rd)
+}
+
+// Seconddate reads a SECONDDATE field value.
+func (r *FieldReader) Seconddate() (time.Time, bool) {
+	return readSeconddate(r.rd)
+}
+
+// Daydate reads a DAYDATE field value.
+func (r *FieldReader) Daydate() (time.Time, bool) {
+	return readDaydate(r.rd)
+}
+
+// Secondtime reads a SECONDTIME field value.
+func (r *FieldReader) Secondtime() (time.Time, bool) {
+	return readSecondtime(r.rd)
+}
+
+// Chars reads a CHAR or VARCHAR field value.
+func (r *FieldReader) Chars() ([]byte, bool) {
+	return readCharBytes(r.rd, r.fv.alloc)
+}
+
+// NChars reads a NCHAR or NVARCHAR field value as UTF-8 encoded bytes.
+func (r *FieldReader) NChars() ([]byte, bool) {
+	return readUtf8(r.rd, r.fv.alloc)
+}
+
+// Binary reads a BINARY or VARBINARY field value.
+func (r *FieldReader) Binary() ([]byte, bool) {
+	return readBytes(r.rd, r.fv.alloc)
+}
+
+// Value reads a field value of type code tc like the default decoding (e.g. for lob fields).
+func (r *FieldReader) Value(tc TypeCode) (driver.Value, error) {
+	return r.fv.readField(r.s, r.rd, tc)
+}
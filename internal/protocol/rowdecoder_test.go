@@ -0,0 +1,83 @@
+/*
+Copyright 2014 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+type testRowDecoder struct{}
+
+func (d testRowDecoder) TypeCodes() []TypeCode { return []TypeCode{tcInteger, tcNvarchar} }
+
+func (d testRowDecoder) DecodeRow(rd *FieldReader, dest []driver.Value) error {
+	if i, null := rd.Integer(); !null {
+		dest[0] = i * 10 // distinguish from default decoding
+	}
+	if b, null := rd.NChars(); !null {
+		dest[1] = string(b)
+	}
+	return nil
+}
+
+func TestReadResultsetRowDecoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	wr.WriteBool(true)
+	wr.WriteInt32(4)
+	writeUtf8Bytes(wr, []byte("€"))
+	wr.WriteBool(false) // null
+	wr.WriteB(bytesLenIndNullValue)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	newFieldSet := func(tcs ...TypeCode) *ResultFieldSet {
+		resultFieldSet := newResultFieldSet(len(tcs))
+		for i, tc := range tcs {
+			resultFieldSet.fields[i] = &ResultField{fieldNames: newFieldNames(), tc: tc}
+		}
+		return resultFieldSet
+	}
+
+	fieldValues := newFieldValues()
+	fieldValues.dec = testRowDecoder{}
+	r := &resultset{numArg: 2, resultFieldSet: newFieldSet(tcInteger, tcNvarchar), fieldValues: fieldValues}
+	if err := r.read(bufio.NewReader(buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := make([]driver.Value, 2)
+	r.fieldValues.Row(0, dest)
+	if dest[0] != int32(40) || dest[1] != "€" {
+		t.Fatalf("row 0 %v - expected %v", dest, []driver.Value{int32(40), "€"})
+	}
+	r.fieldValues.Row(1, dest)
+	if dest[0] != nil || dest[1] != nil {
+		t.Fatalf("row 1 %v - expected null values", dest)
+	}
+
+	// type code mismatch
+	r = &resultset{numArg: 1, resultFieldSet: newFieldSet(tcBigint, tcNvarchar), fieldValues: fieldValues}
+	if err := r.read(bufio.NewReader(new(bytes.Buffer))); err == nil {
+		t.Fatal("row decoder type code error expected")
+	}
+}
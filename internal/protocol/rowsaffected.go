@@ -57,6 +57,11 @@ func (r *rowsAffected) read(rd *bufio.Reader) error {
 	return rd.GetError()
 }
 
+// reset removes the rows affected of a previous reply (replies without rows affected part).
+func (r *rowsAffected) reset() {
+	r.rows = r.rows[:0]
+}
+
 func (r *rowsAffected) total() int64 {
 	if r.rows == nil {
 		return 0
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+func TestReadReplyResetRowsAffected(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	// ddl reply without rows affected part
+	mh := &messageHeader{varPartLength: segmentHeaderSize, varPartSize: segmentHeaderSize, noOfSegm: 1}
+	mh.write(wr)
+	sh := &segmentHeader{segmentLength: segmentHeaderSize, segmentNo: 1, segmentKind: skReply, functionCode: fcDDL}
+	sh.write(wr)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Session{
+		rd:           bufio.NewReader(buf),
+		mh:           new(messageHeader),
+		sh:           new(segmentHeader),
+		ph:           new(partHeader),
+		resultset:    new(resultset),
+		rowsAffected: &rowsAffected{rows: []int32{5}}, // rows affected of previous reply
+		stmtCtx:      newStatementContext(),
+		lastError:    new(hdbErrors),
+	}
+
+	if err := s.readReply(nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.rowsAffected.total() != 0 {
+		t.Fatalf("rows affected %d - expected %d", s.rowsAffected.total(), 0)
+	}
+}
@@ -17,18 +17,23 @@ limitations under the License.
 package protocol
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"database/sql/driver"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"math"
 	"net"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/text/encoding"
+
 	"github.com/SAP/go-hdb/internal/bufio"
 	"github.com/SAP/go-hdb/internal/unicode"
 	"github.com/SAP/go-hdb/internal/unicode/cesu8"
@@ -73,18 +78,32 @@ type sessionConn struct {
 	isBad    bool  // bad connection
 	badError error // error cause for session bad state
 	inTx     bool  // in transaction
+	txAbort  error // cause of the rollback of the transaction by the driver (see abortWriteLob)
+}
+
+// UnixSocketPrefix is the address prefix of database connections via Unix domain socket (unix:///path/to/socket).
+const UnixSocketPrefix = "unix://"
+
+// dialAddr returns the network and the address to dial for a host address.
+func dialAddr(addr string) (string, string) {
+	if strings.HasPrefix(addr, UnixSocketPrefix) {
+		return "unix", addr[len(UnixSocketPrefix):]
+	}
+	return "tcp", addr
 }
 
 func newSessionConn(ctx context.Context, addr string, timeoutSec int, config *tls.Config) (*sessionConn, error) {
 	timeout := time.Duration(timeoutSec) * time.Second
 	dialer := net.Dialer{Timeout: timeout}
-	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	network, address := dialAddr(addr)
+	conn, err := dialer.DialContext(ctx, network, address)
 	if err != nil {
 		return nil, err
 	}
 
 	// is TLS connection requested?
-	if config != nil {
+	// the local Unix domain socket transport does not use TLS
+	if config != nil && network == "tcp" {
 		conn = tls.Client(conn, config)
 	}
 
@@ -135,8 +154,25 @@ type sessionPrm interface {
 	Username() string
 	Password() string
 	Locale() string
+	DatabaseName() string
+	MaxRedirects() int
+	BufferSize() int
+	WriteBufferSize() int
 	FetchSize() int
+	PacketSize() int
+	AutoCloseResultset() bool
+	NullAsZeroValue() bool
+	TrimChar() bool
+	EmptyStringAsNull() bool
+	DropStatementsImmediately() bool
+	StatementRouting() bool
+	ValidateUTF8() bool
+	CharEncoding() encoding.Encoding
+	ClientInfo() map[string]string
+	AsyncCommit() bool
 	Timeout() int
+	ConnectTimeout() int
+	DataFormatVersion() int
 	TLSConfig() *tls.Config
 }
 
@@ -159,6 +195,8 @@ type Session struct {
 	scramsha256FinalRequest   *scramsha256FinalRequest
 	scramsha256FinalReply     *scramsha256FinalReply
 	topologyInformation       *topologyInformation
+	dbConnectInfo             *dbConnectInfo
+	tableLocation             *tableLocation
 	connectOptions            *connectOptions
 	rowsAffected              *rowsAffected
 	statementID               *statementID
@@ -171,6 +209,31 @@ type Session struct {
 	readLobRequest            *readLobRequest
 	writeLobReply             *writeLobReply
 	readLobReply              *readLobReply
+	reply                     *replyState // reused by readReply
+
+	// statement handles to be released (see DropStatementID)
+	dropStatementIDs []uint64
+	// number of prepared statements not dropped yet
+	numStatement int
+	// client information sent with statement execution requests
+	clientInfo *clientInfo
+	// client information changes are not sent with the subsequent requests of a split execute request (see Exec)
+	skipClientInfo bool
+	// row decoder, cursor holdability and scrollability of the next query (see SetRowDecoder, SetHoldCursor, SetScrollableCursor)
+	rowDecoder       RowDecoder
+	holdCursor       bool
+	scrollableCursor bool
+	// statement sequence info returned by the last reply and sent with the next statement execution
+	// (see StatementSequenceInfo, SetStatementSequenceInfo)
+	seqInfo, nextSeqInfo []byte
+	// cancellation of the lob parameter upload of the next statement execution (see SetWriteLobDone)
+	writeLobDone <-chan struct{}
+	// location of time-zone-naive database date and time values (see SetTimeLocation)
+	timeLocation *time.Location
+	// prepared statement metadata shared with other sessions (see SetMetadataCache)
+	metadataCache *MetadataCache
+	// asynchronous commit: reply of last commit not read yet (see Commit)
+	commitReplyPending bool
 
 	//standard replies
 	stmtCtx   *statementContext
@@ -189,13 +252,41 @@ func NewSession(ctx context.Context, prm sessionPrm) (*Session, error) {
 		outLogger.Printf("%s", prm)
 	}
 
-	conn, err := newSessionConn(ctx, prm.Host(), prm.Timeout(), prm.TLSConfig())
+	// connect timeout: dial, TLS handshake and authentication
+	connectTimeout := prm.ConnectTimeout()
+	if connectTimeout == 0 {
+		connectTimeout = prm.Timeout()
+	}
+
+	host := prm.Host()
+	for numRedirect := 0; ; numRedirect++ {
+		s, redirectHost, err := newSession(ctx, prm, host, connectTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if redirectHost == "" {
+			return s, nil
+		}
+		if numRedirect >= prm.MaxRedirects() {
+			return nil, fmt.Errorf("connect %s: maximum number of redirects %d exceeded", prm.Host(), prm.MaxRedirects())
+		}
+		if trace {
+			outLogger.Printf("redirect from %s to %s", host, redirectHost)
+		}
+		host = redirectHost
+	}
+}
+
+// newSession connects to host. In case the database server requests a redirect to a different host
+// newSession closes the connection and returns the host to redirect to instead of a session.
+func newSession(ctx context.Context, prm sessionPrm, host string, connectTimeout int) (*Session, string, error) {
+	conn, err := newSessionConn(ctx, host, connectTimeout, prm.TLSConfig())
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	rd := bufio.NewReader(conn)
-	wr := bufio.NewWriter(conn)
+	rd := bufio.NewReaderSize(conn, prm.BufferSize())
+	wr := bufio.NewWriterSize(conn, prm.WriteBufferSize())
 
 	s := &Session{
 		prm:  prm,
@@ -210,12 +301,13 @@ func NewSession(ctx context.Context, prm sessionPrm) (*Session, error) {
 		scramsha256FinalRequest:   new(scramsha256FinalRequest),
 		scramsha256FinalReply:     new(scramsha256FinalReply),
 		topologyInformation:       newTopologyInformation(),
+		tableLocation:             new(tableLocation),
 		connectOptions:            newConnectOptions(),
 		rowsAffected:              new(rowsAffected),
 		statementID:               new(statementID),
 		resultMetadata:            new(resultMetadata),
 		resultsetID:               new(resultsetID),
-		resultset:                 new(resultset),
+		resultset:                 &resultset{nullAsZeroValue: prm.NullAsZeroValue(), trimChar: prm.TrimChar(), charEncoding: prm.CharEncoding()},
 		parameterMetadata:         new(parameterMetadata),
 		outputParameters:          new(outputParameters),
 		writeLobRequest:           new(writeLobRequest),
@@ -223,15 +315,19 @@ func NewSession(ctx context.Context, prm sessionPrm) (*Session, error) {
 		writeLobReply:             new(writeLobReply),
 		readLobReply:              new(readLobReply),
 		stmtCtx:                   newStatementContext(),
+		clientInfo:                newClientInfo(prm.ClientInfo()),
 		txFlags:                   newTransactionFlags(),
 		lastError:                 new(hdbErrors),
 	}
 
-	if err = s.init(); err != nil {
-		return nil, err
+	redirectHost, err := s.init()
+	if err != nil || redirectHost != "" {
+		conn.close()
+		return nil, redirectHost, err
 	}
 
-	return s, nil
+	conn.timeout = time.Duration(prm.Timeout()) * time.Second
+	return s, "", nil
 }
 
 // Close closes the session.
@@ -251,6 +347,7 @@ func (s *Session) InTx() bool {
 // SetInTx sets session in transaction mode.
 func (s *Session) SetInTx(v bool) {
 	s.conn.inTx = v
+	s.conn.txAbort = nil
 }
 
 // IsBad indicates, that the session is in bad state.
@@ -263,10 +360,139 @@ func (s *Session) BadErr() error {
 	return s.conn.badError
 }
 
-func (s *Session) init() error {
+// ConnectionID returns the database connection id of the session.
+func (s *Session) ConnectionID() int {
+	if v, ok := s.connectOptions.get(coConnectionID); ok {
+		if id, ok := v.(intType); ok {
+			return int(id)
+		}
+	}
+	return 0
+}
+
+// ServerVersion returns the full database server version, if provided by the server.
+func (s *Session) ServerVersion() string {
+	if v, ok := s.connectOptions.get(coFullVersionString); ok {
+		if version, ok := v.(stringType); ok {
+			return string(version)
+		}
+	}
+	return ""
+}
+
+// StatementContext returns the server execution information of the last statement.
+func (s *Session) StatementContext() StatementContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stmtCtx.statementContext()
+}
+
+// RowsAffected returns the number of rows affected by the last statement.
+func (s *Session) RowsAffected() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rowsAffected.total()
+}
+
+// SetClientInfo sets client information values sent to the database server with the next statement execution
+// request in addition to the session values. The values are reset by the following statement.
+func (s *Session) SetClientInfo(values map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clientInfo.setStmt(values)
+}
+
+// SetRowDecoder sets the row decoder of the resultset returned by the next query execution.
+// The decoder is reset by the query execution.
+func (s *Session) SetRowDecoder(dec RowDecoder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rowDecoder = dec
+}
+
+// SetHoldCursor requests the resultset of the next query execution to be kept open over commits
+// (cursor holdability). The option is reset by the query execution.
+func (s *Session) SetHoldCursor(holdCursor bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.holdCursor = holdCursor
+}
+
+// SetScrollableCursor requests a scrollable cursor for the resultset of the next query execution,
+// so that the resultset can be fetched backwards (see FetchLast, FetchRelative). The option is reset by the query execution.
+func (s *Session) SetScrollableCursor(scrollableCursor bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scrollableCursor = scrollableCursor
+}
+
+// StatementSequenceInfo returns the last statement sequence info returned by the database server, identifying
+// the snapshot (commit sequence) of the last statement. The result is nil if no sequence info was returned.
+func (s *Session) StatementSequenceInfo() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seqInfo
+}
+
+// SetStatementSequenceInfo sets the statement sequence info sent with the next statement execution, requesting
+// the database server to execute the statement at least on the snapshot identified by seqInfo.
+func (s *Session) SetStatementSequenceInfo(seqInfo []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextSeqInfo = seqInfo
+}
+
+// SetWriteLobDone sets the channel canceling the lob parameter upload of the next statement execution
+// when closed (e.g. context.Done). The channel is reset by the statement execution.
+func (s *Session) SetWriteLobDone(done <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeLobDone = done
+}
+
+// TimeLocation returns the location of the time-zone-naive database date and time values or nil, if the values are UTC values.
+func (s *Session) TimeLocation() *time.Location {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.timeLocation
+}
+
+// SetTimeLocation sets the location the time-zone-naive database date and time values are interpreted in
+// (nil: UTC, default). The location is applied by the driver converting query results and parameters.
+func (s *Session) SetTimeLocation(loc *time.Location) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeLocation = loc
+}
+
+// SetMetadataCache sets the cache of prepared statement metadata used by Prepare (nil: no caching).
+func (s *Session) SetMetadataCache(c *MetadataCache) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metadataCache = c
+}
+
+// Topology returns the hosts of the database system provided by the database server.
+func (s *Session) Topology() []TopologyHost {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.topologyInformation.hosts()
+}
+
+// init initializes and authenticates the session. If the session is not connected to the requested
+// database init returns the host of the database instead.
+func (s *Session) init() (string, error) {
 
 	if err := s.initRequest(); err != nil {
-		return err
+		return "", err
+	}
+
+	if databaseName := s.prm.DatabaseName(); databaseName != "" {
+		redirectHost, err := s.dbConnectRequest(databaseName)
+		if err != nil || redirectHost != "" {
+			return redirectHost, err
+		}
 	}
 
 	// TODO: detect authentication method
@@ -276,11 +502,11 @@ func (s *Session) init() error {
 
 	switch authentication {
 	default:
-		return fmt.Errorf("invalid authentication %s", authentication)
+		return "", fmt.Errorf("invalid authentication %s", authentication)
 
 	case mnSCRAMSHA256:
 		if err := s.authenticateScramsha256(); err != nil {
-			return err
+			return "", err
 		}
 	case mnGSS:
 		panic("not implemented error")
@@ -290,14 +516,33 @@ func (s *Session) init() error {
 
 	id := s.sessionID()
 	if id <= 0 {
-		return fmt.Errorf("invalid session id %d", id)
+		return "", fmt.Errorf("invalid session id %d", id)
 	}
 
 	if trace {
 		outLogger.Printf("sessionId %d", id)
 	}
 
-	return nil
+	return "", nil
+}
+
+// dbConnectRequest requests the connect information of database databaseName. It returns the host
+// of the database, if the session is not connected to the database.
+func (s *Session) dbConnectRequest(databaseName string) (string, error) {
+	s.dbConnectInfo = newDBConnectInfo(databaseName)
+
+	if err := s.writeRequest(mtDBConnectInfo, false, s.dbConnectInfo); err != nil {
+		return "", err
+	}
+
+	if err := s.readReply(nil); err != nil {
+		return "", err
+	}
+
+	if s.dbConnectInfo.isConnected() {
+		return "", nil
+	}
+	return s.dbConnectInfo.redirectHost()
 }
 
 func (s *Session) authenticateScramsha256() error {
@@ -340,14 +585,12 @@ func (s *Session) authenticateScramsha256() error {
 	co.set(coDistributionProtocolVersion, booleanType(false))
 	co.set(coSelectForUpdateSupported, booleanType(false))
 	co.set(coSplitBatchCommands, booleanType(true))
-	// cannot use due to HDB protocol error with secondtime datatype
-	//co.set(coDataFormatVersion2, dfvSPS06)
-	co.set(coDataFormatVersion2, dfvBaseline)
+	co.set(coDataFormatVersion2, intType(s.prm.DataFormatVersion()))
 	co.set(coCompleteArrayExecution, booleanType(true))
 	if s.prm.Locale() != "" {
 		co.set(coClientLocale, stringType(s.prm.Locale()))
 	}
-	co.set(coClientDistributionMode, cdmOff)
+	co.set(coClientDistributionMode, clientDistributionMode(s.prm))
 	// setting this option has no effect
 	//co.set(coImplicitLobStreaming, booleanType(true))
 
@@ -362,18 +605,39 @@ func (s *Session) authenticateScramsha256() error {
 	return nil
 }
 
+// nextQueryOptions returns the row decoder and the command options of the next query execution
+// and resets the query settings (see SetRowDecoder, SetHoldCursor and SetScrollableCursor).
+func (s *Session) nextQueryOptions() (RowDecoder, commandOptions) {
+	dec, options := s.rowDecoder, s.queryOptions()
+	if s.holdCursor {
+		options |= coHoldCursorOverCommtit
+	}
+	if s.scrollableCursor { // resultset needs to be kept open after the last packet
+		options = options&^coNoResultsetCloseNeeded | coScrollableCursorOn
+	}
+	s.rowDecoder, s.holdCursor, s.scrollableCursor = nil, false, false
+	return dec, options
+}
+
 // QueryDirect executes a query without query parameters.
 func (s *Session) QueryDirect(query string) (uint64, *ResultFieldSet, *FieldValues, PartAttributes, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if err := s.writeRequest(mtExecuteDirect, false, command(query)); err != nil {
+	dec, options := s.nextQueryOptions()
+
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return 0, nil, nil, nil, err
+	}
+
+	if err := s.writeRequestOptions(mtExecuteDirect, false, options, command(query)); err != nil {
 		return 0, nil, nil, nil, err
 	}
 
 	var id uint64
 	var resultFieldSet *ResultFieldSet
-	fieldValues := newFieldValues()
+	fieldValues := getFieldValues()
+	fieldValues.dec = dec
 
 	f := func(p replyPart) {
 
@@ -392,10 +656,101 @@ func (s *Session) QueryDirect(query string) (uint64, *ResultFieldSet, *FieldValu
 	}
 
 	if err := s.readReply(f); err != nil {
+		fieldValues.Release()
 		return 0, nil, nil, nil, err
 	}
 
-	return id, resultFieldSet, fieldValues, s.ph.partAttributes, nil
+	return id, resultFieldSet, fieldValues, s.resultset.attrs, nil
+}
+
+// maxQueryBatchPipeline is the maximum number of query requests sent before the replies are read,
+// so that the database server does not block writing replies not read by the client.
+const maxQueryBatchPipeline = 16
+
+// QueryDirectResult is the result of a query executed by QueryDirectBatch.
+type QueryDirectResult struct {
+	ID             uint64 // resultset id (0: no resultset)
+	ResultFieldSet *ResultFieldSet
+	FieldValues    *FieldValues
+	Attrs          PartAttributes
+	Err            error // query error (e.g. database error)
+}
+
+// QueryDirectBatch executes queries without query parameters pipelined: the query requests are sent before
+// the replies are read, so that independent queries are executed without a round trip per query.
+// Errors of single queries are returned in the query results, the returned error is a session error
+// (e.g. network error), in which case no results are returned.
+func (s *Session) QueryDirectBatch(queries []string) ([]QueryDirectResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dec, options := s.nextQueryOptions()
+
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return nil, err
+	}
+	return s.queryDirectBatch(queries, dec, options)
+}
+
+func (s *Session) queryDirectBatch(queries []string, dec RowDecoder, options commandOptions) ([]QueryDirectResult, error) {
+	results := make([]QueryDirectResult, len(queries))
+
+	releaseResults := func() {
+		for i := range results {
+			results[i].FieldValues.Release()
+		}
+	}
+
+	for start := 0; start < len(queries); start += maxQueryBatchPipeline {
+		end := start + maxQueryBatchPipeline
+		if end > len(queries) {
+			end = len(queries)
+		}
+
+		for _, query := range queries[start:end] {
+			if err := s.writeRequestOptions(mtExecuteDirect, false, options, command(query)); err != nil {
+				releaseResults()
+				return nil, err
+			}
+		}
+
+		for i := start; i < end; i++ {
+			r := &results[i]
+			r.FieldValues = getFieldValues()
+			r.FieldValues.dec = dec
+
+			f := func(p replyPart) {
+
+				switch p := p.(type) {
+
+				case *resultsetID:
+					p.id = &r.ID
+				case *resultMetadata:
+					r.ResultFieldSet = newResultFieldSet(p.numArg)
+					p.resultFieldSet = r.ResultFieldSet
+				case *resultset:
+					p.s = s
+					p.resultFieldSet = r.ResultFieldSet
+					p.fieldValues = r.FieldValues
+				}
+			}
+
+			if err := s.readReply(f); err != nil {
+				if s.IsBad() { // replies of the remaining queries cannot be read
+					releaseResults()
+					return nil, err
+				}
+				if hdbErr, ok := err.(*hdbErrors); ok { // session error is reused by subsequent replies
+					err = hdbErr.clone()
+				}
+				r.FieldValues.Release()
+				*r = QueryDirectResult{Err: err}
+				continue
+			}
+			r.Attrs = s.resultset.attrs
+		}
+	}
+	return results, nil
 }
 
 // ExecDirect executes a sql statement without statement parameters.
@@ -403,6 +758,10 @@ func (s *Session) ExecDirect(query string) (driver.Result, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return nil, err
+	}
+
 	if err := s.writeRequest(mtExecuteDirect, !s.conn.inTx, command(query)); err != nil {
 		return nil, err
 	}
@@ -411,9 +770,7 @@ func (s *Session) ExecDirect(query string) (driver.Result, error) {
 		return nil, err
 	}
 
-	if s.sh.functionCode == fcDDL {
-		return driver.ResultNoRows, nil
-	}
+	// ddl: rows affected is 0 (create table as select: number of inserted rows)
 	return driver.RowsAffected(s.rowsAffected.total()), nil
 }
 
@@ -422,6 +779,10 @@ func (s *Session) Prepare(query string) (QueryType, uint64, *ParameterFieldSet,
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return QtNone, 0, nil, nil, err
+	}
+
 	if err := s.writeRequest(mtPrepare, false, command(query)); err != nil {
 		return QtNone, 0, nil, nil, err
 	}
@@ -429,6 +790,13 @@ func (s *Session) Prepare(query string) (QueryType, uint64, *ParameterFieldSet,
 	var id uint64
 	var prmFieldSet *ParameterFieldSet
 	var resultFieldSet *ResultFieldSet
+	var prmNumArg, resultNumArg int
+	var prmData, resultData []byte
+
+	cache := s.metadataCache
+	if cache != nil {
+		defer func() { s.parameterMetadata.b, s.resultMetadata.b = nil, nil }()
+	}
 
 	f := func(p replyPart) {
 
@@ -437,9 +805,19 @@ func (s *Session) Prepare(query string) (QueryType, uint64, *ParameterFieldSet,
 		case *statementID:
 			p.id = &id
 		case *parameterMetadata:
+			if cache != nil {
+				prmNumArg, prmData = p.numArg, make([]byte, s.ph.bufferLength)
+				p.b = prmData
+				return
+			}
 			prmFieldSet = newParameterFieldSet(p.numArg)
 			p.prmFieldSet = prmFieldSet
 		case *resultMetadata:
+			if cache != nil {
+				resultNumArg, resultData = p.numArg, make([]byte, s.ph.bufferLength)
+				p.b = resultData
+				return
+			}
 			resultFieldSet = newResultFieldSet(p.numArg)
 			p.resultFieldSet = resultFieldSet
 		}
@@ -449,51 +827,230 @@ func (s *Session) Prepare(query string) (QueryType, uint64, *ParameterFieldSet,
 		return QtNone, 0, nil, nil, err
 	}
 
+	if cache != nil {
+		var err error
+		if prmFieldSet, resultFieldSet, err = cache.fieldSets(query, prmNumArg, prmData, resultNumArg, resultData); err != nil {
+			return QtNone, 0, nil, nil, err
+		}
+	}
+
+	s.numStatement++
 	return s.sh.functionCode.queryType(), id, prmFieldSet, resultFieldSet, nil
 }
 
 // Exec executes a sql statement.
+// Mass inserts exceeding the maximum packet size are split into multiple execute requests.
+// Outside of a transaction only the last request is committed, so that the statement is executed atomically
+// (in case of an error the requests sent before are rolled back).
 func (s *Session) Exec(id uint64, prmFieldSet *ParameterFieldSet, args []driver.NamedValue) (driver.Result, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.statementID.id = &id
-	if err := s.writeRequest(mtExecute, !s.conn.inTx, s.statementID, newInputParameters(prmFieldSet.inputFields(), args)); err != nil {
+	if err := s.dropPendingStatementIDs(); err != nil {
 		return nil, err
 	}
 
-	if err := s.readReply(nil); err != nil {
+	done := s.writeLobDone
+	s.writeLobDone = nil
+
+	chunks, err := newInputParameters(prmFieldSet.inputFields(), args, s.inputOptions()).split(s.maxParameterSize(), s.maxParameterRows())
+	if err != nil {
 		return nil, err
 	}
 
 	var result driver.Result
-	if s.sh.functionCode == fcDDL {
-		result = driver.ResultNoRows
-	} else {
-		result = driver.RowsAffected(s.rowsAffected.total())
+	var rowsAffected int64
+	rowOfs := 0
+
+	autoCommit := !s.conn.inTx
+	defer func() { s.skipClientInfo = false }()
+
+	// abort rolls back the not committed requests sent before chunk i.
+	abort := func(i int, err error) error {
+		if autoCommit && i > 0 && !s.conn.isBad {
+			s.rollback()
+		}
+		return err
 	}
 
-	if err := s.writeLobStream(prmFieldSet, nil, args); err != nil {
-		return nil, err
+	for i, chunk := range chunks {
+
+		s.skipClientInfo = i > 0 // client information applies to the statement and is sent with the first request only
+
+		s.statementID.id = &id
+		if err := s.writeRequest(mtExecute, autoCommit && i == len(chunks)-1, s.statementID, chunk); err != nil {
+			return nil, abort(i, err)
+		}
+
+		if err := s.readReply(nil); err != nil {
+			if err == s.lastError {
+				s.lastError.addStmtNoOffset(rowOfs)
+			}
+			return nil, abort(i, err)
+		}
+
+		// ddl: rows affected is 0 (create table as select: number of inserted rows)
+		rowsAffected += s.rowsAffected.total()
+		result = driver.RowsAffected(rowsAffected)
+
+		if err := s.writeLobStream(prmFieldSet, nil, chunk.args, rowOfs, done); err != nil {
+			return nil, abort(i, err)
+		}
+
+		rowOfs += chunk.numArg()
 	}
 
 	return result, nil
 }
 
-// DropStatementID releases the hdb statement handle.
-func (s *Session) DropStatementID(id uint64) error {
+// inputOptions returns the options applied to input parameter values.
+func (s *Session) inputOptions() inputOptions {
+	return inputOptions{emptyStringAsNull: s.prm.EmptyStringAsNull(), charEncoding: s.prm.CharEncoding(), validateUTF8: s.prm.ValidateUTF8()}
+}
+
+// maxParameterSize returns the maximum size of the input parameter part of an execute request.
+func (s *Session) maxParameterSize() int {
+	return s.prm.PacketSize() - (messageHeaderSize + segmentHeaderSize + 2*partHeaderSize + statementIDSize + padding)
+}
+
+// clientDistributionMode returns the client distribution mode negotiated on connect.
+// Table locations are only replied by the database server in the statement distribution modes.
+func clientDistributionMode(prm sessionPrm) intType {
+	if prm.StatementRouting() {
+		return cdmStatement
+	}
+	return cdmOff
+}
+
+// StatementRouting prepares the query and returns the hosts owning the tables accessed by the statement
+// (scale-out statement routing). The volume ids of the table locations are mapped to the hosts of the
+// topology information.
+func (s *Session) StatementRouting(query string) ([]TopologyHost, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.statementID.id = &id
-	if err := s.writeRequest(mtDropStatementID, false, s.statementID); err != nil {
+	if !s.prm.StatementRouting() {
+		return nil, fmt.Errorf("statement routing is not enabled")
+	}
+
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return nil, err
+	}
+
+	if err := s.writeRequest(mtPrepare, false, command(query)); err != nil {
+		return nil, err
+	}
+
+	var id uint64
+	s.tableLocation.volumeIDs = nil
+
+	f := func(p replyPart) {
+		switch p := p.(type) {
+		case *statementID:
+			p.id = &id
+		case *parameterMetadata:
+			p.prmFieldSet = newParameterFieldSet(p.numArg)
+		case *resultMetadata:
+			p.resultFieldSet = newResultFieldSet(p.numArg)
+		}
+	}
+
+	if err := s.readReply(f); err != nil {
+		return nil, err
+	}
+
+	s.dropStatementIDs = append(s.dropStatementIDs, id) // statement is not needed anymore
+	return routingHosts(s.topologyInformation.hosts(), s.tableLocation.volumeIDs), nil
+}
+
+// Validate prepares the query without executing it and releases the statement handle afterwards
+// (see DropStatementID), so that the database server checks the syntax and the referenced database objects.
+func (s *Session) Validate(query string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.dropPendingStatementIDs(); err != nil {
 		return err
 	}
 
-	if err := s.readReply(nil); err != nil {
+	if err := s.writeRequest(mtPrepare, false, command(query)); err != nil {
+		return err
+	}
+
+	var id uint64
+
+	f := func(p replyPart) {
+		switch p := p.(type) {
+		case *statementID:
+			p.id = &id
+		case *parameterMetadata:
+			p.prmFieldSet = newParameterFieldSet(p.numArg)
+		case *resultMetadata:
+			p.resultFieldSet = newResultFieldSet(p.numArg)
+		}
+	}
+
+	if err := s.readReply(f); err != nil {
 		return err
 	}
 
+	s.dropStatementIDs = append(s.dropStatementIDs, id) // statement is not needed anymore
+	return nil
+}
+
+// DropStatementID releases the hdb statement handle.
+// To avoid a round trip per statement, the statement handle is not released immediately
+// but before the next commands of the session are executed, unless the session parameter
+// DropStatementsImmediately is set. Statement handles not released when the session is closed
+// are released by the database server on disconnect.
+func (s *Session) DropStatementID(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dropStatementIDs = append(s.dropStatementIDs, id)
+	s.numStatement--
+
+	if s.prm.DropStatementsImmediately() {
+		return s.dropPendingStatementIDs()
+	}
+	return nil
+}
+
+// NumStatement returns the number of prepared statements of the session which are not dropped yet.
+func (s *Session) NumStatement() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.numStatement
+}
+
+// maxDropStatementIDs is the maximum number of statement handles released before a command.
+const maxDropStatementIDs = 8
+
+// dropPendingStatementIDs releases the statement handles of statements closed before.
+// As each handle is released by a round trip of its own, at most maxDropStatementIDs handles
+// are released per call. The remaining handles stay pending for the next commands.
+func (s *Session) dropPendingStatementIDs() error {
+	n := len(s.dropStatementIDs)
+	if n > maxDropStatementIDs {
+		n = maxDropStatementIDs
+	}
+	for i := 0; i < n; i++ {
+		id := s.dropStatementIDs[i]
+		s.statementID.id = &id
+		if err := s.writeRequest(mtDropStatementID, false, s.statementID); err != nil {
+			s.dropStatementIDs = s.dropStatementIDs[i+1:]
+			return err
+		}
+
+		if err := s.readReply(nil); err != nil {
+			if _, ok := err.(*hdbErrors); !ok {
+				s.dropStatementIDs = s.dropStatementIDs[i+1:]
+				return err
+			}
+			sqltrace.Traceln(err) // statement handle could not be released: do not fail the command
+		}
+	}
+	s.dropStatementIDs = s.dropStatementIDs[:copy(s.dropStatementIDs, s.dropStatementIDs[n:])]
 	return nil
 }
 
@@ -502,8 +1059,15 @@ func (s *Session) Call(id uint64, prmFieldSet *ParameterFieldSet, args []driver.
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	done := s.writeLobDone
+	s.writeLobDone = nil
+
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return nil, nil, err
+	}
+
 	s.statementID.id = &id
-	if err := s.writeRequest(mtExecute, false, s.statementID, newInputParameters(prmFieldSet.inputFields(), args)); err != nil {
+	if err := s.writeRequest(mtExecute, false, s.statementID, newInputParameters(prmFieldSet.inputFields(), args, s.inputOptions())); err != nil {
 		return nil, nil, err
 	}
 
@@ -529,7 +1093,7 @@ func (s *Session) Call(id uint64, prmFieldSet *ParameterFieldSet, args []driver.
 			p.id = &(tableResult.id)
 		case *resultset:
 			p.s = s
-			tableResult.attrs = s.ph.partAttributes
+			tableResult.attrs = s.resultset.attrs // part attributes (see readReply)
 			p.resultFieldSet = tableResult.resultFieldSet
 			p.fieldValues = tableResult.fieldValues
 		}
@@ -539,7 +1103,7 @@ func (s *Session) Call(id uint64, prmFieldSet *ParameterFieldSet, args []driver.
 		return nil, nil, err
 	}
 
-	if err := s.writeLobStream(prmFieldSet, prmFieldValues, args); err != nil {
+	if err := s.writeLobStream(prmFieldSet, prmFieldValues, args, 0, done); err != nil {
 		return nil, nil, err
 	}
 
@@ -547,22 +1111,34 @@ func (s *Session) Call(id uint64, prmFieldSet *ParameterFieldSet, args []driver.
 }
 
 // Query executes a query.
-func (s *Session) Query(stmtID uint64, prmFieldSet *ParameterFieldSet, resultFieldSet *ResultFieldSet, args []driver.NamedValue) (uint64, *FieldValues, PartAttributes, error) {
+// The result field set is the prepared resultFieldSet, or the field set of the result metadata returned by
+// the execution (e.g. table functions with dynamic result schema).
+func (s *Session) Query(stmtID uint64, prmFieldSet *ParameterFieldSet, resultFieldSet *ResultFieldSet, args []driver.NamedValue) (uint64, *ResultFieldSet, *FieldValues, PartAttributes, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	dec, options := s.nextQueryOptions()
+
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return 0, nil, nil, nil, err
+	}
+
 	s.statementID.id = &stmtID
-	if err := s.writeRequest(mtExecute, false, s.statementID, newInputParameters(prmFieldSet.inputFields(), args)); err != nil {
-		return 0, nil, nil, err
+	if err := s.writeRequestOptions(mtExecute, false, options, s.statementID, newInputParameters(prmFieldSet.inputFields(), args, s.inputOptions())); err != nil {
+		return 0, nil, nil, nil, err
 	}
 
 	var rsetID uint64
-	fieldValues := newFieldValues()
+	fieldValues := getFieldValues()
+	fieldValues.dec = dec
 
 	f := func(p replyPart) {
 
 		switch p := p.(type) {
 
+		case *resultMetadata: // result schema determined by execution
+			resultFieldSet = newResultFieldSet(p.numArg)
+			p.resultFieldSet = resultFieldSet
 		case *resultsetID:
 			p.id = &rsetID
 		case *resultset:
@@ -573,19 +1149,82 @@ func (s *Session) Query(stmtID uint64, prmFieldSet *ParameterFieldSet, resultFie
 	}
 
 	if err := s.readReply(f); err != nil {
-		return 0, nil, nil, err
+		fieldValues.Release()
+		return 0, nil, nil, nil, err
 	}
 
-	return rsetID, fieldValues, s.ph.partAttributes, nil
+	return rsetID, resultFieldSet, fieldValues, s.resultset.attrs, nil
 }
 
 // FetchNext fetches next chunk in query result set.
-func (s *Session) FetchNext(id uint64, resultFieldSet *ResultFieldSet, fieldValues *FieldValues) (PartAttributes, error) {
+// If fetchSize is 0 the session fetch size is used.
+// Resultsets opened with a scrollable cursor (see SetScrollableCursor) are never closed by the server after the
+// last packet, as the cursor can still be moved backwards.
+func (s *Session) FetchNext(id uint64, fetchSize int, scrollable bool, resultFieldSet *ResultFieldSet, fieldValues *FieldValues) (PartAttributes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fetchSize == 0 {
+		fetchSize = s.prm.FetchSize()
+	}
+
+	options := s.queryOptions()
+	if scrollable {
+		options = coNil
+	}
+
+	s.resultsetID.id = &id
+	return s.fetch(mtFetchNext, options, resultFieldSet, fieldValues, s.resultsetID, newFetchsize(fetchSize))
+}
+
+// FetchLast fetches the last row of a query result set opened with a scrollable cursor (see SetScrollableCursor).
+func (s *Session) FetchLast(id uint64, resultFieldSet *ResultFieldSet, fieldValues *FieldValues) (PartAttributes, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.resultsetID.id = &id
-	if err := s.writeRequest(mtFetchNext, false, s.resultsetID, fetchsize(s.prm.FetchSize())); err != nil {
+	return s.fetch(mtFetchLast, coNil, resultFieldSet, fieldValues, s.resultsetID)
+}
+
+// FetchRelative moves the cursor of a query result set opened with a scrollable cursor (see SetScrollableCursor)
+// by offset rows relative to the last fetched row and fetches the next chunk starting at the new cursor position.
+// If fetchSize is 0 the session fetch size is used.
+func (s *Session) FetchRelative(id uint64, offset, fetchSize int, resultFieldSet *ResultFieldSet, fieldValues *FieldValues) (PartAttributes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fetchSize == 0 {
+		fetchSize = s.prm.FetchSize()
+	}
+
+	s.resultsetID.id = &id
+	return s.fetch(mtFetchRelative, coNil, resultFieldSet, fieldValues, s.resultsetID, newFetchsize(fetchSize), newFetchOptions(offset))
+}
+
+// FetchAbsolute positions the cursor of a query result set opened with a scrollable cursor (see SetScrollableCursor)
+// on row pos (1: first row, negative: counted from the last row) and fetches the chunk starting at the new cursor position.
+// If fetchSize is 0 the session fetch size is used.
+func (s *Session) FetchAbsolute(id uint64, pos, fetchSize int, resultFieldSet *ResultFieldSet, fieldValues *FieldValues) (PartAttributes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fetchSize == 0 {
+		fetchSize = s.prm.FetchSize()
+	}
+
+	s.resultsetID.id = &id
+	return s.fetch(mtFetchAbsolute, coNil, resultFieldSet, fieldValues, s.resultsetID, newFetchsize(fetchSize), newFetchOptions(pos))
+}
+
+// fetch requests a chunk of a query result set. The command options decide, if the server closes the resultset
+// after the last packet (coNoResultsetCloseNeeded), which the callers only request for forward only resultsets.
+// A row split across fetch replies is only continued by fetching the next chunk (mtFetchNext).
+func (s *Session) fetch(messageType messageType, commandOptions commandOptions, resultFieldSet *ResultFieldSet, fieldValues *FieldValues, requests ...requestPart) (PartAttributes, error) {
+	if messageType != mtFetchNext { // positioning fetch: rows start at the new cursor position
+		fieldValues.splitRow = nil
+	}
+
+	if err := s.writeRequestOptions(messageType, false, commandOptions, requests...); err != nil {
 		return nil, err
 	}
 
@@ -604,7 +1243,7 @@ func (s *Session) FetchNext(id uint64, resultFieldSet *ResultFieldSet, fieldValu
 		return nil, err
 	}
 
-	return s.ph.partAttributes, nil
+	return s.resultset.attrs, nil
 }
 
 // CloseResultsetID releases the hdb resultset handle.
@@ -625,14 +1264,32 @@ func (s *Session) CloseResultsetID(id uint64) error {
 }
 
 // Commit executes a database commit.
+// In case of asynchronous commits the commit reply is not awaited but read before the next request is sent.
 func (s *Session) Commit() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return err
+	}
+
+	if cause := s.conn.txAbort; cause != nil { // transaction was rolled back: roll back statements executed since
+		if err := s.rollback(); err != nil {
+			return err
+		}
+		return fmt.Errorf("commit: transaction was rolled back: %s", cause)
+	}
+
 	if err := s.writeRequest(mtCommit, false); err != nil {
 		return err
 	}
 
+	if s.prm.AsyncCommit() {
+		s.commitReplyPending = true
+		s.conn.inTx = false
+		return nil
+	}
+
 	if err := s.readReply(nil); err != nil {
 		return err
 	}
@@ -650,6 +1307,13 @@ func (s *Session) Rollback() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return err
+	}
+	return s.rollback()
+}
+
+func (s *Session) rollback() error {
 	if err := s.writeRequest(mtRollback, false); err != nil {
 		return err
 	}
@@ -663,12 +1327,17 @@ func (s *Session) Rollback() error {
 	}
 
 	s.conn.inTx = false
+	s.conn.txAbort = nil
 	return nil
 }
 
-//
-
+// readLobStream reads the lob chunks of w from the database.
+// As lobs are read after the query returning the lob locators, the session is locked for the read lob requests,
+// so that lobs read concurrently (e.g. the lobs of different rows by different goroutines) do not interleave
+// with each other or with other requests on the connection.
 func (s *Session) readLobStream(w lobChunkWriter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	s.readLobRequest.w = w
 	s.readLobReply.w = w
@@ -685,27 +1354,67 @@ func (s *Session) readLobStream(w lobChunkWriter) error {
 	return nil
 }
 
-func (s *Session) writeLobStream(prmFieldSet *ParameterFieldSet, prmFieldValues *FieldValues, args []driver.NamedValue) error {
+// errWriteLobCanceled is returned if the lob parameter upload of a statement was canceled (see SetWriteLobDone).
+var errWriteLobCanceled = errors.New("lob write canceled")
+
+// abortWriteLob rolls back the statement with incompletely written lob parameters, so that no partially
+// written lob remains, and returns the error causing the abort (cancellation or lob reader error).
+// As the database does not support rolling back single statements, the enclosing transaction is
+// rolled back. The transaction mode of the session is kept until the transaction is ended, whereby
+// a commit of the rolled back transaction fails.
+func (s *Session) abortWriteLob(cause error) error {
+	if err := s.abortTx(cause); err != nil {
+		return err
+	}
+	return cause
+}
+
+// AbortTx rolls back the transaction of the session on behalf of the driver (e.g. an aborted bulk insert).
+// Like for aborted lob writes (see abortWriteLob) the transaction mode of the session is kept until the
+// transaction is ended, whereby a commit of the rolled back transaction fails with cause.
+func (s *Session) AbortTx(cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.dropPendingStatementIDs(); err != nil {
+		return err
+	}
+	return s.abortTx(cause)
+}
+
+func (s *Session) abortTx(cause error) error {
+	inTx := s.conn.inTx
+
+	if err := s.rollback(); err != nil {
+		return err
+	}
+	if inTx {
+		s.conn.inTx = true
+		s.conn.txAbort = cause
+	}
+	return nil
+}
+
+func (s *Session) writeLobStream(prmFieldSet *ParameterFieldSet, prmFieldValues *FieldValues, args []driver.NamedValue, rowOfs int, done <-chan struct{}) error {
 
 	if s.writeLobReply.numArg == 0 {
 		return nil
 	}
 
-	lobPrmFields := make([]*ParameterField, s.writeLobReply.numArg)
-
-	j := 0
-	for _, f := range prmFieldSet.fields {
-		if f.TypeCode().isLob() && f.In() && f.chunkReader != nil {
-			f.lobLocatorID = s.writeLobReply.ids[j]
-			lobPrmFields[j] = f
-			j++
-		}
+	// lob locator ids are returned in row order (bulk insert)
+	descrs := newWriteLobDescrs(prmFieldSet.inputFields(), args)
+	if len(descrs) != s.writeLobReply.numArg {
+		return fmt.Errorf("protocol error: invalid number of lob parameter ids %d - expected %d", len(descrs), s.writeLobReply.numArg)
 	}
-	if j != s.writeLobReply.numArg {
-		return fmt.Errorf("protocol error: invalid number of lob parameter ids %d - expected %d", j, s.writeLobReply.numArg)
+	for i, descr := range descrs {
+		descr.id = s.writeLobReply.ids[i]
+		descr.row += rowOfs
 	}
 
-	s.writeLobRequest.lobPrmFields = lobPrmFields
+	defer func() { // reset lob write state for next statement
+		s.writeLobRequest.descrs = nil
+		s.writeLobReply.numArg = 0
+	}()
 
 	f := func(p replyPart) {
 		if p, ok := p.(*outputParameters); ok {
@@ -715,14 +1424,43 @@ func (s *Session) writeLobStream(prmFieldSet *ParameterFieldSet, prmFieldValues
 		}
 	}
 
-	for s.writeLobReply.numArg != 0 {
-		if err := s.writeRequest(mtReadLob, false, s.writeLobRequest); err != nil {
-			return err
+	// write lobs row by row, so that errors can be linked to the row of the lob
+	for i := 0; i < len(descrs); {
+		row := descrs[i].row
+		j := i + 1
+		for j < len(descrs) && descrs[j].row == row {
+			j++
 		}
 
-		if err := s.readReply(f); err != nil {
-			return err
+		s.writeLobRequest.descrs = descrs[i:j]
+
+		for s.writeLobRequest.numArg() != 0 {
+			select { // check cancellation between chunks
+			case <-done:
+				return s.abortWriteLob(errWriteLobCanceled)
+			default:
+			}
+
+			// lob size might be unknown: read and write chunks up to the end of the lob readers
+			if err := s.writeLobRequest.fill(); err != nil { // lob reader error: fail statement
+				return s.abortWriteLob(err)
+			}
+
+			if err := s.writeRequest(mtReadLob, false, s.writeLobRequest); err != nil {
+				return err
+			}
+
+			if err := s.readReply(f); err != nil {
+				if err == s.lastError { //link error to row
+					for k := 0; k < s.lastError.numArg; k++ {
+						s.lastError.setStmtNo(k, row)
+					}
+				}
+				return err
+			}
 		}
+
+		i = j
 	}
 
 	return nil
@@ -755,7 +1493,59 @@ func (s *Session) initRequest() error {
 	return nil
 }
 
+// ReadPendingCommitReply reads the reply of an asynchronous commit, if pending (e.g. before the session is
+// closed or reused). A commit error is returned as *CommitError.
+func (s *Session) ReadPendingCommitReply() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readPendingCommitReply()
+}
+
+// readPendingCommitReply reads the reply of an asynchronous commit.
+func (s *Session) readPendingCommitReply() error {
+	if !s.commitReplyPending {
+		return nil
+	}
+	s.commitReplyPending = false
+	if err := s.readReply(nil); err != nil {
+		if hdbErr, ok := err.(*hdbErrors); ok { // session error is reused by subsequent replies
+			err = hdbErr.clone()
+		}
+		return &CommitError{err: err}
+	}
+	return nil
+}
+
 func (s *Session) writeRequest(messageType messageType, commit bool, requests ...requestPart) error {
+	return s.writeRequestOptions(messageType, commit, coNil, requests...)
+}
+
+// queryOptions returns the command options of query requests.
+func (s *Session) queryOptions() commandOptions {
+	if s.prm.AutoCloseResultset() { // server closes resultset after last packet
+		return coNoResultsetCloseNeeded
+	}
+	return coNil
+}
+
+func (s *Session) writeRequestOptions(messageType messageType, commit bool, commandOptions commandOptions, requests ...requestPart) error {
+
+	if err := s.readPendingCommitReply(); err != nil {
+		return err
+	}
+
+	if messageType == mtExecuteDirect || messageType == mtExecute {
+		if !s.skipClientInfo {
+			if part := s.clientInfo.changes(); len(part) != 0 {
+				requests = append(requests[:len(requests):len(requests)], part)
+			}
+		}
+		if s.nextSeqInfo != nil {
+			part := &statementContext{options: plainOptions{int8(scStatementSequenceInfo): binaryStringType(s.nextSeqInfo)}}
+			requests = append(requests[:len(requests):len(requests)], part)
+			s.nextSeqInfo = nil
+		}
+	}
 
 	partSize := make([]int, len(requests))
 
@@ -790,6 +1580,7 @@ func (s *Session) writeRequest(messageType messageType, commit bool, requests ..
 
 	s.sh.messageType = messageType
 	s.sh.commit = commit
+	s.sh.commandOptions = commandOptions
 	s.sh.segmentKind = skRequest
 	s.sh.segmentLength = int32(size)
 	s.sh.segmentOfs = 0
@@ -843,113 +1634,277 @@ func (s *Session) writeRequest(messageType messageType, commit bool, requests ..
 
 }
 
+// replyPartFuncs is the dispatch table of readReply returning the session reply part of a part kind.
+var replyPartFuncs = [...]func(s *Session) replyPart{
+	pkAuthentication: func(s *Session) replyPart {
+		if s.scramsha256InitialReply != nil { // first call: initial reply
+			return s.scramsha256InitialReply
+		}
+		return s.scramsha256FinalReply // second call: final reply
+	},
+	pkTopologyInformation: func(s *Session) replyPart { return s.topologyInformation },
+	pkDBConnectInfo:       func(s *Session) replyPart { return s.dbConnectInfo },
+	pkTableLocation:       func(s *Session) replyPart { return s.tableLocation },
+	pkConnectOptions:      func(s *Session) replyPart { return s.connectOptions },
+	pkStatementID:         func(s *Session) replyPart { return s.statementID },
+	pkResultMetadata:      func(s *Session) replyPart { return s.resultMetadata },
+	pkResultsetID:         func(s *Session) replyPart { return s.resultsetID },
+	pkResultset:           func(s *Session) replyPart { return s.resultset },
+	pkParameterMetadata:   func(s *Session) replyPart { return s.parameterMetadata },
+	pkOutputParameters:    func(s *Session) replyPart { return s.outputParameters },
+	pkError:               func(s *Session) replyPart { return s.lastError },
+	pkStatementContext:    func(s *Session) replyPart { return s.stmtCtx },
+	pkTransactionFlags:    func(s *Session) replyPart { return s.txFlags },
+	pkRowsAffected:        func(s *Session) replyPart { return s.rowsAffected },
+	pkReadLobReply:        func(s *Session) replyPart { return s.readLobReply },
+	pkWriteLobReply:       func(s *Session) replyPart { return s.writeLobReply },
+}
+
+// replyPartOf returns the session reply part of part kind kind.
+func (s *Session) replyPartOf(kind partKind) (replyPart, error) {
+	if kind < 0 || int(kind) >= len(replyPartFuncs) || replyPartFuncs[kind] == nil {
+		return nil, fmt.Errorf("read not expected part kind %s", kind)
+	}
+	return replyPartFuncs[kind](s), nil
+}
+
+// deferredPart is a reply part buffered to be read later in the reply (see readReply).
+type deferredPart struct {
+	kind     partKind
+	numArg   int
+	attrs    partAttributes
+	ofs, len int // part data in replyState buffer
+}
+
+// replyState is the state of reading a reply, which is kept by the session to reuse buffers of
+// previous replies.
+type replyState struct {
+	beforeRead beforeRead
+
+	// procedure call replies might contain resultset parts before the corresponding resultset metadata:
+	// these parts are buffered until the metadata was read
+	deferredParts []deferredPart
+	next          int           // index of next deferred part to be read
+	b             []byte        // buffer of deferred part data
+	br            *bytes.Reader // reader of deferred part data
+	rd            *bufio.Reader
+
+	numMetadata, numResultsetID, numResultset int
+}
+
+// reset prepares the reply state for reading the next reply, keeping the allocated buffers.
+func (r *replyState) reset(beforeRead beforeRead) {
+	r.beforeRead = beforeRead
+	r.deferredParts, r.next = r.deferredParts[:0], 0
+	r.b = r.b[:0]
+	r.numMetadata, r.numResultsetID, r.numResultset = 0, 0, 0
+}
+
+// readPart reads part from rd and counts the resultset related parts read.
+func (r *replyState) readPart(part replyPart, numArg int, rd *bufio.Reader) error {
+	part.setNumArg(numArg)
+
+	if r.beforeRead != nil {
+		r.beforeRead(part)
+	}
+
+	if err := part.read(rd); err != nil {
+		return err
+	}
+
+	switch part.(type) {
+	case *resultMetadata:
+		r.numMetadata++
+	case *resultsetID:
+		r.numResultsetID++
+	case *resultset:
+		r.numResultset++
+	}
+	return nil
+}
+
+// metadataPending returns true, if the metadata of a resultset (id) part was not read yet.
+func (r *replyState) metadataPending(kind partKind) bool {
+	switch kind {
+	case pkResultsetID:
+		return r.numResultsetID >= r.numMetadata
+	case pkResultset:
+		return r.numResultset >= r.numMetadata
+	}
+	return false
+}
+
+// deferPart buffers the part data of part header ph.
+func (r *replyState) deferPart(rd *bufio.Reader, ph *partHeader) {
+	ofs, size := len(r.b), int(ph.bufferLength)
+	if cap(r.b)-ofs < size {
+		b := make([]byte, ofs, 2*cap(r.b)+size)
+		copy(b, r.b)
+		r.b = b
+	}
+	r.b = r.b[:ofs+size]
+	rd.ReadFull(r.b[ofs:])
+	r.deferredParts = append(r.deferredParts, deferredPart{kind: ph.partKind, numArg: int(ph.argumentCount), attrs: ph.partAttributes, ofs: ofs, len: size})
+}
+
+// hasDeferredParts returns true, if buffered parts were not read yet.
+func (r *replyState) hasDeferredParts() bool {
+	return r.next < len(r.deferredParts)
+}
+
+// readDeferredParts reads the buffered parts in order. If all is false, parts are only read
+// after the corresponding metadata was read.
+func (r *replyState) readDeferredParts(s *Session, all bool) error {
+	for r.hasDeferredParts() {
+		d := r.deferredParts[r.next]
+		if !all && r.metadataPending(d.kind) {
+			return nil
+		}
+		r.next++
+
+		var part replyPart = s.resultsetID
+		if d.kind == pkResultset {
+			s.resultset.attrs, s.resultset.size = d.attrs, d.len
+			part = s.resultset
+		}
+
+		if r.br == nil {
+			r.br = bytes.NewReader(r.b[d.ofs : d.ofs+d.len])
+			r.rd = bufio.NewReader(r.br)
+		} else {
+			r.br.Reset(r.b[d.ofs : d.ofs+d.len])
+			r.rd.Reset(r.br)
+		}
+		if err := r.readPart(part, d.numArg, r.rd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readReply reads the reply of the last request. Reply parts are dispatched to the session
+// reply parts (see replyPartOf) and parts preceding their resultset metadata are deferred (see replyState).
 func (s *Session) readReply(beforeRead beforeRead) error {
 
 	replyRowsAffected := false
 	replyError := false
 
-	if err := s.mh.read(s.rd); err != nil {
-		return err
-	}
-	if s.mh.noOfSegm != 1 {
-		return fmt.Errorf("simple message: no of segments %d - expected 1", s.mh.noOfSegm)
+	s.resultset.reset()
+	s.rowsAffected.reset()
+	s.stmtCtx.reset()
+
+	if s.reply == nil {
+		s.reply = new(replyState)
 	}
-	if err := s.sh.read(s.rd); err != nil {
+	r := s.reply
+	r.reset(beforeRead)
+	defer r.reset(nil) // release callback
+
+	if err := s.mh.read(s.rd); err != nil {
 		return err
 	}
 
-	// TODO: protocol error (sps 82)?: message header varPartLength < segment header segmentLength (*1)
-	diff := int(s.mh.varPartLength) - int(s.sh.segmentLength)
-	if trace && diff != 0 {
-		outLogger.Printf("+++++diff %d", diff)
+	noOfSegm := int(s.mh.noOfSegm)
+	if noOfSegm < 1 {
+		return fmt.Errorf("invalid number of segments %d - expected at least 1", noOfSegm)
 	}
+	lastSegm := noOfSegm - 1
+	segmentLength := 0 // accumulated segment length
 
-	noOfParts := int(s.sh.noOfParts)
-	lastPart := noOfParts - 1
-
-	for i := 0; i < noOfParts; i++ {
+	for j := 0; j < noOfSegm; j++ {
 
-		if err := s.ph.read(s.rd); err != nil {
+		if err := s.sh.read(s.rd); err != nil {
 			return err
 		}
+		segmentLength += int(s.sh.segmentLength)
 
-		numArg := int(s.ph.argumentCount)
+		noOfParts := int(s.sh.noOfParts)
+		lastPart := noOfParts - 1
 
-		var part replyPart
+		for i := 0; i < noOfParts; i++ {
 
-		switch s.ph.partKind {
+			if err := s.ph.read(s.rd); err != nil {
+				return err
+			}
+
+			numArg := int(s.ph.argumentCount)
 
-		case pkAuthentication:
-			if s.scramsha256InitialReply != nil { // first call: initial reply
-				part = s.scramsha256InitialReply
-			} else { // second call: final reply
-				part = s.scramsha256FinalReply
+			part, err := s.replyPartOf(s.ph.partKind)
+			if err != nil {
+				return err
+			}
+
+			switch s.ph.partKind {
+			case pkResultset:
+				s.resultset.attrs = s.ph.partAttributes // resultset might not be the last part of the reply
+				s.resultset.size = int(s.ph.bufferLength)
+			case pkError:
+				replyError = true
+			case pkRowsAffected:
+				replyRowsAffected = true
 			}
-		case pkTopologyInformation:
-			part = s.topologyInformation
-		case pkConnectOptions:
-			part = s.connectOptions
-		case pkStatementID:
-			part = s.statementID
-		case pkResultMetadata:
-			part = s.resultMetadata
-		case pkResultsetID:
-			part = s.resultsetID
-		case pkResultset:
-			part = s.resultset
-		case pkParameterMetadata:
-			part = s.parameterMetadata
-		case pkOutputParameters:
-			part = s.outputParameters
-		case pkError:
-			replyError = true
-			part = s.lastError
-		case pkStatementContext:
-			part = s.stmtCtx
-		case pkTransactionFlags:
-			part = s.txFlags
-		case pkRowsAffected:
-			replyRowsAffected = true
-			part = s.rowsAffected
-		case pkReadLobReply:
-			part = s.readLobReply
-		case pkWriteLobReply:
-			part = s.writeLobReply
-		default:
-			return fmt.Errorf("read not expected part kind %s", s.ph.partKind)
-		}
 
-		part.setNumArg(numArg)
+			switch {
+			case (s.sh.functionCode == fcDBProcedureCall || s.sh.functionCode == fcDBProcedureCallWithResult) &&
+				(r.hasDeferredParts() || r.metadataPending(s.ph.partKind)) &&
+				(s.ph.partKind == pkResultsetID || s.ph.partKind == pkResultset):
+				r.deferPart(s.rd, s.ph)
+			default:
+				if err := r.readPart(part, numArg, s.rd); err != nil {
+					return err
+				}
+				if _, ok := part.(*resultMetadata); ok {
+					if err := r.readDeferredParts(s, false); err != nil {
+						return err
+					}
+				}
+			}
 
-		if beforeRead != nil {
-			beforeRead(part)
+			if i != lastPart { // not last part
+				// Error padding (protocol error?)
+				// driver test TestHDBWarning
+				//   --> 18 bytes fix error bytes + 103 bytes error text => 121 bytes (7 bytes padding needed)
+				//   but s.ph.bufferLength = 122 (standard padding would only consume 6 bytes instead of 7)
+				// driver test TestBulkInsertDuplicates
+				//   --> returns 3 errors (number of total bytes matches s.ph.bufferLength)
+				// ==> hdbErrors take care about padding
+				if s.ph.partKind != pkError {
+					s.rd.Skip(padBytes(int(s.ph.bufferLength)))
+				}
+			}
 		}
 
-		if err := part.read(s.rd); err != nil {
-			return err
+		// last part of segment
+		if j != lastSegm { // segments are 8 byte aligned
+			s.rd.Skip(padBytes(int(s.ph.bufferLength)))
+			continue
 		}
 
-		if i != lastPart { // not last part
-			// Error padding (protocol error?)
-			// driver test TestHDBWarning
-			//   --> 18 bytes fix error bytes + 103 bytes error text => 121 bytes (7 bytes padding needed)
-			//   but s.ph.bufferLength = 122 (standard padding would only consume 6 bytes instead of 7)
-			// driver test TestBulkInsertDuplicates
-			//   --> returns 3 errors (number of total bytes matches s.ph.bufferLength)
-			// ==> hdbErrors take care about padding
-			if s.ph.partKind != pkError {
-				s.rd.Skip(padBytes(int(s.ph.bufferLength)))
-			}
+		// last part of message
+		// TODO: protocol error (sps 82)?: message header varPartLength < segment header segmentLength (*1)
+		diff := int(s.mh.varPartLength) - segmentLength
+		if trace && diff != 0 {
+			outLogger.Printf("+++++diff %d", diff)
+		}
+		// TODO: workaround (see *)
+		if diff == 0 {
+			s.rd.Skip(padBytes(int(s.ph.bufferLength)))
 		}
 	}
 
-	// last part
-	// TODO: workaround (see *)
-	if diff == 0 {
-		s.rd.Skip(padBytes(int(s.ph.bufferLength)))
+	if err := s.rd.GetError(); err != nil {
+		return err
 	}
 
-	if err := s.rd.GetError(); err != nil {
+	if err := r.readDeferredParts(s, true); err != nil { // parts without metadata in reply
+		return err
+	}
+
+	if seqInfo := s.stmtCtx.sequenceInfo(); seqInfo != nil {
+		s.seqInfo = seqInfo
+	}
+
+	if err := s.resultset.keepSplitRow(); err != nil {
 		return err
 	}
 
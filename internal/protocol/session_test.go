@@ -0,0 +1,1030 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"database/sql/driver"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+	"golang.org/x/text/encoding"
+)
+
+func TestReadPendingCommitReply(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	// commit reply
+	mh := &messageHeader{varPartLength: segmentHeaderSize, varPartSize: segmentHeaderSize, noOfSegm: 1}
+	mh.write(wr)
+	sh := &segmentHeader{segmentLength: segmentHeaderSize, segmentNo: 1, segmentKind: skReply, functionCode: fcCommit}
+	sh.write(wr)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Session{
+		rd:                 bufio.NewReader(buf),
+		mh:                 new(messageHeader),
+		sh:                 new(segmentHeader),
+		ph:                 new(partHeader),
+		resultset:          new(resultset),
+		rowsAffected:       new(rowsAffected),
+		stmtCtx:            newStatementContext(),
+		lastError:          new(hdbErrors),
+		commitReplyPending: true,
+	}
+
+	if err := s.readPendingCommitReply(); err != nil {
+		t.Fatal(err)
+	}
+	if s.commitReplyPending {
+		t.Fatal("commit reply still pending")
+	}
+	// no pending reply: must not read
+	if err := s.readPendingCommitReply(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadReplyLateResultMetadata(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	type part struct {
+		kind   partKind
+		numArg int
+		size   int
+		write  func()
+	}
+
+	metadata := part{pkResultMetadata, 1, 24, func() { // one integer field without name
+		wr.WriteInt8(0) // column options
+		wr.WriteInt8(int8(tcInteger))
+		wr.WriteInt16(0)  // fraction
+		wr.WriteInt16(10) // length
+		wr.WriteZeroes(2)
+		for i := 0; i < 4; i++ {
+			wr.WriteUint32(noFieldName)
+		}
+	}}
+	idPart := func(id uint64) part {
+		return part{pkResultsetID, 1, resultsetIDSize, func() { wr.WriteUint64(id) }}
+	}
+	rowsPart := func(rows ...int32) part {
+		return part{pkResultset, len(rows), len(rows) * (1 + intFieldSize), func() {
+			for _, v := range rows {
+				wr.WriteBool(true) // not null
+				wr.WriteInt32(v)
+			}
+		}}
+	}
+
+	// table 1: resultset id and resultset before metadata, table 2: regular order
+	parts := []part{idPart(1), rowsPart(1, 2), metadata, metadata, idPart(2), rowsPart(3)}
+
+	segmentLength := segmentHeaderSize
+	for _, p := range parts {
+		segmentLength += partHeaderSize + p.size + padBytes(p.size)
+	}
+	mh := &messageHeader{varPartLength: uint32(segmentLength), varPartSize: uint32(segmentLength), noOfSegm: 1}
+	mh.write(wr)
+	sh := &segmentHeader{segmentLength: int32(segmentLength), noOfParts: int16(len(parts)), segmentNo: 1, segmentKind: skReply, functionCode: fcDBProcedureCall}
+	sh.write(wr)
+	for _, p := range parts {
+		ph := &partHeader{partKind: p.kind, argumentCount: int16(p.numArg), bufferLength: int32(p.size), bufferSize: int32(p.size)}
+		ph.write(wr)
+		p.write()
+		wr.WriteZeroes(padBytes(p.size))
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Session{
+		rd:             bufio.NewReader(buf),
+		mh:             new(messageHeader),
+		sh:             new(segmentHeader),
+		ph:             new(partHeader),
+		resultMetadata: new(resultMetadata),
+		resultsetID:    new(resultsetID),
+		resultset:      new(resultset),
+		rowsAffected:   new(rowsAffected),
+		stmtCtx:        newStatementContext(),
+		lastError:      new(hdbErrors),
+	}
+
+	// procedure call like reply handling
+	var tableResults []*TableResult
+	var tableResult *TableResult
+	if err := s.readReply(func(p replyPart) {
+		switch p := p.(type) {
+		case *resultMetadata:
+			tableResult = newTableResult(s, p.numArg)
+			tableResults = append(tableResults, tableResult)
+			p.resultFieldSet = tableResult.resultFieldSet
+		case *resultsetID:
+			p.id = &(tableResult.id)
+		case *resultset:
+			p.s = s
+			p.resultFieldSet = tableResult.resultFieldSet
+			p.fieldValues = tableResult.fieldValues
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]int64{{1, 2}, {3}}
+	if len(tableResults) != len(expected) {
+		t.Fatalf("number of table results %d - expected %d", len(tableResults), len(expected))
+	}
+	dest := make([]driver.Value, 1)
+	for i, tableResult := range tableResults {
+		if tableResult.id != uint64(i+1) {
+			t.Fatalf("table %d: resultset id %d - expected %d", i, tableResult.id, i+1)
+		}
+		if tableResult.fieldValues.NumRow() != len(expected[i]) {
+			t.Fatalf("table %d: number of rows %d - expected %d", i, tableResult.fieldValues.NumRow(), len(expected[i]))
+		}
+		for j, v := range expected[i] {
+			tableResult.fieldValues.Row(j, dest)
+			if dest[0] != v {
+				t.Fatalf("table %d row %d: value %v - expected %d", i, j, dest[0], v)
+			}
+		}
+	}
+}
+
+func TestReadReplyUnrequestedResultMetadata(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+
+	size := 24 // one integer field without name
+
+	segmentLength := segmentHeaderSize + partHeaderSize + size + padBytes(size)
+	mh := &messageHeader{varPartLength: uint32(segmentLength), varPartSize: uint32(segmentLength), noOfSegm: 1}
+	mh.write(wr)
+	sh := &segmentHeader{segmentLength: int32(segmentLength), noOfParts: 1, segmentNo: 1, segmentKind: skReply, functionCode: fcSelect}
+	sh.write(wr)
+	ph := &partHeader{partKind: pkResultMetadata, argumentCount: 1, bufferLength: int32(size), bufferSize: int32(size)}
+	ph.write(wr)
+	wr.WriteInt8(0) // column options
+	wr.WriteInt8(int8(tcInteger))
+	wr.WriteInt16(0)  // fraction
+	wr.WriteInt16(10) // length
+	wr.WriteZeroes(2)
+	for i := 0; i < 4; i++ {
+		wr.WriteUint32(noFieldName)
+	}
+	wr.WriteZeroes(padBytes(size))
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Session{
+		rd:             bufio.NewReader(buf),
+		mh:             new(messageHeader),
+		sh:             new(segmentHeader),
+		ph:             new(partHeader),
+		resultMetadata: new(resultMetadata),
+		resultset:      new(resultset),
+		rowsAffected:   new(rowsAffected),
+		stmtCtx:        newStatementContext(),
+		lastError:      new(hdbErrors),
+	}
+
+	// field set of a previous reply (e.g. prepare)
+	prevFieldSet := newResultFieldSet(2)
+	s.resultMetadata.resultFieldSet = prevFieldSet
+
+	if err := s.readReply(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, f := range prevFieldSet.fields {
+		if f != nil {
+			t.Fatalf("field %d of previous field set overwritten", i)
+		}
+	}
+	resultFieldSet := s.resultMetadata.resultFieldSet
+	if resultFieldSet == prevFieldSet {
+		t.Fatal("new field set expected")
+	}
+	if resultFieldSet.NumField() != 1 {
+		t.Fatalf("number of fields %d - expected %d", resultFieldSet.NumField(), 1)
+	}
+	if resultFieldSet.Field(0).TypeCode() != tcInteger {
+		t.Fatalf("type code %s - expected %s", resultFieldSet.Field(0).TypeCode(), tcInteger)
+	}
+}
+
+func TestUnixSocketConn(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "hdb.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skip(err) // unix domain sockets not supported
+	}
+	defer l.Close()
+
+	go func() {
+		if conn, err := l.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	// tls configuration is ignored for unix domain sockets
+	c, err := newSessionConn(context.Background(), UnixSocketPrefix+path, 1, &tls.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.close()
+
+	if _, ok := c.conn.(*net.UnixConn); !ok {
+		t.Fatalf("connection type %T - expected %T", c.conn, (*net.UnixConn)(nil))
+	}
+
+	for addr, expected := range map[string][2]string{
+		"localhost:30015":      {"tcp", "localhost:30015"},
+		"unix:///tmp/hdb.sock": {"unix", "/tmp/hdb.sock"},
+	} {
+		if network, address := dialAddr(addr); network != expected[0] || address != expected[1] {
+			t.Fatalf("%s: network %s address %s - expected %s %s", addr, network, address, expected[0], expected[1])
+		}
+	}
+}
+
+// testReplyPart is a reply part written by writeTestReply.
+type testReplyPart struct {
+	kind   partKind
+	numArg int
+	size   int
+	attrs  partAttributes
+	write  func(wr *bufio.Writer)
+}
+
+// testMetadataPart returns a result metadata part of cols integer fields without name.
+func testMetadataPart(cols int) testReplyPart {
+	return testReplyPart{pkResultMetadata, cols, cols * 24, 0, func(wr *bufio.Writer) {
+		for i := 0; i < cols; i++ {
+			wr.WriteInt8(0) // column options
+			wr.WriteInt8(int8(tcInteger))
+			wr.WriteInt16(0)  // fraction
+			wr.WriteInt16(10) // length
+			wr.WriteZeroes(2)
+			for j := 0; j < 4; j++ {
+				wr.WriteUint32(noFieldName)
+			}
+		}
+	}}
+}
+
+func testResultsetIDPart(id uint64) testReplyPart {
+	return testReplyPart{pkResultsetID, 1, resultsetIDSize, 0, func(wr *bufio.Writer) { wr.WriteUint64(id) }}
+}
+
+// testResultsetPart returns a resultset part of rows rows with cols integer fields.
+func testResultsetPart(rows, cols int) testReplyPart {
+	return testReplyPart{pkResultset, rows, rows * cols * (1 + intFieldSize), 0, func(wr *bufio.Writer) {
+		for i := 0; i < rows*cols; i++ {
+			wr.WriteBool(true) // not null
+			wr.WriteInt32(int32(i))
+		}
+	}}
+}
+
+// writeTestReply writes a single segment reply message containing parts.
+func writeTestReply(wr *bufio.Writer, fc functionCode, parts []testReplyPart) {
+	segmentLength := segmentHeaderSize
+	for _, p := range parts {
+		segmentLength += partHeaderSize + p.size + padBytes(p.size)
+	}
+	mh := &messageHeader{varPartLength: uint32(segmentLength), varPartSize: uint32(segmentLength), noOfSegm: 1}
+	mh.write(wr)
+	sh := &segmentHeader{segmentLength: int32(segmentLength), noOfParts: int16(len(parts)), segmentNo: 1, segmentKind: skReply, functionCode: fc}
+	sh.write(wr)
+	for _, p := range parts {
+		ph := &partHeader{partKind: p.kind, partAttributes: p.attrs, argumentCount: int16(p.numArg), bufferLength: int32(p.size), bufferSize: int32(p.size)}
+		ph.write(wr)
+		p.write(wr)
+		wr.WriteZeroes(padBytes(p.size))
+	}
+}
+
+// replayReader reads b repeatedly.
+type replayReader struct {
+	b   []byte
+	ofs int
+}
+
+func (r *replayReader) Read(p []byte) (int, error) {
+	n := copy(p, r.b[r.ofs:])
+	r.ofs = (r.ofs + n) % len(r.b)
+	return n, nil
+}
+
+func TestReplyPartOf(t *testing.T) {
+	s := &Session{resultset: new(resultset), lastError: new(hdbErrors)}
+
+	for _, kind := range []partKind{-1, pkNil, pkCommand, pkSQLReplyOptions, pkSQLReplyOptions + 1} {
+		if _, err := s.replyPartOf(kind); err == nil {
+			t.Fatalf("part kind %s: error expected", kind)
+		}
+	}
+	if part, err := s.replyPartOf(pkResultset); err != nil || part != s.resultset {
+		t.Fatalf("part %v error %v - expected %v", part, err, s.resultset)
+	}
+	if part, err := s.replyPartOf(pkError); err != nil || part != s.lastError {
+		t.Fatalf("part %v error %v - expected %v", part, err, s.lastError)
+	}
+}
+
+func TestReadReplyDeferredPartsReuse(t *testing.T) {
+	const cols = 2
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	var parts []testReplyPart
+	for i := 0; i < 3; i++ {
+		parts = append(parts, testResultsetIDPart(uint64(i+1)), testResultsetPart(i+1, cols), testMetadataPart(cols))
+	}
+	writeTestReply(wr, fcDBProcedureCall, parts)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Session{
+		rd:             bufio.NewReader(&replayReader{b: buf.Bytes()}),
+		mh:             new(messageHeader),
+		sh:             new(segmentHeader),
+		ph:             new(partHeader),
+		resultMetadata: new(resultMetadata),
+		resultsetID:    new(resultsetID),
+		resultset:      new(resultset),
+		rowsAffected:   new(rowsAffected),
+		stmtCtx:        newStatementContext(),
+		lastError:      new(hdbErrors),
+	}
+
+	dest := make([]driver.Value, cols)
+	for n := 0; n < 2; n++ { // second reply reuses the buffers of the first one
+		var tableResults []*TableResult
+		var tableResult *TableResult
+		if err := s.readReply(func(p replyPart) {
+			switch p := p.(type) {
+			case *resultMetadata:
+				tableResult = newTableResult(s, p.numArg)
+				tableResults = append(tableResults, tableResult)
+				p.resultFieldSet = tableResult.resultFieldSet
+			case *resultsetID:
+				p.id = &(tableResult.id)
+			case *resultset:
+				p.s = s
+				p.resultFieldSet = tableResult.resultFieldSet
+				p.fieldValues = tableResult.fieldValues
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(tableResults) != 3 {
+			t.Fatalf("reply %d: number of table results %d - expected %d", n, len(tableResults), 3)
+		}
+		for i, tableResult := range tableResults {
+			if tableResult.id != uint64(i+1) {
+				t.Fatalf("reply %d table %d: resultset id %d - expected %d", n, i, tableResult.id, i+1)
+			}
+			if tableResult.fieldValues.NumRow() != i+1 {
+				t.Fatalf("reply %d table %d: number of rows %d - expected %d", n, i, tableResult.fieldValues.NumRow(), i+1)
+			}
+			for j := 0; j < i+1; j++ {
+				tableResult.fieldValues.Row(j, dest)
+				for k, v := range dest {
+					if v != int64(j*cols+k) {
+						t.Fatalf("reply %d table %d row %d: value %v - expected %d", n, i, j, v, j*cols+k)
+					}
+				}
+			}
+		}
+	}
+}
+
+// testSplitResultsetPart returns a resultset part of numArg rows containing the integer field values from..to-1.
+func testSplitResultsetPart(numArg, from, to int) testReplyPart {
+	return testReplyPart{pkResultset, numArg, (to - from) * (1 + intFieldSize), 0, func(wr *bufio.Writer) {
+		for i := from; i < to; i++ {
+			wr.WriteBool(true) // not null
+			wr.WriteInt32(int32(i))
+		}
+	}}
+}
+
+func TestReadSplitRow(t *testing.T) {
+	const cols = 200 // wide row
+
+	// read reads a reply into tableResult (nil: reply including result metadata)
+	read := func(tableResult *TableResult, parts []testReplyPart) (*TableResult, error) {
+		buf := new(bytes.Buffer)
+		wr := bufio.NewWriter(buf)
+		writeTestReply(wr, fcSelect, parts)
+		if err := wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		s := &Session{
+			rd:             bufio.NewReader(buf),
+			mh:             new(messageHeader),
+			sh:             new(segmentHeader),
+			ph:             new(partHeader),
+			resultMetadata: new(resultMetadata),
+			resultsetID:    new(resultsetID),
+			resultset:      new(resultset),
+			rowsAffected:   new(rowsAffected),
+			stmtCtx:        newStatementContext(),
+			lastError:      new(hdbErrors),
+		}
+
+		err := s.readReply(func(p replyPart) {
+			switch p := p.(type) {
+			case *resultMetadata:
+				tableResult = newTableResult(s, p.numArg)
+				p.resultFieldSet = tableResult.resultFieldSet
+			case *resultsetID:
+				p.id = &(tableResult.id)
+			case *resultset:
+				p.s = s
+				p.resultFieldSet = tableResult.resultFieldSet
+				p.fieldValues = tableResult.fieldValues
+			}
+		})
+		return tableResult, err
+	}
+
+	// checkRows checks that the field values consist of the rows from, from+1, ...
+	checkRows := func(tableResult *TableResult, from, numRow int) {
+		if tableResult.fieldValues.NumRow() != numRow {
+			t.Fatalf("number of rows %d - expected %d", tableResult.fieldValues.NumRow(), numRow)
+		}
+		dest := make([]driver.Value, cols)
+		for i := 0; i < numRow; i++ {
+			tableResult.fieldValues.Row(i, dest)
+			for j, v := range dest {
+				if v != int64((from+i)*cols+j) {
+					t.Fatalf("row %d field %d: value %v - expected %d", from+i, j, v, (from+i)*cols+j)
+				}
+			}
+		}
+	}
+
+	// 3 rows: the second row is split at field 150, whereby the row is counted by both resultset parts
+	tableResult, err := read(nil, []testReplyPart{
+		testMetadataPart(cols),
+		testResultsetIDPart(1),
+		testSplitResultsetPart(2, 0, cols+150),
+		testSplitResultsetPart(2, cols+150, 3*cols),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(tableResult, 0, 3)
+
+	// rows split across fetch replies: the second row is split at field 150, the third row at field 0
+	// (the reply counts the row without containing fields of it)
+	tableResult, err = read(nil, []testReplyPart{
+		testMetadataPart(cols),
+		testResultsetIDPart(1),
+		testSplitResultsetPart(2, 0, cols+150),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(tableResult, 0, 1) // incomplete row is not provided
+	if _, err := read(tableResult, []testReplyPart{testSplitResultsetPart(2, cols+150, 2*cols)}); err != nil {
+		t.Fatal(err)
+	}
+	checkRows(tableResult, 1, 1)
+	if _, err := read(tableResult, []testReplyPart{testSplitResultsetPart(1, 2*cols, 3*cols)}); err != nil {
+		t.Fatal(err)
+	}
+	checkRows(tableResult, 2, 1)
+
+	// split row in last packet
+	part := testSplitResultsetPart(2, 0, cols+150)
+	part.attrs = paLastPacket
+	if _, err := read(nil, []testReplyPart{
+		testMetadataPart(cols),
+		testResultsetIDPart(1),
+		part,
+	}); err == nil {
+		t.Fatal("incomplete row error expected")
+	}
+}
+
+func benchmarkReadReply(b *testing.B, fc functionCode, parts []testReplyPart) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	writeTestReply(wr, fc, parts)
+	if err := wr.Flush(); err != nil {
+		b.Fatal(err)
+	}
+
+	s := &Session{
+		rd:             bufio.NewReader(&replayReader{b: buf.Bytes()}),
+		mh:             new(messageHeader),
+		sh:             new(segmentHeader),
+		ph:             new(partHeader),
+		resultMetadata: new(resultMetadata),
+		resultsetID:    new(resultsetID),
+		resultset:      new(resultset),
+		rowsAffected:   new(rowsAffected),
+		stmtCtx:        newStatementContext(),
+		lastError:      new(hdbErrors),
+	}
+
+	var tableResult *TableResult
+	beforeRead := func(p replyPart) {
+		switch p := p.(type) {
+		case *resultMetadata:
+			tableResult = newTableResult(s, p.numArg)
+			p.resultFieldSet = tableResult.resultFieldSet
+		case *resultsetID:
+			p.id = &(tableResult.id)
+		case *resultset:
+			p.s = s
+			p.resultFieldSet = tableResult.resultFieldSet
+			p.fieldValues = tableResult.fieldValues
+		}
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(buf.Len()))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := s.readReply(beforeRead); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadReply(b *testing.B) {
+	const cols = 4
+
+	b.Run("query", func(b *testing.B) {
+		benchmarkReadReply(b, fcSelect, []testReplyPart{testMetadataPart(cols), testResultsetIDPart(1), testResultsetPart(1000, cols)})
+	})
+
+	// procedure call: many small tables, resultset id and resultset before metadata
+	b.Run("procedureCall", func(b *testing.B) {
+		var parts []testReplyPart
+		for i := 0; i < 32; i++ {
+			parts = append(parts, testResultsetIDPart(uint64(i+1)), testResultsetPart(8, cols), testMetadataPart(cols))
+		}
+		benchmarkReadReply(b, fcDBProcedureCall, parts)
+	})
+}
+
+// testErrorPart returns an error part of a single error (text length: 8 byte alignment without padding).
+func testErrorPart(code int32, text string) testReplyPart {
+	return testReplyPart{pkError, 1, 18 + len(text), 0, func(wr *bufio.Writer) {
+		wr.WriteInt32(code)
+		wr.WriteInt32(0) // position
+		wr.WriteInt32(int32(len(text)))
+		wr.WriteInt8(int8(errorLevelError))
+		wr.Write([]byte("HY000"))
+		wr.Write([]byte(text))
+	}}
+}
+
+func TestQueryDirectBatch(t *testing.T) {
+	in := new(bytes.Buffer) // database server replies
+	wr := bufio.NewWriter(in)
+
+	// select, error, select, error
+	writeTestReply(wr, fcSelect, []testReplyPart{testMetadataPart(1), testResultsetIDPart(1), testResultsetPart(2, 1)})
+	writeTestReply(wr, fcNil, []testReplyPart{testErrorPart(259, "invalid table name: T1")})
+	writeTestReply(wr, fcSelect, []testReplyPart{testMetadataPart(1), testResultsetIDPart(3), testResultsetPart(1, 1)})
+	writeTestReply(wr, fcNil, []testReplyPart{testErrorPart(260, "invalid column name: C")})
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer) // client requests
+
+	s := &Session{
+		conn:           &sessionConn{},
+		rd:             bufio.NewReader(in),
+		wr:             bufio.NewWriter(out),
+		mh:             new(messageHeader),
+		sh:             new(segmentHeader),
+		ph:             new(partHeader),
+		resultMetadata: new(resultMetadata),
+		resultsetID:    new(resultsetID),
+		resultset:      new(resultset),
+		rowsAffected:   new(rowsAffected),
+		stmtCtx:        newStatementContext(),
+		lastError:      new(hdbErrors),
+		clientInfo:     newClientInfo(nil),
+	}
+
+	queries := []string{"select 1", "select 2", "select 3", "select 4"}
+	results, err := s.queryDirectBatch(queries, nil, coNil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// all requests written before the replies are read
+	rd := bufio.NewReader(out)
+	for range queries {
+		if err := s.mh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.sh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if s.sh.messageType != mtExecuteDirect {
+			t.Fatalf("message type %s - expected %s", s.sh.messageType, mtExecuteDirect)
+		}
+		rd.Skip(int(s.mh.varPartLength) - segmentHeaderSize)
+	}
+
+	for i, e := range []struct {
+		id      uint64
+		numRow  int
+		errCode int
+	}{{1, 2, 0}, {0, 0, 259}, {3, 1, 0}, {0, 0, 260}} {
+		r := results[i]
+		if e.errCode != 0 {
+			hdbErr, ok := r.Err.(*hdbErrors)
+			if !ok || hdbErr.Code() != e.errCode {
+				t.Fatalf("query %d: error %v - expected error code %d", i, r.Err, e.errCode)
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Fatalf("query %d: %s", i, r.Err)
+		}
+		if r.ID != e.id || r.FieldValues.NumRow() != e.numRow {
+			t.Fatalf("query %d: resultset id %d rows %d - expected %d %d", i, r.ID, r.FieldValues.NumRow(), e.id, e.numRow)
+		}
+	}
+}
+
+// testSessionPrm provides the session parameters used by the session tests.
+type testSessionPrm struct {
+	sessionPrm
+}
+
+func (testSessionPrm) PacketSize() int                 { return 1 << 17 }
+func (testSessionPrm) EmptyStringAsNull() bool         { return false }
+func (testSessionPrm) ValidateUTF8() bool              { return false }
+func (testSessionPrm) CharEncoding() encoding.Encoding { return nil }
+func (testSessionPrm) AsyncCommit() bool               { return false }
+func (testSessionPrm) DropStatementsImmediately() bool { return false }
+func (testSessionPrm) StatementRouting() bool          { return false }
+func (testSessionPrm) ClientInfo() map[string]string   { return nil }
+func (testSessionPrm) AutoCloseResultset() bool        { return true }
+func (testSessionPrm) FetchSize() int                  { return 128 }
+
+func TestExecSplitCommit(t *testing.T) {
+	prmFieldSet := newParameterFieldSet(1)
+	prmFieldSet.fields[0] = &ParameterField{tc: tcInteger, mode: pmIn}
+	prmFieldSet._inputFields = append(prmFieldSet._inputFields, prmFieldSet.fields[0])
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(1)}, {Ordinal: 2, Value: int64(2)}}
+
+	for _, d := range []struct {
+		replies  []functionCode // fcNil: error reply
+		messages []messageType
+		commits  []bool
+		numParts []int16 // client info is sent with the first request only
+	}{
+		{[]functionCode{fcInsert, fcInsert}, []messageType{mtExecute, mtExecute}, []bool{false, true}, []int16{3, 2}},
+		{[]functionCode{fcInsert, fcNil, fcRollback}, []messageType{mtExecute, mtExecute, mtRollback}, []bool{false, true, false}, []int16{3, 2, 0}},
+	} {
+		in := new(bytes.Buffer) // database server replies
+		wr := bufio.NewWriter(in)
+		for _, fc := range d.replies {
+			parts := []testReplyPart{{pkRowsAffected, 1, 4, 0, func(wr *bufio.Writer) { wr.WriteInt32(1) }}}
+			switch fc {
+			case fcNil:
+				parts = []testReplyPart{testErrorPart(301, "unique constraint violated")}
+			case fcRollback:
+				parts = nil
+			}
+			writeTestReply(wr, fc, parts)
+		}
+		if err := wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		out := new(bytes.Buffer) // client requests
+
+		// complete array execution disabled explicitly: one row per request
+		s := &Session{
+			prm:            testSessionPrm{},
+			conn:           &sessionConn{},
+			rd:             bufio.NewReader(in),
+			wr:             bufio.NewWriter(out),
+			mh:             new(messageHeader),
+			sh:             new(segmentHeader),
+			ph:             new(partHeader),
+			resultset:      new(resultset),
+			rowsAffected:   new(rowsAffected),
+			statementID:    new(statementID),
+			stmtCtx:        newStatementContext(),
+			lastError:      new(hdbErrors),
+			clientInfo:     newClientInfo(nil),
+			connectOptions: newConnectOptions(),
+			writeLobReply:  new(writeLobReply),
+		}
+
+		s.connectOptions.set(coCompleteArrayExecution, booleanType(false))
+		s.clientInfo.setStmt(map[string]string{"APPLICATIONUSER": "user"})
+
+		_, err := s.Exec(1, prmFieldSet, args)
+		if (err != nil) != (d.replies[1] == fcNil) {
+			t.Fatalf("%v: unexpected error %v", d.replies, err)
+		}
+
+		rd := bufio.NewReader(out)
+		for i, mt := range d.messages {
+			if err := s.mh.read(rd); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.sh.read(rd); err != nil {
+				t.Fatal(err)
+			}
+			if s.sh.messageType != mt || s.sh.commit != d.commits[i] || s.sh.noOfParts != d.numParts[i] {
+				t.Fatalf("request %d: message type %s commit %t parts %d - expected %s %t %d", i, s.sh.messageType, s.sh.commit, s.sh.noOfParts, mt, d.commits[i], d.numParts[i])
+			}
+			rd.Skip(int(s.mh.varPartLength) - segmentHeaderSize)
+		}
+	}
+}
+
+func TestFetchNextScrollable(t *testing.T) {
+	for _, scrollable := range []bool{false, true} {
+		in := new(bytes.Buffer) // database server replies
+		wr := bufio.NewWriter(in)
+		writeTestReply(wr, fcFetch, []testReplyPart{testResultsetPart(0, 1)})
+		if err := wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		out := new(bytes.Buffer) // client requests
+
+		s := &Session{
+			prm:          testSessionPrm{},
+			conn:         &sessionConn{},
+			rd:           bufio.NewReader(in),
+			wr:           bufio.NewWriter(out),
+			mh:           new(messageHeader),
+			sh:           new(segmentHeader),
+			ph:           new(partHeader),
+			resultsetID:  new(resultsetID),
+			resultset:    new(resultset),
+			rowsAffected: new(rowsAffected),
+			stmtCtx:      newStatementContext(),
+			lastError:    new(hdbErrors),
+		}
+
+		if _, err := s.FetchNext(1, 0, scrollable, newResultFieldSet(1), newFieldValues()); err != nil {
+			t.Fatal(err)
+		}
+
+		// scrollable resultsets must not be closed by the server after the last packet
+		rd := bufio.NewReader(out)
+		if err := s.mh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.sh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if closed := s.sh.commandOptions&coNoResultsetCloseNeeded != 0; closed == scrollable {
+			t.Fatalf("scrollable %t: command options %s", scrollable, s.sh.commandOptions)
+		}
+	}
+}
+
+// testDropImmediatelyPrm releases closed statements immediately.
+type testDropImmediatelyPrm struct{ testSessionPrm }
+
+func (testDropImmediatelyPrm) DropStatementsImmediately() bool { return true }
+
+func TestDropStatementID(t *testing.T) {
+	for _, prm := range []sessionPrm{testSessionPrm{}, testDropImmediatelyPrm{}} {
+		immediately := prm.DropStatementsImmediately()
+
+		in := new(bytes.Buffer) // database server replies
+		wr := bufio.NewWriter(in)
+		writeTestReply(wr, fcNil, []testReplyPart{{pkRowsAffected, 1, 4, 0, func(wr *bufio.Writer) { wr.WriteInt32(0) }}})
+		if err := wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		out := new(bytes.Buffer) // client requests
+		s := &Session{
+			prm:            prm,
+			conn:           &sessionConn{},
+			rd:             bufio.NewReader(in),
+			wr:             bufio.NewWriter(out),
+			mh:             new(messageHeader),
+			sh:             new(segmentHeader),
+			ph:             new(partHeader),
+			resultset:      new(resultset),
+			rowsAffected:   new(rowsAffected),
+			statementID:    new(statementID),
+			stmtCtx:        newStatementContext(),
+			lastError:      new(hdbErrors),
+			clientInfo:     newClientInfo(nil),
+			connectOptions: newConnectOptions(),
+			writeLobReply:  new(writeLobReply),
+			numStatement:   1,
+		}
+
+		if err := s.DropStatementID(1); err != nil {
+			t.Fatal(err)
+		}
+		if s.NumStatement() != 0 {
+			t.Fatalf("immediately %t: number of statements %d - expected 0", immediately, s.NumStatement())
+		}
+
+		if !immediately {
+			if len(s.dropStatementIDs) != 1 || out.Len() != 0 {
+				t.Fatalf("statement released before the next command: pending %v - request size %d", s.dropStatementIDs, out.Len())
+			}
+			continue
+		}
+
+		if len(s.dropStatementIDs) != 0 {
+			t.Fatalf("statement not released immediately: pending %v", s.dropStatementIDs)
+		}
+		rd := bufio.NewReader(out)
+		if err := s.mh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.sh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if s.sh.messageType != mtDropStatementID {
+			t.Fatalf("message type %s - expected %s", s.sh.messageType, mtDropStatementID)
+		}
+	}
+}
+
+func TestDropPendingStatementIDs(t *testing.T) {
+	in := new(bytes.Buffer) // database server replies
+	wr := bufio.NewWriter(in)
+	for i := 0; i < maxDropStatementIDs; i++ {
+		writeTestReply(wr, fcNil, []testReplyPart{{pkRowsAffected, 1, 4, 0, func(wr *bufio.Writer) { wr.WriteInt32(0) }}})
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer) // client requests
+	s := &Session{
+		prm:            testSessionPrm{},
+		conn:           &sessionConn{},
+		rd:             bufio.NewReader(in),
+		wr:             bufio.NewWriter(out),
+		mh:             new(messageHeader),
+		sh:             new(segmentHeader),
+		ph:             new(partHeader),
+		resultset:      new(resultset),
+		rowsAffected:   new(rowsAffected),
+		statementID:    new(statementID),
+		stmtCtx:        newStatementContext(),
+		lastError:      new(hdbErrors),
+		clientInfo:     newClientInfo(nil),
+		connectOptions: newConnectOptions(),
+		writeLobReply:  new(writeLobReply),
+	}
+	numPending := maxDropStatementIDs + 2
+	for i := 0; i < numPending; i++ {
+		s.dropStatementIDs = append(s.dropStatementIDs, uint64(i))
+	}
+
+	if err := s.dropPendingStatementIDs(); err != nil {
+		t.Fatal(err)
+	}
+
+	// handles exceeding the maximum stay pending for the next command
+	if len(s.dropStatementIDs) != 2 || s.dropStatementIDs[0] != uint64(maxDropStatementIDs) {
+		t.Fatalf("pending statements %v - expected %d, %d", s.dropStatementIDs, maxDropStatementIDs, maxDropStatementIDs+1)
+	}
+	if in.Len() != 0 {
+		t.Fatalf("%d bytes of replies not read", in.Len())
+	}
+
+	rd := bufio.NewReader(out)
+	for i := 0; i < maxDropStatementIDs; i++ {
+		if err := s.mh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.sh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if s.sh.messageType != mtDropStatementID {
+			t.Fatalf("message type %s - expected %s", s.sh.messageType, mtDropStatementID)
+		}
+		rd.Skip(int(s.mh.varPartLength) - segmentHeaderSize)
+	}
+	if err := s.mh.read(rd); err != io.EOF {
+		t.Fatalf("additional request: %v", err)
+	}
+}
+
+func TestNewFetchsize(t *testing.T) {
+	for _, size := range []int{1, 1 << 16, math.MaxInt32} {
+		if fs := newFetchsize(size); int(fs) != size {
+			t.Fatalf("size %d: fetch size %d", size, fs)
+		}
+	}
+
+	if strconv.IntSize == 64 { // sizes exceeding the protocol maximum are clamped
+		exceeding := int64(math.MaxInt32)
+		exceeding++
+		if fs := newFetchsize(int(exceeding)); fs != math.MaxInt32 {
+			t.Fatalf("size %d: fetch size %d - expected %d", exceeding, fs, math.MaxInt32)
+		}
+	}
+}
+
+// testAsyncCommitPrm commits transactions asynchronously.
+type testAsyncCommitPrm struct{ testSessionPrm }
+
+func (testAsyncCommitPrm) AsyncCommit() bool { return true }
+
+func TestAsyncCommitError(t *testing.T) {
+	in := new(bytes.Buffer) // database server replies
+	wr := bufio.NewWriter(in)
+	writeTestReply(wr, fcCommit, []testReplyPart{testErrorPart(133, "transaction rolled back: retry")})
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Session{
+		prm:            testAsyncCommitPrm{},
+		conn:           &sessionConn{inTx: true},
+		rd:             bufio.NewReader(in),
+		wr:             bufio.NewWriter(new(bytes.Buffer)),
+		mh:             new(messageHeader),
+		sh:             new(segmentHeader),
+		ph:             new(partHeader),
+		resultset:      new(resultset),
+		rowsAffected:   new(rowsAffected),
+		statementID:    new(statementID),
+		stmtCtx:        newStatementContext(),
+		lastError:      new(hdbErrors),
+		clientInfo:     newClientInfo(nil),
+		connectOptions: newConnectOptions(),
+		writeLobReply:  new(writeLobReply),
+	}
+
+	if err := s.Commit(); err != nil { // reply is not awaited
+		t.Fatal(err)
+	}
+
+	err := s.ReadPendingCommitReply()
+	commitErr, ok := err.(*CommitError)
+	if !ok {
+		t.Fatalf("error %v - expected commit error", err)
+	}
+	if hdbErr, ok := commitErr.Unwrap().(*hdbErrors); !ok || hdbErr.Code() != 133 {
+		t.Fatalf("commit error %v - expected database error code %d", commitErr, 133)
+	}
+
+	// commit reply was read: the error is returned only once
+	if err := s.ReadPendingCommitReply(); err != nil {
+		t.Fatal(err)
+	}
+}
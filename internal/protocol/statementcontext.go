@@ -18,10 +18,18 @@ package protocol
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/SAP/go-hdb/internal/bufio"
 )
 
+// StatementContext contains the server execution information of the last statement.
+type StatementContext struct {
+	ServerExecutionTime time.Duration
+	ServerCPUTime       time.Duration
+	ServerMemoryUsage   int64 // bytes
+}
+
 type statementContext struct {
 	options plainOptions
 	_numArg int
@@ -33,6 +41,38 @@ func newStatementContext() *statementContext {
 	}
 }
 
+func (c *statementContext) reset() {
+	for k := range c.options {
+		delete(c.options, k)
+	}
+}
+
+func (c *statementContext) int64(k statementContextType) int64 {
+	switch v := c.options[int8(k)].(type) {
+	case bigintType:
+		return int64(v)
+	case intType:
+		return int64(v)
+	}
+	return 0
+}
+
+// sequenceInfo returns the statement sequence info or nil, if not provided by the database server.
+func (c *statementContext) sequenceInfo() []byte {
+	if v, ok := c.options[int8(scStatementSequenceInfo)].(binaryStringType); ok {
+		return v
+	}
+	return nil
+}
+
+func (c *statementContext) statementContext() StatementContext {
+	return StatementContext{
+		ServerExecutionTime: time.Duration(c.int64(scServerExecutionTime)) * time.Microsecond,
+		ServerCPUTime:       time.Duration(c.int64(scServerCPUTime)) * time.Microsecond,
+		ServerMemoryUsage:   c.int64(scServerMemoryUsage),
+	}
+}
+
 func (c *statementContext) String() string {
 	typedSc := make(map[statementContextType]interface{})
 	for k, v := range c.options {
@@ -45,6 +85,24 @@ func (c *statementContext) kind() partKind {
 	return pkStatementContext
 }
 
+func (c *statementContext) size() (int, error) {
+	return c.options.size(), nil
+}
+
+func (c *statementContext) numArg() int {
+	return len(c.options)
+}
+
+func (c *statementContext) write(wr *bufio.Writer) error {
+	c.options.write(wr)
+
+	if trace {
+		outLogger.Printf("statement context: %v", c)
+	}
+
+	return nil
+}
+
 func (c *statementContext) setNumArg(numArg int) {
 	c._numArg = numArg
 }
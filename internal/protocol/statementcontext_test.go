@@ -0,0 +1,126 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+func TestStatementContext(t *testing.T) {
+	options := plainOptions{
+		int8(scStatementSequenceInfo): binaryStringType{0x01, 0x02},
+		int8(scServerExecutionTime):   bigintType(1500),
+		int8(scServerCPUTime):         bigintType(1000),
+		int8(scServerMemoryUsage):     bigintType(4096),
+	}
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	options.write(wr)
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newStatementContext()
+	c.setNumArg(len(options))
+	if err := c.read(bufio.NewReader(buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := StatementContext{ServerExecutionTime: 1500 * time.Microsecond, ServerCPUTime: time.Millisecond, ServerMemoryUsage: 4096}
+	if sc := c.statementContext(); sc != expected {
+		t.Fatalf("statement context %v - expected %v", sc, expected)
+	}
+
+	c.reset()
+	if sc := c.statementContext(); sc != (StatementContext{}) {
+		t.Fatalf("statement context %v - expected zero value", sc)
+	}
+}
+
+func TestStatementSequenceInfo(t *testing.T) {
+	seqInfo := binaryStringType{0x01, 0x02, 0x03}
+
+	in := new(bytes.Buffer) // database server reply
+	wr := bufio.NewWriter(in)
+	options := plainOptions{int8(scStatementSequenceInfo): seqInfo}
+	writeTestReply(wr, fcSelect, []testReplyPart{{pkStatementContext, len(options), options.size(), 0, options.write}})
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer) // client request
+	s := &Session{
+		rd:           bufio.NewReader(in),
+		wr:           bufio.NewWriter(out),
+		mh:           new(messageHeader),
+		sh:           new(segmentHeader),
+		ph:           new(partHeader),
+		resultset:    new(resultset),
+		rowsAffected: new(rowsAffected),
+		stmtCtx:      newStatementContext(),
+		lastError:    new(hdbErrors),
+		clientInfo:   newClientInfo(nil),
+	}
+
+	if err := s.readReply(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s.StatementSequenceInfo(), seqInfo) {
+		t.Fatalf("sequence info %x - expected %x", s.StatementSequenceInfo(), seqInfo)
+	}
+
+	// sequence info is sent with the next statement execution only
+	s.SetStatementSequenceInfo(s.StatementSequenceInfo())
+	for i, expected := range []bool{true, false} {
+		out.Reset()
+		if err := s.writeRequest(mtExecuteDirect, false, command("select 1 from dummy")); err != nil {
+			t.Fatal(err)
+		}
+
+		rd := bufio.NewReader(out)
+		if err := s.mh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.sh.read(rd); err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for j := 0; j < int(s.sh.noOfParts); j++ {
+			if err := s.ph.read(rd); err != nil {
+				t.Fatal(err)
+			}
+			if s.ph.partKind == pkStatementContext {
+				c := newStatementContext()
+				c.setNumArg(int(s.ph.argumentCount))
+				if err := c.read(rd); err != nil {
+					t.Fatal(err)
+				}
+				found = bytes.Equal(c.sequenceInfo(), seqInfo)
+				continue
+			}
+			rd.Skip(int(s.ph.bufferLength) + padBytes(int(s.ph.bufferLength)))
+		}
+		if found != expected {
+			t.Fatalf("request %d: sequence info sent %t - expected %t", i, found, expected)
+		}
+	}
+}
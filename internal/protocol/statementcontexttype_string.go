@@ -4,9 +4,9 @@ package protocol
 
 import "strconv"
 
-const _statementContextType_name = "scStatementSequenceInfoscServerExecutionTime"
+const _statementContextType_name = "scStatementSequenceInfoscServerExecutionTimescSchemaNamescFlagSetscQueryTimeoutscClientReconnectionWaitTimeoutscServerCPUTimescServerMemoryUsage"
 
-var _statementContextType_index = [...]uint8{0, 23, 44}
+var _statementContextType_index = [...]uint8{0, 23, 44, 56, 65, 79, 110, 125, 144}
 
 func (i statementContextType) String() string {
 	i -= 1
@@ -0,0 +1,54 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+// tableLocation contains the volume ids of the tables accessed by a prepared statement (scale-out statement routing).
+type tableLocation struct {
+	_numArg   int
+	volumeIDs []int
+}
+
+func (l *tableLocation) String() string {
+	return fmt.Sprintf("volume ids %v", l.volumeIDs)
+}
+
+func (l *tableLocation) kind() partKind {
+	return pkTableLocation
+}
+
+func (l *tableLocation) setNumArg(numArg int) {
+	l._numArg = numArg
+}
+
+func (l *tableLocation) read(rd *bufio.Reader) error {
+	l.volumeIDs = make([]int, l._numArg)
+	for i := 0; i < l._numArg; i++ {
+		l.volumeIDs[i] = int(rd.ReadInt32())
+	}
+
+	if trace {
+		outLogger.Printf("table location: %v", l)
+	}
+
+	return rd.GetError()
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package protocol
 
 import (
+	"bytes"
+	"database/sql/driver"
 	"testing"
 	"time"
+
+	"github.com/SAP/go-hdb/internal/bufio"
 )
 
 type testJulianDay struct {
@@ -61,3 +65,81 @@ func TestJulianDayToTime(t *testing.T) {
 		}
 	}
 }
+
+func TestSeconddate(t *testing.T) {
+	var data = []time.Time{
+		time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC), // minimum value
+		time.Date(1, time.January, 1, 23, 59, 59, 0, time.UTC),
+		time.Date(1582, time.October, 4, 23, 59, 59, 0, time.UTC),
+		time.Date(1582, time.October, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, time.December, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2000, time.February, 29, 12, 30, 45, 0, time.UTC),
+		time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC), // maximum value
+	}
+
+	for _, d := range data {
+		if v := convertSeconddateToTime(convertTimeToSeconddate(d)); !v.Equal(d) {
+			t.Fatalf("seconddate value %s - expected %s", v, d)
+		}
+		// sub-second components are truncated
+		v := convertSeconddateToTime(convertTimeToSeconddate(d.Add(999999999 * time.Nanosecond)))
+		if !v.Equal(d) || v.Nanosecond() != 0 {
+			t.Fatalf("seconddate value %s - expected %s", v, d)
+		}
+	}
+}
+
+func TestLongdate(t *testing.T) {
+	var data = []time.Time{
+		time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC), // minimum value
+		time.Date(1970, time.January, 1, 0, 0, 0, 123456700, time.UTC),
+		time.Date(2000, time.February, 29, 12, 30, 45, 100, time.UTC),
+		time.Date(9999, time.December, 31, 23, 59, 59, 999999900, time.UTC), // maximum value
+	}
+
+	for _, d := range data {
+		if v := convertLongdateToTime(convertTimeToLongdate(d)); !v.Equal(d) {
+			t.Fatalf("longdate value %s - expected %s", v, d)
+		}
+		// nanoseconds beyond 100 nanosecond precision are truncated
+		if v := convertLongdateToTime(convertTimeToLongdate(d.Add(99 * time.Nanosecond))); !v.Equal(d) {
+			t.Fatalf("longdate value %s - expected %s", v, d)
+		}
+	}
+
+	// 1234567 units of 100 nanoseconds
+	d := time.Date(2020, time.June, 30, 10, 20, 30, 123456700, time.UTC)
+	if longdate := convertTimeToLongdate(d); longdate%10000000 != 1234567+1 {
+		t.Fatalf("longdate fraction %d - expected %d", longdate%10000000-1, 1234567)
+	}
+}
+
+func TestWriteSecondtimeNull(t *testing.T) {
+	// null value cannot be written by setting the high bit of the type code (HDB bug)
+	arg := driver.NamedValue{Value: nil}
+	size, err := fieldSize(tcSecondtime, arg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != secondtimeFieldSize {
+		t.Fatalf("size %d - expected %d", size, secondtimeFieldSize)
+	}
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	if err := writeField(wr, tcSecondtime, arg); err != nil {
+		t.Fatal(err)
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd := bufio.NewReader(buf)
+	if tc := TypeCode(rd.ReadB()); tc != tcSecondtime {
+		t.Fatalf("type code %s - expected %s", tc, tcSecondtime)
+	}
+	if _, null := readSecondtime(rd); !null {
+		t.Fatal("null value expected")
+	}
+}
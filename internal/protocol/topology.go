@@ -22,6 +22,15 @@ import (
 	"github.com/SAP/go-hdb/internal/bufio"
 )
 
+// TopologyHost represents a host (index server) of a database system as provided by the topology information.
+type TopologyHost struct {
+	HostName         string
+	Port             int
+	VolumeID         int
+	IsMaster         bool
+	IsCurrentSession bool
+}
+
 type topologyInformation struct {
 	mlo     multiLineOptions
 	_numArg int
@@ -61,7 +70,54 @@ func (o *topologyInformation) setNumArg(numArg int) {
 	o._numArg = numArg
 }
 
+// hosts returns the hosts of the topology information.
+func (o *topologyInformation) hosts() []TopologyHost {
+	hosts := make([]TopologyHost, len(o.mlo))
+	for i, po := range o.mlo {
+		h := &hosts[i]
+		if v, ok := po[int8(toHostName)].(stringType); ok {
+			h.HostName = string(v)
+		}
+		if v, ok := po[int8(toHostPortnumber)].(intType); ok {
+			h.Port = int(v)
+		}
+		if v, ok := po[int8(toVolumeID)].(intType); ok {
+			h.VolumeID = int(v)
+		}
+		if v, ok := po[int8(toIsMaster)].(booleanType); ok {
+			h.IsMaster = bool(v)
+		}
+		if v, ok := po[int8(toIsCurrentSession)].(booleanType); ok {
+			h.IsCurrentSession = bool(v)
+		}
+	}
+	return hosts
+}
+
+// routingHosts returns the hosts owning the volumes of volumeIDs in volume id order, each host only once.
+func routingHosts(hosts []TopologyHost, volumeIDs []int) []TopologyHost {
+	var r []TopologyHost
+	for _, volumeID := range volumeIDs {
+		for _, h := range hosts {
+			if h.VolumeID == volumeID && !containsHost(r, h) {
+				r = append(r, h)
+			}
+		}
+	}
+	return r
+}
+
+func containsHost(hosts []TopologyHost, host TopologyHost) bool {
+	for _, h := range hosts {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
 func (o *topologyInformation) read(rd *bufio.Reader) error {
+	o.mlo = o.mlo[:0] // topology information is complete: replace previous one
 	o.mlo.read(rd, o._numArg)
 
 	if trace {
@@ -0,0 +1,136 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/bufio"
+)
+
+func TestTopologyHosts(t *testing.T) {
+	o := &topologyInformation{
+		mlo: multiLineOptions{
+			plainOptions{int8(toHostName): stringType("host1"), int8(toHostPortnumber): intType(30015), int8(toVolumeID): intType(2), int8(toIsMaster): booleanType(true), int8(toIsCurrentSession): booleanType(true)},
+			plainOptions{int8(toHostName): stringType("host2"), int8(toHostPortnumber): intType(30015), int8(toVolumeID): intType(3)},
+		},
+	}
+
+	hosts := o.hosts()
+	expected := []TopologyHost{
+		{HostName: "host1", Port: 30015, VolumeID: 2, IsMaster: true, IsCurrentSession: true},
+		{HostName: "host2", Port: 30015, VolumeID: 3},
+	}
+	if !reflect.DeepEqual(hosts, expected) {
+		t.Fatalf("hosts %v - expected %v", hosts, expected)
+	}
+}
+
+func TestReadTableLocation(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	for _, id := range []int32{3, 2} {
+		wr.WriteInt32(id)
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	l := new(tableLocation)
+	l.setNumArg(2)
+	if err := l.read(bufio.NewReader(buf)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(l.volumeIDs, []int{3, 2}) {
+		t.Fatalf("volume ids %v - expected %v", l.volumeIDs, []int{3, 2})
+	}
+}
+
+// testRoutingPrm enables statement routing.
+type testRoutingPrm struct{ testSessionPrm }
+
+func (testRoutingPrm) StatementRouting() bool { return true }
+
+func TestClientDistributionMode(t *testing.T) {
+	if mode := clientDistributionMode(testSessionPrm{}); mode != cdmOff {
+		t.Fatalf("client distribution mode %d - expected %d", mode, cdmOff)
+	}
+	if mode := clientDistributionMode(testRoutingPrm{}); mode != cdmStatement {
+		t.Fatalf("client distribution mode %d - expected %d", mode, cdmStatement)
+	}
+}
+
+func TestStatementRouting(t *testing.T) {
+	volumeIDs := []int32{4, 2, 4}
+
+	in := new(bytes.Buffer) // database server replies
+	wr := bufio.NewWriter(in)
+	writeTestReply(wr, fcSelect, []testReplyPart{
+		{pkStatementID, 1, statementIDSize, 0, func(wr *bufio.Writer) { wr.WriteUint64(1) }},
+		{pkTableLocation, len(volumeIDs), 4 * len(volumeIDs), 0, func(wr *bufio.Writer) {
+			for _, id := range volumeIDs {
+				wr.WriteInt32(id)
+			}
+		}},
+	})
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Session{
+		prm:           testRoutingPrm{},
+		conn:          &sessionConn{},
+		rd:            bufio.NewReader(in),
+		wr:            bufio.NewWriter(new(bytes.Buffer)),
+		mh:            new(messageHeader),
+		sh:            new(segmentHeader),
+		ph:            new(partHeader),
+		resultset:     new(resultset),
+		rowsAffected:  new(rowsAffected),
+		statementID:   new(statementID),
+		stmtCtx:       newStatementContext(),
+		lastError:     new(hdbErrors),
+		tableLocation: new(tableLocation),
+		topologyInformation: &topologyInformation{
+			mlo: multiLineOptions{
+				plainOptions{int8(toHostName): stringType("host1"), int8(toHostPortnumber): intType(30015), int8(toVolumeID): intType(2), int8(toIsMaster): booleanType(true)},
+				plainOptions{int8(toHostName): stringType("host2"), int8(toHostPortnumber): intType(30015), int8(toVolumeID): intType(3)},
+				plainOptions{int8(toHostName): stringType("host3"), int8(toHostPortnumber): intType(30015), int8(toVolumeID): intType(4)},
+			},
+		},
+	}
+
+	hosts, err := s.StatementRouting("select * from t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TopologyHost{
+		{HostName: "host3", Port: 30015, VolumeID: 4},
+		{HostName: "host1", Port: 30015, VolumeID: 2, IsMaster: true},
+	}
+	if !reflect.DeepEqual(hosts, expected) {
+		t.Fatalf("routing hosts %v - expected %v", hosts, expected)
+	}
+
+	// routing not enabled
+	s.prm = testSessionPrm{}
+	if _, err := s.StatementRouting("select * from t"); err == nil {
+		t.Fatal("statement routing error expected")
+	}
+}
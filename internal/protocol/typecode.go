@@ -54,7 +54,7 @@ const (
 	//tcTimestampltz      TypeCode = 20 // reserved: do not use
 	//tcInvervalym        TypeCode = 21 // reserved: do not use
 	//tcInvervalds        TypeCode = 22 // reserved: do not use
-	//tcRowid             TypeCode = 23 // reserved: do not use
+	tcRowid TypeCode = 23 // $rowid$ pseudo column
 	//tcUrowid            TypeCode = 24 // reserved: do not use
 	tcClob     TypeCode = 25
 	tcNclob    TypeCode = 26
@@ -64,12 +64,12 @@ const (
 	tcNstring  TypeCode = 30
 	tcBlocator TypeCode = 31
 	tcNlocator TypeCode = 32
-	tcBstring  TypeCode = 33
+	tcBstring  TypeCode = 33 // binary string (e.g. ABAP RAWSTRING)
 	//tcDecimaldigitarray TypeCode = 34 // reserved: do not use
 	tcVarchar2   TypeCode = 35
 	tcVarchar3   TypeCode = 36
 	tcNvarchar3  TypeCode = 37
-	tcVarbinary3 TypeCode = 38
+	tcVarbinary3 TypeCode = 38 // binary (e.g. ABAP RAW)
 	//tcVargroup          TypeCode = 39 // reserved: do not use
 	//tcTinyintnotnull    TypeCode = 40 // reserved: do not use
 	//tcSmallintnotnull   TypeCode = 41 // reserved: do not use
@@ -84,7 +84,7 @@ const (
 	tcArray     TypeCode = 50
 	tcText      TypeCode = 51
 	tcShorttext TypeCode = 52
-	//tcFixedString       TypeCode = 53 // reserved: do not use
+	tcBintext   TypeCode = 53
 	//tcFixedpointdecimal TypeCode = 54 // reserved: do not use
 	tcAlphanum TypeCode = 55
 	//tcTlocator    TypeCode = 56 // reserved: do not use
@@ -99,27 +99,58 @@ const (
 	//tcNclobdisk   TypeCode = 73 // reserved: do not use
 	//tcGeometry    TypeCode = 74 // reserved: do not use
 	//tcPoint       TypeCode = 75 // reserved: do not use
-	//tcFixed16     TypeCode = 76 // reserved: do not use
+	tcFixed16 TypeCode = 76
 	//tcBlobhybrid  TypeCode = 77 // reserved: do not use
 	//tcClobhybrid  TypeCode = 78 // reserved: do not use
 	//tcNclobhybrid TypeCode = 79 // reserved: do not use
 	//tcPointz      TypeCode = 80 // reserved: do not use
+	tcFixed8  TypeCode = 81
+	tcFixed12 TypeCode = 82
 )
 
 func (k TypeCode) isLob() bool {
-	return k == tcClob || k == tcNclob || k == tcBlob
+	return k == tcClob || k == tcNclob || k == tcBlob || k == tcText || k == tcBintext
 }
 
 func (k TypeCode) isCharBased() bool {
-	return k == tcNvarchar || k == tcNstring || k == tcNclob
+	return k == tcNvarchar || k == tcNstring || k == tcNclob || k == tcText
+}
+
+func (k TypeCode) isString() bool {
+	return k == tcChar || k == tcVarchar || k == tcString || k == tcNchar || k == tcNvarchar || k == tcNstring
+}
+
+func (k TypeCode) isNonUnicodeChar() bool {
+	return k == tcChar || k == tcVarchar || k == tcString
 }
 
 func (k TypeCode) isVariableLength() bool {
-	return k == tcChar || k == tcNchar || k == tcVarchar || k == tcNvarchar || k == tcBinary || k == tcVarbinary || k == tcShorttext || k == tcAlphanum
+	return k == tcChar || k == tcNchar || k == tcVarchar || k == tcNvarchar || k == tcBinary || k == tcVarbinary || k == tcRowid || k == tcBstring || k == tcVarbinary3 || k == tcShorttext || k == tcAlphanum
 }
 
 func (k TypeCode) isDecimalType() bool {
-	return k == tcSmalldecimal || k == tcDecimal
+	return k == tcSmalldecimal || k == tcDecimal || k.IsFixed()
+}
+
+// IsFixed returns true if the type code is a fixed size decimal type (FIXED8, FIXED12, FIXED16),
+// which is reported for DECIMAL(p,s) fields with data format version 8 and above.
+func (k TypeCode) IsFixed() bool {
+	return k == tcFixed8 || k == tcFixed12 || k == tcFixed16
+}
+
+// HasDatePart returns true if the type code is a time type including a date part.
+func (k TypeCode) HasDatePart() bool {
+	return k == tcDate || k == tcTimestamp || k == tcLongdate || k == tcSeconddate || k == tcDaydate
+}
+
+// HasTimePart returns true if the type code is a time type including a time part.
+func (k TypeCode) HasTimePart() bool {
+	return k == tcTime || k == tcTimestamp || k == tcLongdate || k == tcSeconddate || k == tcSecondtime
+}
+
+// SecondPrecision returns true if the type code is a time type of second precision (SECONDDATE, SECONDTIME).
+func (k TypeCode) SecondPrecision() bool {
+	return k == tcSeconddate || k == tcSecondtime
 }
 
 // DataType converts a type code into one of the supported data types by the driver.
@@ -141,19 +172,26 @@ func (k TypeCode) DataType() DataType {
 		return DtDouble
 	case tcDate, tcTime, tcTimestamp, tcLongdate, tcSeconddate, tcDaydate, tcSecondtime:
 		return DtTime
-	case tcDecimal:
+	case tcDecimal, tcFixed8, tcFixed12, tcFixed16:
 		return DtDecimal
 	case tcChar, tcVarchar, tcString, tcNchar, tcNvarchar, tcNstring:
 		return DtString
-	case tcBinary, tcVarbinary:
+	case tcBinary, tcVarbinary, tcRowid, tcBstring, tcVarbinary3:
 		return DtBytes
-	case tcBlob, tcClob, tcNclob:
+	case tcBlob, tcClob, tcNclob, tcText, tcBintext:
 		return DtLob
+	case tcArray:
+		return DtArray
+	case tcBoolean:
+		return DtBoolean
 	}
 }
 
 // TypeName returns the database type name.
 // see https://golang.org/pkg/database/sql/driver/#RowsColumnTypeDatabaseTypeName
 func (k TypeCode) TypeName() string {
+	if k.IsFixed() {
+		return "DECIMAL"
+	}
 	return strings.ToUpper(k.String()[2:])
 }
@@ -72,6 +72,13 @@ func FullRune(p []byte) bool {
 	return !short
 }
 
+// HighSurrogate reports whether p begins with the CESU-8 encoding of a high (leading) UTF-16 surrogate,
+// which is the first half of the CESU-8 encoding of a rune greater than U+FFFF.
+func HighSurrogate(p []byte) bool {
+	r, n, short := decodeRune(p)
+	return !short && n == CESUMax/2 && surrogateMin <= r && r < surrogateLowMin
+}
+
 // DecodeRune unpacks the first CESU-8 encoding in p and returns the rune and its width in bytes.
 func DecodeRune(p []byte) (rune, int) {
 	high, n1, _ := decodeRune(p)
@@ -110,8 +117,9 @@ func RuneLen(r rune) int {
 
 // Code points in the surrogate range are not valid for UTF-8.
 const (
-	surrogateMin = 0xD800
-	surrogateMax = 0xDFFF
+	surrogateMin    = 0xD800
+	surrogateLowMin = 0xDC00 // (*)
+	surrogateMax    = 0xDFFF
 )
 
 const (
@@ -104,3 +104,24 @@ func TestString(t *testing.T) {
 		}
 	}
 }
+
+func TestHighSurrogate(t *testing.T) {
+	var data = []struct {
+		b    []byte
+		high bool
+	}{
+		{[]byte{0xed, 0xa0, 0x81}, true},
+		{[]byte{0xed, 0xa0, 0x81, 0xed, 0xb0}, true},
+		{[]byte{0xed, 0xb0, 0x80}, false}, // low surrogate
+		{[]byte{0xed, 0xa0}, false},       // incomplete
+		{[]byte{0xe2, 0x82, 0xac}, false}, // euro sign
+		{[]byte{0x45}, false},
+		{nil, false},
+	}
+
+	for i, d := range data {
+		if high := HighSurrogate(d.b); high != d.high {
+			t.Fatalf("%d: high surrogate %t - expected %t", i, high, d.high)
+		}
+	}
+}
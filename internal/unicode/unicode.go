@@ -109,3 +109,31 @@ func (t *cesu8ToUtf8Transformer) Transform(dst, src []byte, atEOF bool) (nDst, n
 	}
 	return j, i, nil
 }
+
+// Cesu8ToUtf8 converts the CESU-8 encoded surrogate pairs of b to UTF-8 (inplace) and returns the converted slice.
+// If b does not contain surrogate pairs or is not valid CESU-8, b is returned unchanged.
+func Cesu8ToUtf8(b []byte) []byte {
+	surrogates := false
+	for i := 0; i < len(b); {
+		if b[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		if !cesu8.FullRune(b[i:]) {
+			return b
+		}
+		r, n := cesu8.DecodeRune(b[i:])
+		if r == utf8.RuneError {
+			return b
+		}
+		if n == cesu8.CESUMax {
+			surrogates = true
+		}
+		i += n
+	}
+	if !surrogates {
+		return b
+	}
+	n, _, _ := Cesu8ToUtf8Transformer.Transform(b, b, true) // valid CESU-8: inplace transformation does not fail
+	return b[:n]
+}
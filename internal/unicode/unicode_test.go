@@ -0,0 +1,66 @@
+/*
+Copyright 2018 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unicode
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/text/transform"
+)
+
+const testAstral = "𝕳𝖆𝖓𝖆" // supplementary plane characters
+
+func TestTransformAstral(t *testing.T) {
+	b, _, err := transform.Bytes(Utf8ToCesu8Transformer, []byte(testAstral))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 4*6 { // CESU-8: 6 bytes per supplementary character
+		t.Fatalf("cesu-8 size %d - expected %d", len(b), 4*6)
+	}
+	b, _, err = transform.Bytes(Cesu8ToUtf8Transformer, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != testAstral {
+		t.Fatalf("utf-8 %q - expected %q", b, testAstral)
+	}
+}
+
+func TestCesu8ToUtf8(t *testing.T) {
+	cesu8Astral, _, err := transform.Bytes(Utf8ToCesu8Transformer, []byte(testAstral))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		in, out []byte
+	}{
+		{[]byte("Hana"), []byte("Hana")},
+		{cesu8Astral, []byte(testAstral)},
+		{[]byte(testAstral), []byte(testAstral)},                       // UTF-8: unchanged
+		{[]byte{0xed, 0xa0, 0xb5, 'a'}, []byte{0xed, 0xa0, 0xb5, 'a'}}, // invalid surrogate pair: unchanged
+		{[]byte{0x80, 0x80}, []byte{0x80, 0x80}},                       // invalid: unchanged
+	}
+	for i, test := range tests {
+		in := append([]byte{}, test.in...)
+		if out := Cesu8ToUtf8(in); !bytes.Equal(out, test.out) {
+			t.Fatalf("%d: %x - expected %x", i, out, test.out)
+		}
+	}
+}